@@ -0,0 +1,163 @@
+package checks
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+)
+
+func init() {
+	dashboard.RegisterRule(dashboard.Rule{Name: "node checks out of thresholds", Node: nodeChecksAlerts})
+	dashboard.RegisterRule(dashboard.Rule{Name: "service checks out of thresholds", Service: serviceChecksAlerts})
+}
+
+// Evaluate sets the thresholds, threshold provider and error flag of the
+// checks, from the thresholds of their types in database.
+func Evaluate(l []tables.Check) error {
+	if len(l) == 0 {
+		return nil
+	}
+	thresholds := make([]tables.CheckThreshold, 0)
+	if err := db.DB().Where("chk_type IN ?", Types(l)).Find(&thresholds).Error; err != nil {
+		return fmt.Errorf("select thresholds: %w", err)
+	}
+	tags, err := checkTags(l)
+	if err != nil {
+		return err
+	}
+	for i := range l {
+		Eval(&l[i], thresholds, tags[l[i].NodeID+"/"+l[i].SvcID])
+	}
+	return nil
+}
+
+// Reevaluate evaluates the checks of the types against their current
+// thresholds, after a threshold change, and returns the checks whose
+// evaluation changed.
+func Reevaluate(types ...string) ([]tables.Check, error) {
+	l := make([]tables.Check, 0)
+	if err := db.DB().Where("chk_type IN ?", types).Find(&l).Error; err != nil {
+		return nil, fmt.Errorf("select checks: %w", err)
+	}
+	before := make([]tables.Check, len(l))
+	copy(before, l)
+	if err := Evaluate(l); err != nil {
+		return nil, err
+	}
+	changed := make([]tables.Check, 0)
+	for i, c := range l {
+		b := before[i]
+		if c.Low == b.Low && c.High == b.High && c.Provider == b.Provider && c.Err == b.Err {
+			continue
+		}
+		err := db.DB().Model(&tables.Check{}).
+			Where("id = ?", c.ID).
+			Updates(map[string]interface{}{
+				"chk_low":                c.Low,
+				"chk_high":               c.High,
+				"chk_threshold_provider": c.Provider,
+				"chk_err":                c.Err,
+			}).Error
+		if err != nil {
+			return changed, fmt.Errorf("update check %d: %w", c.ID, err)
+		}
+		events.Publish(events.Update, "checks_live", c.ID, "chk_low", "chk_high", "chk_threshold_provider", "chk_err")
+		changed = append(changed, c)
+	}
+	return changed, nil
+}
+
+// checkTags returns the tag ids of the nodes and services of the checks,
+// indexed by node_id/svc_id. The checks of a service on a node apply the
+// thresholds of the tags of both.
+func checkTags(l []tables.Check) (map[string][]string, error) {
+	nodeIDs := make([]string, 0)
+	svcIDs := make([]string, 0)
+	for _, c := range l {
+		nodeIDs = append(nodeIDs, c.NodeID)
+		if c.SvcID != "" {
+			svcIDs = append(svcIDs, c.SvcID)
+		}
+	}
+	nodeTags := make([]tables.NodeTag, 0)
+	if err := db.DB().Where("node_id IN ?", nodeIDs).Find(&nodeTags).Error; err != nil {
+		return nil, fmt.Errorf("select node tags: %w", err)
+	}
+	svcTags := make([]tables.ServiceTag, 0)
+	if len(svcIDs) > 0 {
+		if err := db.DB().Where("svc_id IN ?", svcIDs).Find(&svcTags).Error; err != nil {
+			return nil, fmt.Errorf("select service tags: %w", err)
+		}
+	}
+	m := make(map[string][]string)
+	for _, c := range l {
+		k := c.NodeID + "/" + c.SvcID
+		if _, ok := m[k]; ok {
+			continue
+		}
+		tagIDs := make([]string, 0)
+		for _, t := range nodeTags {
+			if t.NodeID == c.NodeID {
+				tagIDs = append(tagIDs, t.TagID)
+			}
+		}
+		for _, t := range svcTags {
+			if c.SvcID != "" && t.SvcID == c.SvcID {
+				tagIDs = append(tagIDs, t.TagID)
+			}
+		}
+		m[k] = tagIDs
+	}
+	return m, nil
+}
+
+// errAlerts returns the alert raised by the checks in error, if any.
+func errAlerts(l []tables.Check) []dashboard.Alert {
+	if len(l) == 0 {
+		return nil
+	}
+	names := make([]string, len(l))
+	for i, c := range l {
+		names[i] = Describe(c)
+	}
+	return []dashboard.Alert{{
+		Severity: dashboard.SeverityWarning,
+		Fmt:      "%(n)s checks out of thresholds: %(checks)s",
+		Dict: map[string]interface{}{
+			"n":      fmt.Sprint(len(l)),
+			"checks": strings.Join(names, ", "),
+		},
+	}}
+}
+
+func nodeChecksAlerts(node tables.Node, now time.Time) []dashboard.Alert {
+	l := make([]tables.Check, 0)
+	err := db.DB().
+		Where("node_id = ? AND svc_id = ? AND chk_err = 1", node.NodeID, "").
+		Order("chk_type, chk_instance").
+		Find(&l).Error
+	if err != nil {
+		log.Printf("select node %s checks in error: %s", node.NodeID, err)
+		return nil
+	}
+	return errAlerts(l)
+}
+
+func serviceChecksAlerts(svc tables.Service, now time.Time) []dashboard.Alert {
+	l := make([]tables.Check, 0)
+	err := db.DB().
+		Where("svc_id = ? AND chk_err = 1", svc.SvcID).
+		Order("chk_type, chk_instance").
+		Find(&l).Error
+	if err != nil {
+		log.Printf("select service %s checks in error: %s", svc.SvcID, err)
+		return nil
+	}
+	return errAlerts(l)
+}
@@ -0,0 +1,208 @@
+// Package checks evaluates the check values pushed by the nodes against
+// the thresholds.
+//
+// A check is a value of a type, for example fs_u for the usage percent of
+// a filesystem, measured on an instance, for example the /var mount point.
+// Its thresholds are the [low, high] range of the normal values, selected
+// among the thresholds of its type whose instance pattern matches:
+//
+//   - the thresholds of the service on the node
+//   - the thresholds of the service
+//   - the thresholds of the node
+//   - the thresholds of a tag of the node or service
+//   - the default thresholds
+//
+// Among the thresholds of the same scope, the threshold of the exact
+// instance is preferred, then the longest instance pattern.
+package checks
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+type (
+	// Scope is the scope of a threshold, by decreasing precedence.
+	Scope int
+)
+
+const (
+	ScopeNone Scope = iota
+	ScopeDefault
+	ScopeTag
+	ScopeNode
+	ScopeService
+	ScopeNodeService
+)
+
+// String returns the name of the scope, used as the check
+// chk_threshold_provider.
+func (t Scope) String() string {
+	switch t {
+	case ScopeDefault:
+		return "default"
+	case ScopeTag:
+		return "tag"
+	case ScopeNode:
+		return "node"
+	case ScopeService:
+		return "service"
+	case ScopeNodeService:
+		return "node service"
+	default:
+		return ""
+	}
+}
+
+// ThresholdScope returns the scope of the threshold.
+func ThresholdScope(t tables.CheckThreshold) Scope {
+	switch {
+	case t.NodeID != "" && t.SvcID != "":
+		return ScopeNodeService
+	case t.SvcID != "":
+		return ScopeService
+	case t.NodeID != "":
+		return ScopeNode
+	case t.TagID != "":
+		return ScopeTag
+	default:
+		return ScopeDefault
+	}
+}
+
+// Like returns true if s matches the sql LIKE pattern, where % matches any
+// sequence of characters and _ any character. The match is case
+// sensitive.
+func Like(pattern, s string) bool {
+	p := []rune(pattern)
+	r := []rune(s)
+	// star is the index in p after the last %, and mark the index in r
+	// it was tried at.
+	var i, j int
+	star, mark := -1, 0
+	for j < len(r) {
+		switch {
+		case i < len(p) && (p[i] == '_' || p[i] == r[j]) && p[i] != '%':
+			i++
+			j++
+		case i < len(p) && p[i] == '%':
+			star = i + 1
+			mark = j
+			i++
+		case star >= 0:
+			i = star
+			mark++
+			j = mark
+		default:
+			return false
+		}
+	}
+	for i < len(p) && p[i] == '%' {
+		i++
+	}
+	return i == len(p)
+}
+
+// Applies returns true if the threshold applies to the check, on a node or
+// service with the tags.
+func Applies(t tables.CheckThreshold, c tables.Check, tagIDs []string) bool {
+	if t.Type != c.Type {
+		return false
+	}
+	if t.NodeID != "" && t.NodeID != c.NodeID {
+		return false
+	}
+	if t.SvcID != "" && t.SvcID != c.SvcID {
+		return false
+	}
+	if t.TagID != "" && !hasTag(tagIDs, t.TagID) {
+		return false
+	}
+	pattern := t.Instance
+	if pattern == "" {
+		pattern = "%"
+	}
+	return Like(pattern, c.Instance)
+}
+
+func hasTag(tagIDs []string, tagID string) bool {
+	for _, s := range tagIDs {
+		if s == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the threshold of the check with the highest precedence,
+// and false if no threshold applies.
+func Select(thresholds []tables.CheckThreshold, c tables.Check, tagIDs []string) (tables.CheckThreshold, bool) {
+	l := make([]tables.CheckThreshold, 0)
+	for _, t := range thresholds {
+		if Applies(t, c, tagIDs) {
+			l = append(l, t)
+		}
+	}
+	if len(l) == 0 {
+		return tables.CheckThreshold{}, false
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		si, sj := ThresholdScope(l[i]), ThresholdScope(l[j])
+		if si != sj {
+			return si > sj
+		}
+		ei, ej := l[i].Instance == c.Instance, l[j].Instance == c.Instance
+		if ei != ej {
+			return ei
+		}
+		if len(l[i].Instance) != len(l[j].Instance) {
+			return len(l[i].Instance) > len(l[j].Instance)
+		}
+		return l[i].ID < l[j].ID
+	})
+	return l[0], true
+}
+
+// Eval sets the thresholds, threshold provider and error flag of the check.
+// The check is not in error if no threshold applies.
+func Eval(c *tables.Check, thresholds []tables.CheckThreshold, tagIDs []string) {
+	t, ok := Select(thresholds, *c, tagIDs)
+	if !ok {
+		c.Low, c.High, c.Provider, c.Err = 0, 0, "", 0
+		return
+	}
+	c.Low = t.Low
+	c.High = t.High
+	c.Provider = ThresholdScope(t).String()
+	if c.Value < t.Low || c.Value > t.High {
+		c.Err = 1
+	} else {
+		c.Err = 0
+	}
+}
+
+// Types returns the distinct check types of the checks, sorted.
+func Types(l []tables.Check) []string {
+	m := make(map[string]bool)
+	for _, c := range l {
+		m[c.Type] = true
+	}
+	types := make([]string, 0, len(m))
+	for s := range m {
+		types = append(types, s)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Describe returns the type and instance of the check, as displayed in the
+// dashboard alerts.
+func Describe(c tables.Check) string {
+	l := []string{c.Type}
+	if c.Instance != "" {
+		l = append(l, c.Instance)
+	}
+	return strings.Join(l, ":")
+}
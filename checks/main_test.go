@@ -0,0 +1,147 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLike(t *testing.T) {
+	tests := map[string]struct {
+		pattern, s string
+		expected   bool
+	}{
+		"match all":          {pattern: "%", s: "/var", expected: true},
+		"match all empty":    {pattern: "%", s: "", expected: true},
+		"exact":              {pattern: "/var", s: "/var", expected: true},
+		"exact mismatch":     {pattern: "/var", s: "/var/log", expected: false},
+		"prefix":             {pattern: "/var%", s: "/var/log", expected: true},
+		"suffix":             {pattern: "%log", s: "/var/log", expected: true},
+		"inner":              {pattern: "/%/log", s: "/var/tmp/log", expected: true},
+		"inner mismatch":     {pattern: "/%/log", s: "/var/tmp/logs", expected: false},
+		"any char":           {pattern: "sd_", s: "sda", expected: true},
+		"any char too short": {pattern: "sd_", s: "sd", expected: false},
+		"case sensitive":     {pattern: "/VAR", s: "/var", expected: false},
+		"backtrack":          {pattern: "%a%b", s: "xaxbxb", expected: true},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, Like(test.pattern, test.s))
+	}
+}
+
+func TestSelect(t *testing.T) {
+	thresholds := []tables.CheckThreshold{
+		{ID: 1, Type: "fs_u", Instance: "%", High: 90},
+		{ID: 2, Type: "fs_u", Instance: "/var%", High: 80},
+		{ID: 3, Type: "fs_u", Instance: "/var", High: 70},
+		{ID: 4, Type: "fs_u", Instance: "%", TagID: "tag1", High: 95},
+		{ID: 5, Type: "fs_u", Instance: "%", NodeID: "node1", High: 97},
+		{ID: 6, Type: "fs_u", Instance: "%", SvcID: "svc1", High: 98},
+		{ID: 7, Type: "fs_u", Instance: "%", NodeID: "node1", SvcID: "svc1", High: 99},
+		{ID: 8, Type: "mpath", Instance: "%", Low: 2, High: 4},
+	}
+	tests := map[string]struct {
+		check    tables.Check
+		tagIDs   []string
+		expected uint
+		scope    Scope
+	}{
+		"default": {
+			check:    tables.Check{Type: "fs_u", Instance: "/home", NodeID: "node2"},
+			expected: 1,
+			scope:    ScopeDefault,
+		},
+		"exact instance": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", NodeID: "node2"},
+			expected: 3,
+			scope:    ScopeDefault,
+		},
+		"longest pattern": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var/log", NodeID: "node2"},
+			expected: 2,
+			scope:    ScopeDefault,
+		},
+		"tag": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", NodeID: "node2"},
+			tagIDs:   []string{"tag1"},
+			expected: 4,
+			scope:    ScopeTag,
+		},
+		"node": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", NodeID: "node1"},
+			tagIDs:   []string{"tag1"},
+			expected: 5,
+			scope:    ScopeNode,
+		},
+		"service": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", NodeID: "node2", SvcID: "svc1"},
+			expected: 6,
+			scope:    ScopeService,
+		},
+		"service on node": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", NodeID: "node1", SvcID: "svc1"},
+			expected: 7,
+			scope:    ScopeNodeService,
+		},
+		"other type": {
+			check:    tables.Check{Type: "mpath", Instance: "3600", NodeID: "node1"},
+			expected: 8,
+			scope:    ScopeDefault,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		threshold, ok := Select(thresholds, test.check, test.tagIDs)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, threshold.ID)
+		assert.Equal(t, test.scope, ThresholdScope(threshold))
+	}
+	_, ok := Select(thresholds, tables.Check{Type: "unknown"}, nil)
+	assert.False(t, ok)
+}
+
+func TestEval(t *testing.T) {
+	thresholds := []tables.CheckThreshold{
+		{ID: 1, Type: "fs_u", Instance: "%", High: 90},
+		{ID: 2, Type: "mpath", Instance: "%", NodeID: "node1", Low: 2, High: 4},
+	}
+	tests := map[string]struct {
+		check    tables.Check
+		err      int
+		provider string
+	}{
+		"in range": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", Value: 90},
+			provider: "default",
+		},
+		"above high": {
+			check:    tables.Check{Type: "fs_u", Instance: "/var", Value: 91},
+			err:      1,
+			provider: "default",
+		},
+		"below low": {
+			check:    tables.Check{Type: "mpath", Instance: "3600", NodeID: "node1", Value: 1},
+			err:      1,
+			provider: "node",
+		},
+		"no threshold": {
+			check: tables.Check{Type: "mpath", Instance: "3600", NodeID: "node2", Value: 1, Err: 1, Provider: "node"},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		c := test.check
+		Eval(&c, thresholds, nil)
+		assert.Equal(t, test.err, c.Err)
+		assert.Equal(t, test.provider, c.Provider)
+	}
+}
+
+func TestTypesAndDescribe(t *testing.T) {
+	l := []tables.Check{{Type: "mpath"}, {Type: "fs_u"}, {Type: "mpath"}}
+	assert.Equal(t, []string{"fs_u", "mpath"}, Types(l))
+	assert.Equal(t, "fs_u:/var", Describe(tables.Check{Type: "fs_u", Instance: "/var"}))
+	assert.Equal(t, "uptime", Describe(tables.Check{Type: "uptime"}))
+}
@@ -0,0 +1,213 @@
+// Package compliance computes the compliance rules of the nodes.
+//
+// The rules of a node are the variables of the rulesets and the modules of
+// the modulesets attached to the node, directly or via one of the node
+// tags. The rules of a service run on the node also include the rulesets
+// and modulesets attached to the service, directly or via one of the
+// service tags.
+//
+// A variable defined by several rulesets takes the value of the ruleset
+// attached with the highest precedence, from lowest to highest:
+//
+//	SourceNodeTag     attached to a tag of the node
+//	SourceNode        attached to the node
+//	SourceServiceTag  attached to a tag of the service
+//	SourceService     attached to the service
+//
+// The nodes run the modules checks, and the fixes of the autofix modules
+// found non compliant, and report the results.
+package compliance
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+const (
+	SourceNodeTag Source = iota
+	SourceNode
+	SourceServiceTag
+	SourceService
+)
+
+const (
+	// StatusOK is the status of a module found compliant.
+	StatusOK = 0
+
+	// StatusNotOK is the status of a module found non compliant.
+	StatusNotOK = 1
+
+	// StatusNotApplicable is the status of a module not applicable to
+	// the node.
+	StatusNotApplicable = 2
+
+	ActionCheck = "check"
+	ActionFix   = "fix"
+)
+
+type (
+	// Source is the way a ruleset or moduleset is attached to the node or
+	// service the rules are computed for.
+	Source int
+
+	// Ruleset is a ruleset attached to a node or service, with its
+	// variables.
+	Ruleset struct {
+		Ruleset   tables.CompRuleset
+		Source    Source
+		Variables []tables.CompRulesetVariable
+	}
+
+	// Moduleset is a moduleset attached to a node or service, with its
+	// modules.
+	Moduleset struct {
+		Moduleset tables.CompModuleset
+		Source    Source
+		Modules   []tables.CompModulesetModule
+	}
+
+	// Variable is a compliance variable, and the ruleset defining it.
+	Variable struct {
+		Name    string `json:"var_name"`
+		Class   string `json:"var_class"`
+		Value   string `json:"var_value"`
+		Ruleset string `json:"ruleset_name"`
+	}
+
+	// Module is a compliance module, and the modulesets including it. A
+	// module is autofix if one of the modulesets says so.
+	Module struct {
+		Name       string   `json:"modset_mod_name"`
+		Autofix    bool     `json:"autofix"`
+		Modulesets []string `json:"modsets"`
+	}
+
+	// Rules are the compliance rules of a node, or of a service on a node.
+	Rules struct {
+		NodeID     string     `json:"node_id"`
+		SvcID      string     `json:"svc_id,omitempty"`
+		Rulesets   []string   `json:"rulesets"`
+		Variables  []Variable `json:"variables"`
+		Modulesets []string   `json:"modulesets"`
+		Modules    []Module   `json:"modules"`
+	}
+)
+
+var (
+	sourceNames = []string{"node tag", "node", "service tag", "service"}
+)
+
+func (t Source) String() string {
+	if t < 0 || int(t) >= len(sourceNames) {
+		return fmt.Sprint(int(t))
+	}
+	return sourceNames[t]
+}
+
+// ValidStatus returns true if the status is a module status reported by
+// the nodes.
+func ValidStatus(status int) bool {
+	switch status {
+	case StatusOK, StatusNotOK, StatusNotApplicable:
+		return true
+	}
+	return false
+}
+
+// ValidAction returns true if the action is a module action reported by
+// the nodes.
+func ValidAction(action string) bool {
+	switch action {
+	case ActionCheck, ActionFix:
+		return true
+	}
+	return false
+}
+
+// Merge returns the rules defined by the attached rulesets and modulesets.
+// A ruleset or moduleset attached several ways is merged once, with its
+// highest precedence source.
+func Merge(rulesets []Ruleset, modulesets []Moduleset) Rules {
+	rules := Rules{
+		Rulesets:   make([]string, 0),
+		Variables:  make([]Variable, 0),
+		Modulesets: make([]string, 0),
+		Modules:    make([]Module, 0),
+	}
+
+	rulesets = uniqRulesets(rulesets)
+	sort.SliceStable(rulesets, func(i, j int) bool {
+		if rulesets[i].Source != rulesets[j].Source {
+			return rulesets[i].Source < rulesets[j].Source
+		}
+		return rulesets[i].Ruleset.Name < rulesets[j].Ruleset.Name
+	})
+	variables := make(map[string]Variable)
+	for _, rs := range rulesets {
+		rules.Rulesets = append(rules.Rulesets, rs.Ruleset.Name)
+		for _, v := range rs.Variables {
+			variables[v.Name] = Variable{
+				Name:    v.Name,
+				Class:   v.Class,
+				Value:   v.Value,
+				Ruleset: rs.Ruleset.Name,
+			}
+		}
+	}
+	for _, v := range variables {
+		rules.Variables = append(rules.Variables, v)
+	}
+	sort.Strings(rules.Rulesets)
+	sort.Slice(rules.Variables, func(i, j int) bool { return rules.Variables[i].Name < rules.Variables[j].Name })
+
+	modules := make(map[string]Module)
+	for _, ms := range uniqModulesets(modulesets) {
+		rules.Modulesets = append(rules.Modulesets, ms.Moduleset.Name)
+		for _, m := range ms.Modules {
+			module, ok := modules[m.Name]
+			if !ok {
+				module = Module{Name: m.Name, Modulesets: make([]string, 0)}
+			}
+			module.Autofix = module.Autofix || m.Autofix
+			module.Modulesets = append(module.Modulesets, ms.Moduleset.Name)
+			modules[m.Name] = module
+		}
+	}
+	for _, m := range modules {
+		sort.Strings(m.Modulesets)
+		rules.Modules = append(rules.Modules, m)
+	}
+	sort.Strings(rules.Modulesets)
+	sort.Slice(rules.Modules, func(i, j int) bool { return rules.Modules[i].Name < rules.Modules[j].Name })
+	return rules
+}
+
+func uniqRulesets(l []Ruleset) []Ruleset {
+	m := make(map[uint]Ruleset)
+	for _, rs := range l {
+		if other, ok := m[rs.Ruleset.ID]; !ok || rs.Source > other.Source {
+			m[rs.Ruleset.ID] = rs
+		}
+	}
+	uniq := make([]Ruleset, 0, len(m))
+	for _, rs := range m {
+		uniq = append(uniq, rs)
+	}
+	return uniq
+}
+
+func uniqModulesets(l []Moduleset) []Moduleset {
+	m := make(map[uint]Moduleset)
+	for _, ms := range l {
+		if other, ok := m[ms.Moduleset.ID]; !ok || ms.Source > other.Source {
+			m[ms.Moduleset.ID] = ms
+		}
+	}
+	uniq := make([]Moduleset, 0, len(m))
+	for _, ms := range m {
+		uniq = append(uniq, ms)
+	}
+	return uniq
+}
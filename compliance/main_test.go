@@ -0,0 +1,115 @@
+package compliance
+
+import (
+	"testing"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	base := tables.CompRuleset{ID: 1, Name: "base"}
+	web := tables.CompRuleset{ID: 2, Name: "web"}
+	svc := tables.CompRuleset{ID: 3, Name: "svc1"}
+	variables := map[uint][]tables.CompRulesetVariable{
+		1: {{Name: "motd", Class: "file", Value: "base motd"}, {Name: "ntp", Class: "package", Value: "chrony"}},
+		2: {{Name: "motd", Class: "file", Value: "web motd"}},
+		3: {{Name: "motd", Class: "file", Value: "svc1 motd"}},
+	}
+	tests := map[string]struct {
+		rulesets []Ruleset
+		expected []Variable
+	}{
+		"none": {
+			expected: []Variable{},
+		},
+		"node tag and node": {
+			rulesets: []Ruleset{
+				{Ruleset: base, Source: SourceNode, Variables: variables[1]},
+				{Ruleset: web, Source: SourceNodeTag, Variables: variables[2]},
+			},
+			expected: []Variable{
+				{Name: "motd", Class: "file", Value: "base motd", Ruleset: "base"},
+				{Name: "ntp", Class: "package", Value: "chrony", Ruleset: "base"},
+			},
+		},
+		"service overrides node": {
+			rulesets: []Ruleset{
+				{Ruleset: svc, Source: SourceService, Variables: variables[3]},
+				{Ruleset: base, Source: SourceNode, Variables: variables[1]},
+				{Ruleset: web, Source: SourceServiceTag, Variables: variables[2]},
+			},
+			expected: []Variable{
+				{Name: "motd", Class: "file", Value: "svc1 motd", Ruleset: "svc1"},
+				{Name: "ntp", Class: "package", Value: "chrony", Ruleset: "base"},
+			},
+		},
+		"same source ordered by ruleset name": {
+			rulesets: []Ruleset{
+				{Ruleset: web, Source: SourceNode, Variables: variables[2]},
+				{Ruleset: base, Source: SourceNode, Variables: variables[1]},
+			},
+			expected: []Variable{
+				{Name: "motd", Class: "file", Value: "web motd", Ruleset: "web"},
+				{Name: "ntp", Class: "package", Value: "chrony", Ruleset: "base"},
+			},
+		},
+		"attached twice": {
+			rulesets: []Ruleset{
+				{Ruleset: web, Source: SourceNode, Variables: variables[2]},
+				{Ruleset: base, Source: SourceNodeTag, Variables: variables[1]},
+				{Ruleset: base, Source: SourceService, Variables: variables[1]},
+			},
+			expected: []Variable{
+				{Name: "motd", Class: "file", Value: "base motd", Ruleset: "base"},
+				{Name: "ntp", Class: "package", Value: "chrony", Ruleset: "base"},
+			},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		rules := Merge(test.rulesets, nil)
+		assert.Equal(t, test.expected, rules.Variables)
+		assert.Len(t, rules.Rulesets, len(uniqRulesets(test.rulesets)))
+	}
+}
+
+func TestMergeModules(t *testing.T) {
+	os := tables.CompModuleset{ID: 1, Name: "os"}
+	web := tables.CompModuleset{ID: 2, Name: "web"}
+	modulesets := []Moduleset{
+		{
+			Moduleset: web,
+			Source:    SourceService,
+			Modules:   []tables.CompModulesetModule{{Name: "motd", Autofix: true}, {Name: "httpd"}},
+		},
+		{
+			Moduleset: os,
+			Source:    SourceNode,
+			Modules:   []tables.CompModulesetModule{{Name: "motd"}, {Name: "ntp"}},
+		},
+		{
+			Moduleset: os,
+			Source:    SourceNodeTag,
+			Modules:   []tables.CompModulesetModule{{Name: "motd"}, {Name: "ntp"}},
+		},
+	}
+	rules := Merge(nil, modulesets)
+	assert.Equal(t, []string{"os", "web"}, rules.Modulesets)
+	assert.Equal(t, []Module{
+		{Name: "httpd", Autofix: false, Modulesets: []string{"web"}},
+		{Name: "motd", Autofix: true, Modulesets: []string{"os", "web"}},
+		{Name: "ntp", Autofix: false, Modulesets: []string{"os"}},
+	}, rules.Modules)
+	assert.Equal(t, []string{}, rules.Rulesets)
+}
+
+func TestValidStatusAndAction(t *testing.T) {
+	assert.True(t, ValidStatus(StatusOK))
+	assert.True(t, ValidStatus(StatusNotApplicable))
+	assert.False(t, ValidStatus(3))
+	assert.True(t, ValidAction("check"))
+	assert.True(t, ValidAction("fix"))
+	assert.False(t, ValidAction("fixable"))
+	assert.Equal(t, "service tag", SourceServiceTag.String())
+}
@@ -0,0 +1,150 @@
+package compliance
+
+import (
+	"fmt"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+type (
+	// attachment is a query of the rulesets or modulesets attached to a
+	// node or service one way.
+	attachment struct {
+		Source Source
+		Table  string
+		Join   string
+		Where  string
+		Arg    string
+	}
+)
+
+// NodeRules returns the compliance rules of the node, or of the service on
+// the node if svcID is set.
+func NodeRules(nodeID, svcID string) (Rules, error) {
+	rulesets, err := attachedRulesets(nodeID, svcID)
+	if err != nil {
+		return Rules{}, fmt.Errorf("rulesets: %w", err)
+	}
+	modulesets, err := attachedModulesets(nodeID, svcID)
+	if err != nil {
+		return Rules{}, fmt.Errorf("modulesets: %w", err)
+	}
+	rules := Merge(rulesets, modulesets)
+	rules.NodeID = nodeID
+	rules.SvcID = svcID
+	return rules, nil
+}
+
+func attachedRulesets(nodeID, svcID string) ([]Ruleset, error) {
+	sources, err := attachedIDs("comp_rulesets", "ruleset_id", nodeID, svcID)
+	if err != nil || len(sources) == 0 {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(sources))
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	data := make([]tables.CompRuleset, 0)
+	if err := db.DB().Where("id IN (?)", ids).Find(&data).Error; err != nil {
+		return nil, err
+	}
+	variables := make([]tables.CompRulesetVariable, 0)
+	if err := db.DB().Where("ruleset_id IN (?)", ids).Find(&variables).Error; err != nil {
+		return nil, err
+	}
+	l := make([]Ruleset, len(data))
+	for i, rs := range data {
+		l[i] = Ruleset{Ruleset: rs, Source: sources[rs.ID]}
+		for _, v := range variables {
+			if v.RulesetID == rs.ID {
+				l[i].Variables = append(l[i].Variables, v)
+			}
+		}
+	}
+	return l, nil
+}
+
+func attachedModulesets(nodeID, svcID string) ([]Moduleset, error) {
+	sources, err := attachedIDs("comp_modulesets", "modset_id", nodeID, svcID)
+	if err != nil || len(sources) == 0 {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(sources))
+	for id := range sources {
+		ids = append(ids, id)
+	}
+	data := make([]tables.CompModuleset, 0)
+	if err := db.DB().Where("id IN (?)", ids).Find(&data).Error; err != nil {
+		return nil, err
+	}
+	modules := make([]tables.CompModulesetModule, 0)
+	if err := db.DB().Where("modset_id IN (?)", ids).Find(&modules).Error; err != nil {
+		return nil, err
+	}
+	l := make([]Moduleset, len(data))
+	for i, ms := range data {
+		l[i] = Moduleset{Moduleset: ms, Source: sources[ms.ID]}
+		for _, m := range modules {
+			if m.ModsetID == ms.ID {
+				l[i].Modules = append(l[i].Modules, m)
+			}
+		}
+	}
+	return l, nil
+}
+
+// attachedIDs returns the highest precedence source of the rulesets or
+// modulesets attached to the node or service, indexed by id. The prefix is
+// the prefix of the attachment tables, and col their ruleset or moduleset
+// id column.
+func attachedIDs(prefix, col, nodeID, svcID string) (map[uint]Source, error) {
+	attachments := []attachment{
+		{
+			Source: SourceNodeTag,
+			Table:  prefix + "_tags",
+			Join:   fmt.Sprintf("JOIN node_tags ON node_tags.tag_id = %s_tags.tag_id AND node_tags.deleted_at IS NULL", prefix),
+			Where:  "node_tags.node_id = ?",
+			Arg:    nodeID,
+		},
+		{
+			Source: SourceNode,
+			Table:  prefix + "_nodes",
+			Where:  prefix + "_nodes.node_id = ?",
+			Arg:    nodeID,
+		},
+	}
+	if svcID != "" {
+		attachments = append(attachments,
+			attachment{
+				Source: SourceServiceTag,
+				Table:  prefix + "_tags",
+				Join:   fmt.Sprintf("JOIN svc_tags ON svc_tags.tag_id = %s_tags.tag_id AND svc_tags.deleted_at IS NULL", prefix),
+				Where:  "svc_tags.svc_id = ?",
+				Arg:    svcID,
+			},
+			attachment{
+				Source: SourceService,
+				Table:  prefix + "_services",
+				Where:  prefix + "_services.svc_id = ?",
+				Arg:    svcID,
+			},
+		)
+	}
+	m := make(map[uint]Source)
+	for _, a := range attachments {
+		ids := make([]uint, 0)
+		tx := db.DB().Table(a.Table)
+		if a.Join != "" {
+			tx = tx.Joins(a.Join)
+		}
+		if err := tx.Where(a.Where, a.Arg).Pluck(a.Table+"."+col, &ids).Error; err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Table, err)
+		}
+		for _, id := range ids {
+			// the attachments are listed by increasing precedence
+			m[id] = a.Source
+		}
+	}
+	return m, nil
+}
@@ -20,6 +20,7 @@ func initConf() error {
 	viper.SetDefault("db.port", "3306")
 	viper.SetDefault("db.log.level", "warn")
 	viper.SetDefault("db.log.slow_query_threshold", "1s")
+	viper.SetDefault("jobs.workers", 4)
 
 	// config file
 	viper.SetConfigName("config")
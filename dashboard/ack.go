@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultAckDuration is the duration of an acknowledgement without
+	// explicit expiry.
+	DefaultAckDuration = 24 * time.Hour
+)
+
+// Ack acknowledges the alerts of the entry type on its node or service,
+// until the expires date, and returns the acknowledgement. An existing
+// acknowledgement is replaced.
+func Ack(e tables.Dashboard, userID uint, comment string, expires time.Time) (tables.DashboardAck, error) {
+	ack := tables.DashboardAck{
+		Type:    e.Type,
+		NodeID:  e.NodeID,
+		SvcID:   e.SvcID,
+		App:     e.App,
+		UserID:  userID,
+		Comment: comment,
+		Expires: expires,
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"app", "user_id", "comment", "expires", "updated_at"}),
+	})
+	if err := tx.Create(&ack).Error; err != nil {
+		return ack, err
+	}
+	err := db.DB().Where("dash_type = ? AND node_id = ? AND svc_id = ?", ack.Type, ack.NodeID, ack.SvcID).Take(&ack).Error
+	if err != nil {
+		return ack, err
+	}
+	return ack, setAcked(e, true)
+}
+
+// Unack removes the acknowledgement of the alerts of the entry type on its
+// node or service, and returns the removed acknowledgements.
+func Unack(e tables.Dashboard) ([]tables.DashboardAck, error) {
+	acks := make([]tables.DashboardAck, 0)
+	err := db.DB().
+		Where("dash_type = ? AND node_id = ? AND svc_id = ?", e.Type, e.NodeID, e.SvcID).
+		Find(&acks).Error
+	if err != nil || len(acks) == 0 {
+		return acks, err
+	}
+	if err := db.DB().Delete(&acks).Error; err != nil {
+		return acks, err
+	}
+	return acks, setAcked(e, false)
+}
+
+func setAcked(e tables.Dashboard, v bool) error {
+	return db.DB().Model(&tables.Dashboard{}).
+		Where("dash_type = ? AND node_id = ? AND svc_id = ?", e.Type, e.NodeID, e.SvcID).
+		Update("acked", v).Error
+}
@@ -0,0 +1,178 @@
+// Package dashboard computes the dashboard alerts from the nodes and
+// services state.
+//
+// The alerts are raised by the registered rules. A rule checks a node or a
+// service, and returns the alerts it raises on it. The alerts are
+// refreshed in the background by the RefreshJob jobs, enqueued by the
+// handlers changing the state of a node or service, and periodically for
+// all nodes and services, as some rules depend on the current time.
+//
+// No alert is raised on a snoozed node or service. The alerts of a type on
+// a node or service can be acknowledged until an expiry date, in which case
+// they are still raised, flagged as acked, but not notified.
+//
+// The refresh publishes a creation event for the new alerts, and an update
+// event for the alerts whose severity increased or whose ack expired, so
+// they are notified again.
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/jobs"
+)
+
+const (
+	// RefreshJob is the type of the jobs refreshing the dashboard alerts.
+	RefreshJob = "dashboard_alerts_refresh"
+
+	SeverityInfo     = 0
+	SeverityNotice   = 1
+	SeverityWarning  = 2
+	SeverityError    = 3
+	SeverityCritical = 4
+)
+
+type (
+	// Alert is an alert raised by a rule. Its type is the rule name.
+	Alert struct {
+		Severity int
+		Fmt      string
+		Dict     map[string]interface{}
+	}
+
+	// NodeRule returns the alerts raised on a node.
+	NodeRule func(node tables.Node, now time.Time) []Alert
+
+	// ServiceRule returns the alerts raised on a service.
+	ServiceRule func(svc tables.Service, now time.Time) []Alert
+
+	// Rule raises the alerts of a type. A rule checks nodes, services, or
+	// both.
+	Rule struct {
+		Name    string
+		Node    NodeRule
+		Service ServiceRule
+	}
+
+	// RefreshArgs are the args of a RefreshJob job. The alerts of all
+	// the nodes and services are refreshed if none is set.
+	RefreshArgs struct {
+		NodeID    uint `json:"node_id,omitempty"`
+		ServiceID uint `json:"service_id,omitempty"`
+	}
+
+	// RefreshResult is the result of a RefreshJob job.
+	RefreshResult struct {
+		Nodes    int `json:"nodes"`
+		Services int `json:"services"`
+		Alerts   int `json:"alerts"`
+	}
+)
+
+var (
+	reDictKey = regexp.MustCompile(`%\(([a-zA-Z0-9_]+)\)s`)
+
+	rulesMu sync.RWMutex
+	rules   = make(map[string]Rule)
+
+	// RefreshInterval is the interval between two refreshes of the
+	// alerts of all nodes and services.
+	RefreshInterval = 10 * time.Minute
+)
+
+func init() {
+	jobs.Register(RefreshJob, refresh, jobs.WithUnique(true))
+}
+
+// RegisterRule adds a rule to the rules run by the refresh jobs.
+func RegisterRule(r Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[r.Name] = r
+}
+
+// Rules returns the registered rules, sorted by name.
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	l := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		l = append(l, r)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l
+}
+
+// nodeRuleNames returns the names of the rules checking nodes.
+func nodeRuleNames() []string {
+	l := make([]string, 0)
+	for _, r := range Rules() {
+		if r.Node != nil {
+			l = append(l, r.Name)
+		}
+	}
+	return l
+}
+
+// serviceRuleNames returns the names of the rules checking services.
+func serviceRuleNames() []string {
+	l := make([]string, 0)
+	for _, r := range Rules() {
+		if r.Service != nil {
+			l = append(l, r.Name)
+		}
+	}
+	return l
+}
+
+// EnqueueRefresh enqueues the refresh of the alerts, on behalf of the user
+// with the specified id, and returns the job.
+func EnqueueRefresh(userID uint, args RefreshArgs) (tables.Job, error) {
+	return jobs.Enqueue(RefreshJob, userID, args)
+}
+
+// Start enqueues the refresh of all the alerts every RefreshInterval,
+// until the context is done.
+func Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(RefreshInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := EnqueueRefresh(0, RefreshArgs{}); err != nil {
+				log.Printf("dashboard: enqueue alerts refresh: %s", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Message returns the message of a dashboard entry: its format with the
+// %(key)s placeholders replaced by the dict values.
+func Message(e tables.Dashboard) string {
+	dict := make(map[string]interface{})
+	if len(e.Dict) > 0 {
+		if err := json.Unmarshal(e.Dict, &dict); err != nil {
+			return e.Fmt
+		}
+	}
+	return reDictKey.ReplaceAllStringFunc(e.Fmt, func(s string) string {
+		m := reDictKey.FindStringSubmatch(s)
+		if v, ok := dict[m[1]]; ok {
+			return fmt.Sprint(v)
+		}
+		return s
+	})
+}
@@ -0,0 +1,231 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestNodeAlerts(t *testing.T) {
+	now := time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)
+	created := now.AddDate(-2, 0, 0)
+	healthy := tables.Node{
+		NodeID:          "a6bde1b2-0b5a-4c8f-9c1e-1d6c2b0b8b01",
+		App:             "app1",
+		NodeEnv:         "PRD",
+		TeamResponsible: "team1",
+		CreatedAt:       created,
+		WarrantyEnd:     now.AddDate(1, 0, 0),
+		MaintenanceEnd:  now.AddDate(1, 0, 0),
+		LastComm:        now.Add(-time.Hour),
+	}
+	tests := map[string]struct {
+		node     func(tables.Node) tables.Node
+		expected map[string]int
+	}{
+		"healthy": {
+			node:     func(n tables.Node) tables.Node { return n },
+			expected: map[string]int{},
+		},
+		"unset end dates": {
+			node: func(n tables.Node) tables.Node {
+				n.WarrantyEnd = created
+				n.MaintenanceEnd = time.Time{}
+				return n
+			},
+			expected: map[string]int{},
+		},
+		"warranty ends soon": {
+			node: func(n tables.Node) tables.Node {
+				n.WarrantyEnd = now.AddDate(0, 0, 10)
+				return n
+			},
+			expected: map[string]int{"node warranty end": SeverityWarning},
+		},
+		"maintenance ended": {
+			node: func(n tables.Node) tables.Node {
+				n.MaintenanceEnd = now.AddDate(0, -1, 0)
+				return n
+			},
+			expected: map[string]int{"node maintenance end": SeverityError},
+		},
+		"stale": {
+			node: func(n tables.Node) tables.Node {
+				n.LastComm = now.AddDate(0, 0, -2)
+				return n
+			},
+			expected: map[string]int{"node stale": SeverityWarning},
+		},
+		"snoozed": {
+			node: func(n tables.Node) tables.Node {
+				n.NodeFrozen = true
+				n.SnoozeTill = now.Add(time.Hour)
+				return n
+			},
+			expected: map[string]int{},
+		},
+		"snooze expired": {
+			node: func(n tables.Node) tables.Node {
+				n.NodeFrozen = true
+				n.SnoozeTill = now.Add(-time.Hour)
+				return n
+			},
+			expected: map[string]int{"node frozen": SeverityInfo},
+		},
+		"frozen without team responsible": {
+			node: func(n tables.Node) tables.Node {
+				n.NodeFrozen = true
+				n.TeamResponsible = ""
+				return n
+			},
+			expected: map[string]int{
+				"node frozen":                   SeverityInfo,
+				"node without team responsible": SeverityWarning,
+			},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		entries, err := NodeAlerts(test.node(healthy), now)
+		assert.Nil(t, err)
+		found := make(map[string]int)
+		for _, e := range entries {
+			assert.Equal(t, healthy.NodeID, e.NodeID)
+			assert.Equal(t, "", e.SvcID)
+			assert.Equal(t, "app1", e.App)
+			assert.Equal(t, "PRD", e.Env)
+			found[e.Type] = e.Severity
+		}
+		assert.Equal(t, test.expected, found)
+	}
+}
+
+func TestServiceAlerts(t *testing.T) {
+	now := time.Date(2022, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		svc      tables.Service
+		expected map[string]int
+	}{
+		"up": {
+			svc:      tables.Service{SvcStatus: "up", SvcEnv: "PRD"},
+			expected: map[string]int{},
+		},
+		"undef": {
+			svc:      tables.Service{SvcStatus: "undef"},
+			expected: map[string]int{},
+		},
+		"down": {
+			svc:      tables.Service{SvcStatus: "down", SvcEnv: "TST"},
+			expected: map[string]int{"service status": SeverityWarning},
+		},
+		"snoozed": {
+			svc:      tables.Service{SvcStatus: "down", SvcSnoozeTill: now.Add(time.Hour)},
+			expected: map[string]int{},
+		},
+		"production down and frozen": {
+			svc:      tables.Service{SvcStatus: "down", SvcEnv: "PRD", SvcFrozen: "frozen"},
+			expected: map[string]int{"service status": SeverityError, "service frozen": SeverityInfo},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		test.svc.SvcID = "0b8e6a3c-7a1e-4a55-8a6e-5e0f4f1e3c02"
+		entries, err := ServiceAlerts(test.svc, now)
+		assert.Nil(t, err)
+		found := make(map[string]int)
+		for _, e := range entries {
+			assert.Equal(t, test.svc.SvcID, e.SvcID)
+			assert.Equal(t, "", e.NodeID)
+			found[e.Type] = e.Severity
+		}
+		assert.Equal(t, test.expected, found)
+	}
+}
+
+func TestAlertEntryDict(t *testing.T) {
+	a := Alert{
+		Severity: SeverityWarning,
+		Fmt:      "service status is %(status)s",
+		Dict:     map[string]interface{}{"status": "down"},
+	}
+	e, err := a.entry("service status", "app1", "TST")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"status":"down"}`, string(e.Dict))
+	assert.Equal(t, "service status", e.Type)
+}
+
+func TestMessage(t *testing.T) {
+	tests := map[string]struct {
+		fmt, dict, expected string
+	}{
+		"no dict": {
+			fmt:      "node is frozen",
+			expected: "node is frozen",
+		},
+		"placeholders": {
+			fmt:      "service status is %(status)s since %(date)s",
+			dict:     `{"status":"down","date":"2022-06-15"}`,
+			expected: "service status is down since 2022-06-15",
+		},
+		"missing key": {
+			fmt:      "service status is %(status)s",
+			dict:     `{}`,
+			expected: "service status is %(status)s",
+		},
+		"number": {
+			fmt:      "%(n)s errors",
+			dict:     `{"n":3}`,
+			expected: "3 errors",
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		e := tables.Dashboard{Fmt: test.fmt, Dict: datatypes.JSON(test.dict)}
+		assert.Equal(t, test.expected, Message(e))
+	}
+}
+
+func TestEscalation(t *testing.T) {
+	tests := map[string]struct {
+		before, after tables.Dashboard
+		expected      []string
+	}{
+		"unchanged": {
+			before:   tables.Dashboard{Severity: 1},
+			after:    tables.Dashboard{Severity: 1},
+			expected: []string{},
+		},
+		"severity decreased": {
+			before:   tables.Dashboard{Severity: 3},
+			after:    tables.Dashboard{Severity: 1},
+			expected: []string{},
+		},
+		"severity increased": {
+			before:   tables.Dashboard{Severity: 1},
+			after:    tables.Dashboard{Severity: 3},
+			expected: []string{"dash_severity"},
+		},
+		"ack expired": {
+			before:   tables.Dashboard{Severity: 1, Acked: true},
+			after:    tables.Dashboard{Severity: 1},
+			expected: []string{"acked"},
+		},
+		"acked": {
+			before:   tables.Dashboard{Severity: 1},
+			after:    tables.Dashboard{Severity: 1, Acked: true},
+			expected: []string{},
+		},
+		"severity increased and ack expired": {
+			before:   tables.Dashboard{Severity: 1, Acked: true},
+			after:    tables.Dashboard{Severity: 3},
+			expected: []string{"dash_severity", "acked"},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, escalation(test.before, test.after))
+	}
+}
@@ -0,0 +1,310 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"github.com/opensvc/collector-api/jobs"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	refreshBatch = 100
+)
+
+func refresh(ctx context.Context, job *jobs.Job) (interface{}, error) {
+	args := RefreshArgs{}
+	if err := job.DecodeArgs(&args); err != nil {
+		return nil, fmt.Errorf("decode args: %w", err)
+	}
+	result := RefreshResult{}
+	// the datetime columns have a second precision, so the refreshed
+	// entries updated_at is not before now
+	now := time.Now().Truncate(time.Second)
+	switch {
+	case args.NodeID != 0:
+		node := tables.Node{}
+		if err := db.DB().Where("id = ?", args.NodeID).Take(&node).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		n, err := syncNode(node, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Nodes = 1
+		result.Alerts = n
+	case args.ServiceID != 0:
+		svc := tables.Service{}
+		if err := db.DB().Where("id = ?", args.ServiceID).Take(&svc).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		n, err := syncService(svc, now)
+		if err != nil {
+			return nil, err
+		}
+		result.Services = 1
+		result.Alerts = n
+	default:
+		if err := refreshAll(ctx, job, now, &result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// refreshAll refreshes the alerts of all nodes and services, and removes
+// the alerts of the nodes and services no longer in the database.
+func refreshAll(ctx context.Context, job *jobs.Job, now time.Time, result *RefreshResult) error {
+	var nodeCount, svcCount int64
+	if err := db.DB().Model(&tables.Node{}).Count(&nodeCount).Error; err != nil {
+		return err
+	}
+	if err := db.DB().Model(&tables.Service{}).Count(&svcCount).Error; err != nil {
+		return err
+	}
+	total := int(nodeCount + svcCount)
+	progress := func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if total == 0 {
+			return nil
+		}
+		done := result.Nodes + result.Services
+		return job.SetProgress(done*100/total, fmt.Sprintf("%d/%d nodes and services", done, total))
+	}
+	nodes := make([]tables.Node, 0)
+	tx := db.DB().FindInBatches(&nodes, refreshBatch, func(tx *gorm.DB, batch int) error {
+		for _, node := range nodes {
+			n, err := syncNode(node, now)
+			if err != nil {
+				return err
+			}
+			result.Nodes++
+			result.Alerts += n
+		}
+		return progress()
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	svcs := make([]tables.Service, 0)
+	tx = db.DB().FindInBatches(&svcs, refreshBatch, func(tx *gorm.DB, batch int) error {
+		for _, svc := range svcs {
+			n, err := syncService(svc, now)
+			if err != nil {
+				return err
+			}
+			result.Services++
+			result.Alerts += n
+		}
+		return progress()
+	})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	names := make([]string, 0)
+	for _, r := range Rules() {
+		names = append(names, r.Name)
+	}
+	stale := make([]uint, 0)
+	err := db.DB().Model(&tables.Dashboard{}).
+		Where("dash_type IN ? AND updated_at < ?", names, now).
+		Pluck("id", &stale).Error
+	if err != nil {
+		return err
+	}
+	return deleteEntries(stale)
+}
+
+// NodeAlerts returns the dashboard entries of the alerts raised on the node
+// by the registered rules. No alert is raised on a snoozed node.
+func NodeAlerts(node tables.Node, now time.Time) ([]tables.Dashboard, error) {
+	l := make([]tables.Dashboard, 0)
+	if node.SnoozeTill.After(now) {
+		return l, nil
+	}
+	for _, r := range Rules() {
+		if r.Node == nil {
+			continue
+		}
+		for _, a := range r.Node(node, now) {
+			e, err := a.entry(r.Name, node.App, node.NodeEnv)
+			if err != nil {
+				return l, err
+			}
+			e.NodeID = node.NodeID
+			l = append(l, e)
+		}
+	}
+	return l, nil
+}
+
+// ServiceAlerts returns the dashboard entries of the alerts raised on the
+// service by the registered rules. No alert is raised on a snoozed service.
+func ServiceAlerts(svc tables.Service, now time.Time) ([]tables.Dashboard, error) {
+	l := make([]tables.Dashboard, 0)
+	if svc.SvcSnoozeTill.After(now) {
+		return l, nil
+	}
+	for _, r := range Rules() {
+		if r.Service == nil {
+			continue
+		}
+		for _, a := range r.Service(svc, now) {
+			e, err := a.entry(r.Name, svc.SvcApp, svc.SvcEnv)
+			if err != nil {
+				return l, err
+			}
+			e.SvcID = svc.SvcID
+			l = append(l, e)
+		}
+	}
+	return l, nil
+}
+
+func (t Alert) entry(name, app, env string) (tables.Dashboard, error) {
+	e := tables.Dashboard{
+		Type:     name,
+		App:      app,
+		Env:      env,
+		Severity: t.Severity,
+		Fmt:      t.Fmt,
+	}
+	if t.Dict != nil {
+		b, err := json.Marshal(t.Dict)
+		if err != nil {
+			return e, fmt.Errorf("%s: encode dict: %w", name, err)
+		}
+		e.Dict = b
+	}
+	return e, nil
+}
+
+func syncNode(node tables.Node, now time.Time) (int, error) {
+	entries, err := NodeAlerts(node, now)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), syncEntries(node.NodeID, "", nodeRuleNames(), entries, now)
+}
+
+func syncService(svc tables.Service, now time.Time) (int, error) {
+	entries, err := ServiceAlerts(svc, now)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), syncEntries("", svc.SvcID, serviceRuleNames(), entries, now)
+}
+
+// syncEntries upserts the entries of a node or service, flagged if
+// acknowledged, and deletes the entries of the rules that are not raised
+// anymore. The creation and deletion events of the entries are published,
+// so the raised alerts are notified.
+func syncEntries(nodeID, svcID string, ruleNames []string, entries []tables.Dashboard, now time.Time) error {
+	acks := make([]tables.DashboardAck, 0)
+	err := db.DB().
+		Where("node_id = ? AND svc_id = ? AND expires > ?", nodeID, svcID, now).
+		Find(&acks).Error
+	if err != nil {
+		return err
+	}
+	acked := make(map[string]bool)
+	for _, ack := range acks {
+		acked[ack.Type] = true
+	}
+	existing := make([]tables.Dashboard, 0)
+	err = db.DB().
+		Where("node_id = ? AND svc_id = ? AND dash_type IN ?", nodeID, svcID, ruleNames).
+		Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]tables.Dashboard)
+	for _, e := range existing {
+		previous[e.Type] = e
+	}
+	raised := make(map[string]bool)
+	created := make([]string, 0)
+	escalated := make(map[uint][]string)
+	for i := range entries {
+		entries[i].UpdatedAt = now
+		entries[i].Acked = acked[entries[i].Type]
+		raised[entries[i].Type] = true
+		if before, ok := previous[entries[i].Type]; !ok {
+			created = append(created, entries[i].Type)
+		} else if props := escalation(before, entries[i]); len(props) > 0 {
+			escalated[before.ID] = props
+		}
+	}
+	if len(entries) > 0 {
+		tx := db.DB().Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"app", "dash_env", "dash_severity", "dash_fmt", "dash_dict", "acked", "updated_at"}),
+		})
+		if err := tx.Create(&entries).Error; err != nil {
+			return err
+		}
+	}
+	if len(created) > 0 {
+		ids := make([]uint, 0)
+		err := db.DB().Model(&tables.Dashboard{}).
+			Where("node_id = ? AND svc_id = ? AND dash_type IN ?", nodeID, svcID, created).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			events.Publish(events.Create, "dashboard", id)
+		}
+	}
+	for id, props := range escalated {
+		events.Publish(events.Update, "dashboard", id, props...)
+	}
+	removed := make([]uint, 0)
+	for _, e := range existing {
+		if !raised[e.Type] {
+			removed = append(removed, e.ID)
+		}
+	}
+	return deleteEntries(removed)
+}
+
+// escalation returns the properties of a raised dashboard entry changed in
+// a way the responsible users are notified of again: the severity if it
+// increased, and the acked flag if the entry ack expired.
+func escalation(before, after tables.Dashboard) []string {
+	props := make([]string, 0)
+	if after.Severity > before.Severity {
+		props = append(props, "dash_severity")
+	}
+	if before.Acked && !after.Acked {
+		props = append(props, "acked")
+	}
+	return props
+}
+
+// deleteEntries deletes the dashboard entries and publishes their deletion
+// events.
+func deleteEntries(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := db.DB().Where("id IN ?", ids).Delete(&tables.Dashboard{}).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		events.Publish(events.Delete, "dashboard", id)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package dashboard
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+const (
+	// endNotice is the delay before a warranty or maintenance end from
+	// which an alert is raised.
+	endNotice = 30 * 24 * time.Hour
+
+	// staleComm is the delay since the last communication of a node after
+	// which an alert is raised.
+	staleComm = 24 * time.Hour
+
+	dateLayout = "2006-01-02"
+)
+
+func init() {
+	RegisterRule(Rule{Name: "node warranty end", Node: nodeWarrantyEnd})
+	RegisterRule(Rule{Name: "node maintenance end", Node: nodeMaintenanceEnd})
+	RegisterRule(Rule{Name: "node stale", Node: nodeStale})
+	RegisterRule(Rule{Name: "node frozen", Node: nodeFrozen})
+	RegisterRule(Rule{Name: "node without team responsible", Node: nodeWithoutTeamResponsible})
+	RegisterRule(Rule{Name: "service status", Service: serviceStatus})
+	RegisterRule(Rule{Name: "service frozen", Service: serviceFrozen})
+}
+
+// endAlerts returns the alerts of an approaching or passed end date. The
+// end dates not after the node creation date are considered unset, as the
+// nodes table defaults them to the creation date.
+func endAlerts(what string, end, created, now time.Time) []Alert {
+	if end.IsZero() || !end.After(created) {
+		return nil
+	}
+	dict := map[string]interface{}{"date": end.Format(dateLayout)}
+	switch {
+	case !end.After(now):
+		return []Alert{{Severity: SeverityError, Fmt: what + " ended on %(date)s", Dict: dict}}
+	case end.Sub(now) < endNotice:
+		return []Alert{{Severity: SeverityWarning, Fmt: what + " ends on %(date)s", Dict: dict}}
+	default:
+		return nil
+	}
+}
+
+func nodeWarrantyEnd(node tables.Node, now time.Time) []Alert {
+	return endAlerts("warranty", node.WarrantyEnd, node.CreatedAt, now)
+}
+
+func nodeMaintenanceEnd(node tables.Node, now time.Time) []Alert {
+	return endAlerts("maintenance", node.MaintenanceEnd, node.CreatedAt, now)
+}
+
+func nodeStale(node tables.Node, now time.Time) []Alert {
+	if node.LastComm.IsZero() || now.Sub(node.LastComm) < staleComm {
+		return nil
+	}
+	return []Alert{{
+		Severity: SeverityWarning,
+		Fmt:      "node last communicated on %(date)s",
+		Dict:     map[string]interface{}{"date": node.LastComm.Format(dateLayout)},
+	}}
+}
+
+func nodeFrozen(node tables.Node, now time.Time) []Alert {
+	if !node.NodeFrozen {
+		return nil
+	}
+	return []Alert{{Severity: SeverityInfo, Fmt: "node is frozen"}}
+}
+
+func nodeWithoutTeamResponsible(node tables.Node, now time.Time) []Alert {
+	if node.TeamResponsible != "" {
+		return nil
+	}
+	return []Alert{{Severity: SeverityWarning, Fmt: "node has no team responsible"}}
+}
+
+// serviceStatus raises an alert for the services whose status is known and
+// not up. The alert is an error for production services.
+func serviceStatus(svc tables.Service, now time.Time) []Alert {
+	switch svc.SvcStatus {
+	case "", "up", "n/a", "undef":
+		return nil
+	}
+	severity := SeverityWarning
+	if svc.SvcEnv == "PRD" {
+		severity = SeverityError
+	}
+	return []Alert{{
+		Severity: severity,
+		Fmt:      "service status is %(status)s",
+		Dict:     map[string]interface{}{"status": svc.SvcStatus},
+	}}
+}
+
+func serviceFrozen(svc tables.Service, now time.Time) []Alert {
+	if svc.SvcFrozen != "frozen" {
+		return nil
+	}
+	return []Alert{{Severity: SeverityInfo, Fmt: "service is frozen"}}
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+type (
+	// aggregate is an aggregate function applied to a property, as parsed
+	// from an element of the "aggregate" query parameter, for example
+	// "sum(nodes.mem_bytes):mem".
+	aggregate struct {
+		Func  string
+		Prop  property
+		Alias string
+	}
+	aggregates []aggregate
+)
+
+var (
+	// secretProps are the properties that can not be selected, filtered,
+	// grouped, ordered or aggregated through the generic request
+	// parameters. The request parser verifies every property it resolves
+	// with checkProperty.
+	secretProps = map[property]bool{
+		{Table: "auth_user", Name: "password"}:           true,
+		{Table: "auth_user", Name: "reset_password_key"}: true,
+		{Table: "auth_user", Name: "registration_key"}:   true,
+		{Table: "auth_node", Name: "uuid"}:               true,
+		{Table: "webhooks", Name: "secret"}:              true,
+	}
+
+	reAggregate = regexp.MustCompile(`^(count|sum|avg|min|max)\(\s*(\*|[a-zA-Z_][a-zA-Z0-9_.]*)\s*\)(?::([a-zA-Z_][a-zA-Z0-9_]*))?$`)
+	reHaving    = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*(?:\([a-zA-Z0-9_.*]+\))?)\s*(>=|<=|!=|=|>|<)\s*(.*)$`)
+)
+
+func (t aggregate) SQL() string {
+	if t.Prop.Name == "*" {
+		return "COUNT(*)"
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToUpper(t.Func), t.Prop.SQL())
+}
+
+func (t aggregates) has(alias string) bool {
+	for _, a := range t {
+		if a.Alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// orders returns the ordering properties with the references to the
+// aggregate aliases replaced by unqualified properties, so they are
+// ordered by the aggregated values.
+func (t aggregates) orders(orders propSlice, table string) propSlice {
+	l := make(propSlice, len(orders))
+	for i, prop := range orders {
+		switch {
+		case t.has(prop.String()):
+			l[i] = property{Name: prop.String(), Desc: prop.Desc}
+		case prop.Table == table && t.has(prop.Name):
+			l[i] = property{Name: prop.Name, Desc: prop.Desc}
+		default:
+			l[i] = prop
+		}
+	}
+	return l
+}
+
+func isNumeric(typ schema.DataType) bool {
+	switch typ {
+	case schema.Int, schema.Uint, schema.Float:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkProperty verifies the property is a non-secret column of a
+// registered table joinable to the base table, and returns its validation
+// rule.
+func checkProperty(base string, prop property) (fieldRule, error) {
+	name, _ := splitAlias(prop.Table)
+	if secretProps[property{Table: name, Name: prop.Name}] {
+		return fieldRule{}, fmt.Errorf("%w: property %s is not allowed", ErrBadRequest, prop)
+	}
+	t := Tab(name)
+	if t == nil {
+		return fieldRule{}, fmt.Errorf("%w: unknown table %s", ErrBadRequest, name)
+	}
+	if err := checkJoin(base, name); err != nil {
+		return fieldRule{}, err
+	}
+	rules, err := t.fieldRules()
+	if err != nil {
+		return fieldRule{}, err
+	}
+	rule, ok := rules[prop.Name]
+	if !ok {
+		return fieldRule{}, fmt.Errorf("%w: unknown property %s", ErrBadRequest, prop)
+	}
+	return rule, nil
+}
+
+func (t Table) queryAggregates(r *http.Request) (aggregates, error) {
+	s := r.URL.Query().Get("aggregate")
+	return t.parseAggregates(s)
+}
+
+func queryHaving(r *http.Request) []string {
+	if l, ok := r.URL.Query()["having"]; ok {
+		return l
+	}
+	return []string{}
+}
+
+// parseAggregates parses a comma separated list of aggregates:
+//
+//	count(*):n,sum(nodes.mem_bytes):mem,avg(nodes.cpu_cores)
+//
+// The alias defaults to the aggregate expression, "avg(nodes.cpu_cores)"
+// in this example.
+func (t Table) parseAggregates(s string) (aggregates, error) {
+	l := make(aggregates, 0)
+	for _, s := range strings.Split(s, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		m := reAggregate.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("%w: invalid aggregate %s", ErrBadRequest, s)
+		}
+		a := aggregate{Func: m[1], Alias: m[3]}
+		if m[2] == "*" {
+			if a.Func != "count" {
+				return nil, fmt.Errorf("%w: invalid aggregate %s: only count accepts *", ErrBadRequest, s)
+			}
+			a.Prop = property{Name: "*"}
+		} else {
+			a.Prop = t.parseProperty(m[2])
+			rule, err := checkProperty(t.Name, a.Prop)
+			if err != nil {
+				return nil, err
+			}
+			switch a.Func {
+			case "sum", "avg":
+				if !isNumeric(rule.Type) {
+					return nil, fmt.Errorf("%w: invalid aggregate %s: %s is not numeric", ErrBadRequest, s, a.Prop)
+				}
+			}
+		}
+		if a.Alias == "" {
+			a.Alias = fmt.Sprintf("%s(%s)", a.Func, a.Prop)
+		}
+		if l.has(a.Alias) {
+			return nil, fmt.Errorf("%w: duplicate aggregate alias %s", ErrBadRequest, a.Alias)
+		}
+		l = append(l, a)
+	}
+	return l, nil
+}
+
+// withAggregates selects the props and the aggregates. The props must be
+// grouping properties and default to all of them.
+func (t *request) withAggregates(props, groups propSlice, aggs aggregates) error {
+	for _, prop := range groups {
+		if _, err := checkProperty(t.table.Name, prop); err != nil {
+			return err
+		}
+		t.AutoJoin(prop.Table)
+	}
+	if len(props) == 0 {
+		props = groups
+	}
+	selects := make([]string, 0, len(props)+len(aggs))
+	for _, prop := range props {
+		if !groups.has(prop) {
+			return fmt.Errorf("%w: property %s is neither aggregated nor in groupby", ErrBadRequest, prop)
+		}
+		as := prop.Remap
+		if as == "" {
+			as = prop.String()
+		}
+		selects = append(selects, fmt.Sprintf("%s as `%s`", prop.SQL(), as))
+	}
+	for _, a := range aggs {
+		if a.Prop.Name != "*" {
+			t.AutoJoin(a.Prop.Table)
+		}
+		selects = append(selects, fmt.Sprintf("%s as `%s`", a.SQL(), a.Alias))
+	}
+	t.tx.Select(strings.Join(selects, ","))
+	return nil
+}
+
+// withHaving filters the aggregated rows. The conditions use the filters
+// syntax, with an aggregate alias as left operand: "n>=10".
+func (t *request) withHaving(having []string, aggs aggregates) error {
+	for _, s := range having {
+		m := reHaving.FindStringSubmatch(s)
+		if m == nil {
+			return fmt.Errorf("%w: invalid having condition %s", ErrBadRequest, s)
+		}
+		if !aggs.has(m[1]) {
+			return fmt.Errorf("%w: invalid having condition %s: %s is not an aggregate alias", ErrBadRequest, s, m[1])
+		}
+		op := m[2]
+		if op == "!=" {
+			op = "<>"
+		}
+		t.tx = t.tx.Having(fmt.Sprintf("`%s` %s ?", m[1], op), m[3])
+	}
+	return nil
+}
+
+// has returns true if the slice contains a property with the same table
+// and name.
+func (t propSlice) has(prop property) bool {
+	for _, p := range t {
+		if p.Table == prop.Table && p.Name == prop.Name {
+			return true
+		}
+	}
+	return false
+}
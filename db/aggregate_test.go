@@ -0,0 +1,117 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type aggregateTestEntry struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	Nodename string `gorm:"column:nodename" json:"nodename"`
+	MemBytes int    `gorm:"column:mem_bytes" json:"mem_bytes"`
+}
+
+func TestParseAggregates(t *testing.T) {
+	table := Table{Name: "aggregate_test", Entry: aggregateTestEntry{}}
+	Register(&table)
+	tests := map[string]struct {
+		input  string
+		output aggregates
+		err    bool
+	}{
+		"empty": {
+			input:  "",
+			output: aggregates{},
+		},
+		"aliased and default alias": {
+			input: "count(*):n,sum(aggregate_test.mem_bytes):mem,max(nodename)",
+			output: aggregates{
+				{Func: "count", Prop: property{Name: "*"}, Alias: "n"},
+				{Func: "sum", Prop: property{Table: "aggregate_test", Name: "mem_bytes"}, Alias: "mem"},
+				{Func: "max", Prop: property{Table: "aggregate_test", Name: "nodename"}, Alias: "max(aggregate_test.nodename)"},
+			},
+		},
+		"unknown function": {
+			input: "median(mem_bytes)",
+			err:   true,
+		},
+		"unknown property": {
+			input: "sum(foo)",
+			err:   true,
+		},
+		"unknown table": {
+			input: "sum(foo.mem_bytes)",
+			err:   true,
+		},
+		"sum of string": {
+			input: "sum(nodename)",
+			err:   true,
+		},
+		"sum of star": {
+			input: "sum(*)",
+			err:   true,
+		},
+		"duplicate alias": {
+			input: "count(*):n,max(mem_bytes):n",
+			err:   true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		output, err := table.parseAggregates(test.input)
+		if test.err {
+			assert.True(t, errors.Is(err, ErrBadRequest))
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.output, output)
+	}
+}
+
+func TestAggregatesOrders(t *testing.T) {
+	table := Table{Name: "aggregate_test", Entry: aggregateTestEntry{}}
+	aggs := aggregates{
+		{Func: "count", Prop: property{Name: "*"}, Alias: "n"},
+		{Func: "max", Prop: property{Table: "aggregate_test", Name: "mem_bytes"}, Alias: "max(aggregate_test.mem_bytes)"},
+	}
+	orders := table.parsePropSlice("~n,max(aggregate_test.mem_bytes),nodename")
+	assert.Equal(t, propSlice{
+		{Name: "n", Desc: true},
+		{Name: "max(aggregate_test.mem_bytes)"},
+		{Table: "aggregate_test", Name: "nodename"},
+	}, aggs.orders(orders, table.Name))
+}
+
+func TestAllowProperty(t *testing.T) {
+	table := Table{Name: "aggregate_test", Entry: aggregateTestEntry{}}
+	Register(&table)
+	secret := property{Table: "aggregate_test", Name: "mem_bytes"}
+	secretProps[secret] = true
+	defer delete(secretProps, secret)
+	newRequest := func() *request {
+		return &request{table: table, tx: &gorm.DB{Config: &gorm.Config{}}, filters: true}
+	}
+
+	rq := newRequest()
+	assert.True(t, rq.allowProperties(table.parsePropSlice("nodename,aggregate_test.id")))
+	assert.Nil(t, rq.tx.Error)
+
+	tests := map[string]func(*request){
+		"secret prop":               func(rq *request) { rq.withJoins(table.parsePropSlice("nodename,mem_bytes"), nil) },
+		"secret keyset":             func(rq *request) { rq.withJoins(nil, table.parsePropSlice("mem_bytes")) },
+		"secret filter":             func(rq *request) { rq.withFilters([]string{"aggregate_test.mem_bytes>1"}) },
+		"unqualified secret filter": func(rq *request) { rq.withFilters([]string{"mem_bytes>1"}) },
+		"secret group":              func(rq *request) { rq.withGroups(table.parsePropSlice("mem_bytes")) },
+		"secret order":              func(rq *request) { rq.withOrders(table.parsePropSlice("~mem_bytes")) },
+		"unknown order":             func(rq *request) { rq.withOrders(table.parsePropSlice("foo")) },
+	}
+	for testName, apply := range tests {
+		t.Logf("%s", testName)
+		rq := newRequest()
+		apply(rq)
+		assert.True(t, errors.Is(rq.tx.Error, ErrBadRequest))
+	}
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// cursor is the decoded value of the opaque "cursor" query parameter.
+	// It holds the keyset columns values of the row to start after, and
+	// the paging direction.
+	cursor struct {
+		Values   []interface{} `json:"v"`
+		Backward bool          `json:"b,omitempty"`
+	}
+)
+
+const (
+	// cursorColumnPrefix is the prefix of the alias of the keyset columns
+	// added to the selection to build the next and previous cursors.
+	cursorColumnPrefix = "__cursor_"
+
+	cursorTimeLayout = "2006-01-02 15:04:05.999999"
+)
+
+func decodeCursor(s string) (*cursor, error) {
+	c := &cursor{}
+	if s == "" {
+		return c, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor: %s", ErrBadRequest, err)
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor: %s", ErrBadRequest, err)
+	}
+	return c, nil
+}
+
+func (t cursor) String() string {
+	b, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// makeCursor returns the cursor pointing to the row, from the values of
+// the keyset columns selected in the row.
+func makeCursor(row map[string]interface{}, n int, backward bool) cursor {
+	c := cursor{
+		Values:   make([]interface{}, n),
+		Backward: backward,
+	}
+	for i := 0; i < n; i++ {
+		switch v := row[cursorColumn(i)].(type) {
+		case time.Time:
+			c.Values[i] = v.Format(cursorTimeLayout)
+		case []byte:
+			c.Values[i] = string(v)
+		default:
+			c.Values[i] = v
+		}
+	}
+	return c
+}
+
+func cursorColumn(i int) string {
+	return fmt.Sprintf("%s%d", cursorColumnPrefix, i)
+}
+
+// keysetProps returns the ordering properties completed with the table
+// primary key, so the keyset uniquely identifies a row.
+func (t *request) keysetProps(orders propSlice) propSlice {
+	pk := property{Table: t.table.Name, Name: "id"}
+	for _, prop := range orders {
+		if prop.Table == pk.Table && prop.Name == pk.Name {
+			return orders
+		}
+	}
+	l := make(propSlice, len(orders), len(orders)+1)
+	copy(l, orders)
+	return append(l, pk)
+}
+
+// reversed returns the properties with the ordering direction inverted.
+func (t propSlice) reversed() propSlice {
+	l := make(propSlice, len(t))
+	for i, prop := range t {
+		prop.Desc = !prop.Desc
+		l[i] = prop
+	}
+	return l
+}
+
+// keysetWhere returns the condition selecting the rows after the cursor
+// values in the keyset ordering:
+//
+//	(a > ?) OR (a = ? AND b > ?) OR (a = ? AND b = ? AND id > ?)
+//
+// NULL values are ordered first, as MySQL does.
+func keysetWhere(props propSlice, values []interface{}) (string, []interface{}, error) {
+	if len(values) != len(props) {
+		return "", nil, fmt.Errorf("%w: invalid cursor: %d values for %d ordering properties", ErrBadRequest, len(values), len(props))
+	}
+	ors := make([]string, 0)
+	args := make([]interface{}, 0)
+	for i, prop := range props {
+		ands := make([]string, 0)
+		for j := 0; j < i; j++ {
+			if values[j] == nil {
+				ands = append(ands, props[j].SQL()+" IS NULL")
+			} else {
+				ands = append(ands, props[j].SQL()+" = ?")
+				args = append(args, values[j])
+			}
+		}
+		switch {
+		case !prop.Desc && values[i] == nil:
+			ands = append(ands, prop.SQL()+" IS NOT NULL")
+		case !prop.Desc:
+			ands = append(ands, prop.SQL()+" > ?")
+			args = append(args, values[i])
+		case values[i] == nil:
+			ands = append(ands, "FALSE")
+		default:
+			ands = append(ands, fmt.Sprintf("(%s < ? OR %s IS NULL)", prop.SQL(), prop.SQL()))
+			args = append(args, values[i])
+		}
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return strings.Join(ors, " OR "), args, nil
+}
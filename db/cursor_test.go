@@ -0,0 +1,67 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetWhere(t *testing.T) {
+	tests := map[string]struct {
+		props  propSlice
+		values []interface{}
+		where  string
+		args   []interface{}
+		err    bool
+	}{
+		"ascending": {
+			props:  propSlice{{Table: "nodes", Name: "nodename"}, {Table: "nodes", Name: "id"}},
+			values: []interface{}{"n1", 4.0},
+			where:  "(`nodes`.`nodename` > ?) OR (`nodes`.`nodename` = ? AND `nodes`.`id` > ?)",
+			args:   []interface{}{"n1", "n1", 4.0},
+		},
+		"descending": {
+			props:  propSlice{{Table: "nodes", Name: "nodename", Desc: true}, {Table: "nodes", Name: "id"}},
+			values: []interface{}{"n1", 4.0},
+			where:  "((`nodes`.`nodename` < ? OR `nodes`.`nodename` IS NULL)) OR (`nodes`.`nodename` = ? AND `nodes`.`id` > ?)",
+			args:   []interface{}{"n1", "n1", 4.0},
+		},
+		"null value": {
+			props:  propSlice{{Table: "nodes", Name: "app"}, {Table: "nodes", Name: "id"}},
+			values: []interface{}{nil, 4.0},
+			where:  "(`nodes`.`app` IS NOT NULL) OR (`nodes`.`app` IS NULL AND `nodes`.`id` > ?)",
+			args:   []interface{}{4.0},
+		},
+		"values count mismatch": {
+			props:  propSlice{{Table: "nodes", Name: "id"}},
+			values: []interface{}{"n1", 4.0},
+			err:    true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		where, args, err := keysetWhere(test.props, test.values)
+		if test.err {
+			assert.True(t, errors.Is(err, ErrBadRequest))
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.where, where)
+		assert.Equal(t, test.args, args)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	c := cursor{Values: []interface{}{"n1", 4.0}, Backward: true}
+	decoded, err := decodeCursor(c.String())
+	assert.Nil(t, err)
+	assert.Equal(t, c, *decoded)
+
+	decoded, err = decodeCursor("")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(decoded.Values))
+
+	_, err = decodeCursor("!!")
+	assert.True(t, errors.Is(err, ErrBadRequest))
+}
@@ -14,6 +14,12 @@ import (
 	"gorm.io/gorm/logger"
 )
 
+const (
+	// defaultStringSize is the varchar size of string columns without
+	// explicit size.
+	defaultStringSize = 256
+)
+
 var (
 	db *gorm.DB
 )
@@ -70,7 +76,7 @@ func dbOpen() (*gorm.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/opensvc?charset=utf8&parseTime=True&loc=Local", user, pw, host, port)
 	return gorm.Open(mysql.New(mysql.Config{
 		DSN:               dsn,
-		DefaultStringSize: 256,
+		DefaultStringSize: defaultStringSize,
 		//DisableDatetimePrecision:  true,
 		//DontSupportRenameIndex:    true,
 		//DontSupportRenameColumn:   true,
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// facets is the number of rows per distinct value of properties,
+	// indexed by property name then by value.
+	facets map[string]map[string]int64
+)
+
+const (
+	defaultFacetsLimit = 10
+	maxFacetsLimit     = 100
+)
+
+func (t Table) queryFacets(r *http.Request) propSlice {
+	s := r.URL.Query().Get("facets")
+	return t.parsePropSlice(s)
+}
+
+// queryFacetsLimit returns the maximum number of values per facet, the
+// most frequent values first.
+func queryFacetsLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("facets_limit"))
+	switch {
+	case err != nil || limit <= 0:
+		return defaultFacetsLimit
+	case limit > maxFacetsLimit:
+		return maxFacetsLimit
+	default:
+		return limit
+	}
+}
+
+// clone returns a copy of the request, that can be further modified
+// without affecting the original request.
+func (t *request) clone() *request {
+	c := *t
+	c.tx = t.tx.Session(&gorm.Session{})
+	c.joined = make(joinedTables)
+	for k, v := range t.joined {
+		c.joined[k] = v
+	}
+	c.on = make(joinedTables)
+	for k, v := range t.on {
+		c.on[k] = v
+	}
+	return &c
+}
+
+// facets returns the number of table entries per value of each property,
+// limited to the most frequent values. The counts honour the conditions
+// already applied to the request, like the ACL and the filters.
+func (t *request) facets(props propSlice, limit int) (facets, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+	m := make(facets)
+	for _, prop := range props {
+		if _, err := checkProperty(t.table.Name, prop); err != nil {
+			return nil, err
+		}
+		rows := make([]map[string]interface{}, 0)
+		if err := t.facetQuery(prop, limit).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		values := make(map[string]int64)
+		for _, row := range rows {
+			n, _ := row["count"].(int64)
+			values[facetValue(row["value"])] = n
+		}
+		name := prop.Remap
+		if name == "" {
+			name = prop.String()
+		}
+		m[name] = values
+	}
+	return m, nil
+}
+
+// facetQuery returns the query counting the distinct table entries per
+// value of the property, the most frequent values first.
+func (t *request) facetQuery(prop property, limit int) *gorm.DB {
+	pk := property{Table: t.table.Name, Name: "id"}
+	q := t.clone()
+	q.AutoJoin(prop.Table)
+	return q.tx.
+		Select(fmt.Sprintf("%s AS `value`, COUNT(DISTINCT %s) AS `count`", prop.SQL(), pk.SQL())).
+		Group(prop.SQL()).
+		Order("`count` DESC").
+		Limit(limit)
+}
+
+// facetValue returns the string representation of a property value, used
+// as a facet key. NULL values are represented by an empty string.
+func facetValue(i interface{}) string {
+	switch v := i.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
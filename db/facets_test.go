@@ -0,0 +1,115 @@
+package db
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunDB replaces the database with a dry run session rendering the
+// queries without a server, until the returned function is called.
+func dryRunDB(t *testing.T) func() {
+	dry, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "test:test@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := db
+	db = dry
+	return func() { db = saved }
+}
+
+func TestQueryFacetsLimit(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected int
+	}{
+		"unset": {
+			query:    "",
+			expected: defaultFacetsLimit,
+		},
+		"set": {
+			query:    "facets_limit=5",
+			expected: 5,
+		},
+		"zero": {
+			query:    "facets_limit=0",
+			expected: defaultFacetsLimit,
+		},
+		"invalid": {
+			query:    "facets_limit=ten",
+			expected: defaultFacetsLimit,
+		},
+		"above max": {
+			query:    "facets_limit=1000",
+			expected: maxFacetsLimit,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		r := httptest.NewRequest("GET", "/svcdisks?"+test.query, nil)
+		assert.Equal(t, test.expected, queryFacetsLimit(r))
+	}
+}
+
+func TestFacetQuery(t *testing.T) {
+	defer dryRunDB(t)()
+	table := Table{Name: "svcdisks"}
+	tests := map[string]struct {
+		prop     property
+		limit    int
+		expected string
+	}{
+		"own property": {
+			prop:  property{Table: "svcdisks", Name: "disk_vendor"},
+			limit: 10,
+			expected: "SELECT `svcdisks`.`disk_vendor` AS `value`, COUNT(DISTINCT `svcdisks`.`id`) AS `count` " +
+				"FROM `svcdisks` " +
+				"GROUP BY `svcdisks`.`disk_vendor` ORDER BY `count` DESC LIMIT 10",
+		},
+		"joined property": {
+			prop:  property{Table: "diskinfo", Name: "disk_arrayid"},
+			limit: 3,
+			expected: "SELECT `diskinfo`.`disk_arrayid` AS `value`, COUNT(DISTINCT `svcdisks`.`id`) AS `count` " +
+				"FROM `svcdisks` LEFT JOIN `diskinfo` ON `svcdisks`.`disk_id`=`diskinfo`.`disk_id` " +
+				"GROUP BY `diskinfo`.`disk_arrayid` ORDER BY `count` DESC LIMIT 3",
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		rq := table.Request()
+		stmt := rq.facetQuery(test.prop, test.limit).Find(&[]map[string]interface{}{}).Statement
+		assert.Nil(t, stmt.Error)
+		assert.Equal(t, test.expected, stmt.SQL.String())
+	}
+}
+
+func TestFacetsDoNotAlterRequest(t *testing.T) {
+	defer dryRunDB(t)()
+	rq := Table{Name: "svcdisks"}.Request()
+	rq.Where("`svcdisks`.`disk_local` = ?", false)
+	rq.facetQuery(property{Table: "diskinfo", Name: "disk_arrayid"}, 10).Find(&[]map[string]interface{}{})
+	stmt := rq.tx.Find(&[]map[string]interface{}{}).Statement
+	assert.Equal(t, "SELECT * FROM `svcdisks` WHERE `svcdisks`.`disk_local` = ?", stmt.SQL.String())
+}
+
+func TestFacetValue(t *testing.T) {
+	tests := map[string]struct {
+		value    interface{}
+		expected string
+	}{
+		"nil":    {value: nil, expected: ""},
+		"bytes":  {value: []byte("abc"), expected: "abc"},
+		"int":    {value: int64(12), expected: "12"},
+		"string": {value: "abc", expected: "abc"},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, facetValue(test.value))
+	}
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"gorm.io/gorm"
+)
+
+type (
+	// filterset is a saved set of filters, props and ordering, as stored
+	// in the filtersets table.
+	filterset struct {
+		ID        uint
+		Name      string
+		TableName string
+		Filters   string
+		Props     string
+		Orderby   string
+	}
+
+	// lockedFilterset caches the user locked filterset lookup.
+	lockedFilterset struct {
+		resolved bool
+		fs       *filterset
+	}
+)
+
+// filters returns the filterset filters, with the unqualified properties
+// qualified with the filterset table, so they keep their meaning when
+// applied to a request on another table.
+func (t filterset) filters() ([]string, error) {
+	l := make([]string, 0)
+	if t.Filters == "" {
+		return l, nil
+	}
+	if err := json.Unmarshal([]byte(t.Filters), &l); err != nil {
+		return nil, fmt.Errorf("filterset %s: decode filters: %w", t.Name, err)
+	}
+	for i, s := range l {
+		m := reFilter.FindStringSubmatch(s)
+		if m == nil || strings.Contains(m[1], ".") {
+			continue
+		}
+		l[i] = strings.Replace(s, m[1], t.TableName+"."+m[1], 1)
+	}
+	return l, nil
+}
+
+// userID returns the user id, or false if authenticated as a node.
+func userID(user auth.Info) (int, bool) {
+	id, err := strconv.Atoi(user.GetID())
+	return id, err == nil
+}
+
+// getLockedFilterset returns the filterset assigned to the user, if the
+// user filter is locked.
+func getLockedFilterset(user auth.Info) (*filterset, error) {
+	id, ok := userID(user)
+	if !ok {
+		return nil, nil
+	}
+	fs := filterset{}
+	err := db.Table("filtersets").
+		Select("filtersets.id, filtersets.name, filtersets.table_name, filtersets.filters, filtersets.props, filtersets.orderby").
+		Joins("JOIN filterset_users ON filterset_users.filterset_id = filtersets.id").
+		Joins("JOIN auth_user ON auth_user.id = filterset_users.user_id").
+		Where("auth_user.id = ? AND auth_user.lock_filter = ?", id, "T").
+		Take(&fs).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &fs, nil
+}
+
+// getUserFilterset returns the filterset with the name, owned by the user
+// or shared with one of the user groups. The user's own filterset wins
+// over the shared ones.
+func getUserFilterset(user auth.Info, name string) (*filterset, error) {
+	id, ok := userID(user)
+	if !ok {
+		return nil, fmt.Errorf("%w: filterset %s: nodes have no filterset", ErrBadRequest, name)
+	}
+	fs := filterset{}
+	err := db.Table("filtersets").
+		Select("filtersets.id, filtersets.name, filtersets.table_name, filtersets.filters, filtersets.props, filtersets.orderby").
+		Where("filtersets.name = ?", name).
+		Where("filtersets.user_id = ? OR filtersets.id IN (?)", id, txSharedFiltersetIDs(id)).
+		Order(fmt.Sprintf("filtersets.user_id = %d DESC", id)).
+		Take(&fs).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: filterset %s not found", ErrBadRequest, name)
+	} else if err != nil {
+		return nil, err
+	}
+	return &fs, nil
+}
+
+// txSharedFiltersetIDs selects the ids of the filtersets shared with one
+// of the user groups.
+func txSharedFiltersetIDs(id int) *gorm.DB {
+	return db.Table("filterset_groups").
+		Joins("JOIN auth_membership ON auth_membership.group_id = filterset_groups.group_id").
+		Where("auth_membership.user_id = ?", id).
+		Select("filterset_groups.filterset_id")
+}
+
+// withFiltersetACL limits the filtersets to the ones owned by the user or
+// shared with one of the user groups.
+func (t *request) withFiltersetACL(user auth.Info) {
+	if authuser.IsManager(user) {
+		return
+	}
+	id, ok := userID(user)
+	if !ok {
+		// node auth
+		t.Where("filtersets.id < 0")
+		return
+	}
+	t.Where("filtersets.user_id = ? OR filtersets.id IN (?)", id, txSharedFiltersetIDs(id))
+}
+
+// get returns the filterset assigned to the user if the user filter is
+// locked, querying the database on the first call only.
+func (t *lockedFilterset) get(user auth.Info) (*filterset, error) {
+	if t.resolved {
+		return t.fs, nil
+	}
+	fs, err := getLockedFilterset(user)
+	if err != nil {
+		return nil, err
+	}
+	t.fs = fs
+	t.resolved = true
+	return fs, nil
+}
+
+// withLockedFilterset limits the request to the table entries matching
+// the filters of the user locked filterset.
+func (t *request) withLockedFilterset(user auth.Info) {
+	fs, err := t.locked.get(user)
+	if err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	if fs == nil {
+		return
+	}
+	t.withFiltersetScope(fs)
+}
+
+// withFiltersetScope limits the request to the table entries matching the
+// filterset filters. The filters are applied in a subquery, so the joins
+// they need do not duplicate the request rows.
+func (t *request) withFiltersetScope(fs *filterset) {
+	filters, err := fs.filters()
+	if err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	if len(filters) == 0 {
+		return
+	}
+	sub := t.table.Request(TableRequestWithACL(false))
+	sub.withFilters(filters)
+	if err := sub.tx.Error; err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	pk := property{Table: t.table.Name, Name: "id"}
+	sub.tx.Select(pk.SQL())
+	t.Where(pk.SQL()+" IN (?)", sub.tx)
+}
+
+// queryFilterset applies the filterset named by the "filterset" query
+// parameter, and returns the props and orders to use: the requested ones,
+// or the filterset ones if the request has none and the filterset is
+// saved for the request table.
+func (t *request) queryFilterset(r *http.Request, props, orders propSlice) (propSlice, propSlice, error) {
+	name := r.URL.Query().Get("filterset")
+	if name == "" || !t.filters {
+		return props, orders, nil
+	}
+	fs, err := getUserFilterset(auth.User(r), name)
+	if err != nil {
+		return nil, nil, err
+	}
+	t.withFiltersetScope(fs)
+	if fs.TableName != t.table.Name {
+		return props, orders, nil
+	}
+	if len(props) == 0 {
+		props = t.table.parsePropSlice(fs.Props)
+	}
+	if len(orders) == 0 {
+		orders = t.table.parsePropSlice(fs.Orderby)
+	}
+	return props, orders, nil
+}
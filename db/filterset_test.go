@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestFiltersetFilters(t *testing.T) {
+	tests := map[string]struct {
+		filters  string
+		expected []string
+		err      bool
+	}{
+		"no filters": {
+			filters:  "",
+			expected: []string{},
+		},
+		"unqualified props": {
+			filters:  `["node_env=PRD", "loc_city=Paris"]`,
+			expected: []string{"nodes.node_env=PRD", "nodes.loc_city=Paris"},
+		},
+		"qualified props": {
+			filters:  `["tags.tag_name=foo"]`,
+			expected: []string{"tags.tag_name=foo"},
+		},
+		"invalid json": {
+			filters: `node_env=PRD`,
+			err:     true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		fs := filterset{Name: "test", TableName: "nodes", Filters: test.filters}
+		filters, err := fs.filters()
+		if test.err {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, filters)
+	}
+}
+
+func TestLockedFiltersetResolvedOnce(t *testing.T) {
+	defer dryRunDB(t)()
+	for _, name := range []string{"nodes", "services"} {
+		if Tab(name) != nil {
+			continue
+		}
+		Register(&Table{Name: name})
+		defer delete(tables, name)
+	}
+	var lookups int
+	err := db.Callback().Query().After("gorm:query").Register("test:count_filtersets", func(tx *gorm.DB) {
+		if tx.Statement.Table == "filtersets" {
+			lookups++
+		}
+	})
+	assert.Nil(t, err)
+	user := auth.NewUserInfo("u1", "1", nil, nil)
+	tests := map[string]string{
+		"base acl":         "nodes",
+		"member nodes acl": "clusters",
+		"comp acl":         "comp_rulesets",
+	}
+	for testName, name := range tests {
+		t.Logf("%s", testName)
+		lookups = 0
+		table := Table{Name: name}
+		rq := table.Request()
+		rq.withACL(user)
+		rq.withACL(user)
+		assert.Nil(t, rq.tx.Error)
+		assert.Equal(t, 1, lookups)
+	}
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type (
+	// JoinGraph describes how the requests join a table to another: the
+	// joins between two tables, and the preferred routes through several
+	// joins when the shortest path is not the expected one.
+	JoinGraph struct {
+		Joins  []tableJoin  `json:"joins"`
+		Routes []tableRoute `json:"routes"`
+	}
+)
+
+var (
+	reTableAlias = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+	// aclFamilies are the tables read with an ACL of their own, or
+	// readable only through routes verifying more than the app ACL,
+	// indexed by the family of tables sharing the same ACL. The request
+	// properties can not join them, as the joined tables are not read
+	// with their ACL, except from a table of the same family. The auth_*
+	// tables form the "auth" family.
+	aclFamilies = map[string]string{
+		"filtersets":              "filtersets",
+		"filterset_groups":        "filtersets",
+		"filterset_users":         "filtersets",
+		"webhooks":                "webhooks",
+		"webhook_deliveries":      "webhooks",
+		"jobs":                    "jobs",
+		"comp_rulesets":           "comp_rulesets",
+		"comp_rulesets_variables": "comp_rulesets",
+		"comp_rulesets_tags":      "comp_rulesets",
+		"comp_moduleset":          "comp_moduleset",
+		"comp_moduleset_modules":  "comp_moduleset",
+		"comp_modulesets_tags":    "comp_moduleset",
+		"svc_configs":             "svc_configs",
+		"svc_config_keywords":     "svc_configs",
+		"dashboard_acks":          "dashboard_acks",
+	}
+)
+
+// Joins returns the join graph.
+func Joins() JoinGraph {
+	return JoinGraph{
+		Joins:  tableJoins,
+		Routes: tableRoutes,
+	}
+}
+
+// splitAlias splits a "table@alias" table reference. The alias is empty
+// if the reference has no "@".
+func splitAlias(s string) (string, string) {
+	l := strings.SplitN(s, "@", 2)
+	if len(l) == 2 {
+		return l[0], l[1]
+	}
+	return s, ""
+}
+
+// neighbours returns the tables having a join with the table, in the
+// tableJoins declaration order.
+func neighbours(table string) []string {
+	l := make([]string, 0)
+	for _, j := range tableJoins {
+		switch table {
+		case j.From:
+			l = append(l, j.To)
+		case j.To:
+			l = append(l, j.From)
+		}
+	}
+	return l
+}
+
+// shortestPath returns the tables to join, in order, to reach "to" from
+// "from" through the least joins. Among the shortest paths, the one with
+// the earliest declared joins wins, so the result is stable.
+func shortestPath(from, to string) ([]string, bool) {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		here := queue[0]
+		queue = queue[1:]
+		if here == to {
+			hops := make([]string, 0)
+			for s := to; s != from; s = prev[s] {
+				hops = append([]string{s}, hops...)
+			}
+			return hops, true
+		}
+		for _, there := range neighbours(here) {
+			if _, ok := prev[there]; ok {
+				continue
+			}
+			prev[there] = here
+			queue = append(queue, there)
+		}
+	}
+	return nil, false
+}
+
+// sqlAs returns the sql join clause of the "there" table, aliased to
+// thereAs, with the other table of the join aliased to hereAs.
+func (t tableJoin) sqlAs(there, hereAs, thereAs string) string {
+	fromAs, toAs := hereAs, thereAs
+	if there == t.From {
+		fromAs, toAs = thereAs, hereAs
+	}
+	cols := make([]string, len(t.Cols))
+	for i, col := range t.Cols {
+		cols[i] = fmt.Sprintf("`%s`.`%s`=`%s`.`%s`", fromAs, col[0], toAs, col[1])
+	}
+	if thereAs == there {
+		return fmt.Sprintf("LEFT JOIN `%s` ON %s", there, strings.Join(cols, " AND "))
+	}
+	return fmt.Sprintf("LEFT JOIN `%s` AS `%s` ON %s", there, thereAs, strings.Join(cols, " AND "))
+}
+
+// aclFamily returns the family of the tables sharing the ACL of the table,
+// or an empty string if the table is read with the app ACL.
+func aclFamily(table string) string {
+	if strings.HasPrefix(table, "auth_") {
+		return "auth"
+	}
+	return aclFamilies[table]
+}
+
+// checkJoin verifies the request properties can join the table to the
+// base table: the table and the intermediate tables of the route are read
+// with the app ACL, or share the base table ACL.
+func checkJoin(base, table string) error {
+	if table == base {
+		return nil
+	}
+	hops, err := getHops(base, table)
+	if err != nil {
+		return err
+	}
+	family := aclFamily(base)
+	for _, hop := range hops {
+		if f := aclFamily(hop); f != "" && f != family {
+			return fmt.Errorf("%w: table %s can not be joined to %s", ErrBadRequest, hop, base)
+		}
+	}
+	return nil
+}
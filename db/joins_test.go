@@ -0,0 +1,215 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHops(t *testing.T) {
+	tests := map[string]struct {
+		from, to string
+		hops     []string
+		err      bool
+	}{
+		"direct join": {
+			from: "svc_tags",
+			to:   "tags",
+			hops: []string{"tags"},
+		},
+		"shortest path": {
+			from: "svc_tags",
+			to:   "apps_publications",
+			hops: []string{"services", "apps", "apps_publications"},
+		},
+		"preferred route": {
+			from: "services",
+			to:   "nodes",
+			hops: []string{"svcmon", "nodes"},
+		},
+		"reversed preferred route": {
+			from: "auth_membership",
+			to:   "tags",
+			hops: []string{"apps_publications", "apps", "services", "svc_tags", "tags"},
+		},
+		"compliance status": {
+			from: "comp_status",
+			to:   "apps_publications",
+			hops: []string{"nodes", "apps", "apps_publications"},
+		},
+		"compliance service attachment": {
+			from: "comp_modulesets_services",
+			to:   "apps_responsibles",
+			hops: []string{"services", "apps", "apps_responsibles"},
+		},
+		"node ip": {
+			from: "nodes",
+			to:   "node_ip",
+			hops: []string{"node_ip"},
+		},
+		"disks acl through nodes": {
+			from: "svcdisks",
+			to:   "apps_publications",
+			hops: []string{"nodes", "apps", "apps_publications"},
+		},
+		"disk array info": {
+			from: "services",
+			to:   "diskinfo",
+			hops: []string{"svcdisks", "diskinfo"},
+		},
+		"cluster acl through nodes": {
+			from: "clusters",
+			to:   "apps_publications",
+			hops: []string{"nodes", "apps", "apps_publications"},
+		},
+		"cluster services": {
+			from: "clusters",
+			to:   "services",
+			hops: []string{"services"},
+		},
+		"actions acl through services": {
+			from: "svcactions",
+			to:   "apps_publications",
+			hops: []string{"services", "apps", "apps_publications"},
+		},
+		"actions nodes": {
+			from: "svcactions",
+			to:   "nodes",
+			hops: []string{"nodes"},
+		},
+		"no path": {
+			from: "nodes",
+			to:   "foo",
+			err:  true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		hops, err := getHops(test.from, test.to)
+		if test.err {
+			assert.True(t, errors.Is(err, ErrBadRequest))
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.hops, hops)
+	}
+}
+
+func TestJoinSQL(t *testing.T) {
+	j, _ := findJoin("nodes", "node_tags")
+	assert.Equal(t, "LEFT JOIN `node_tags` ON `nodes`.`node_id`=`node_tags`.`node_id`", j.SQL("node_tags"))
+	assert.Equal(t, "LEFT JOIN `nodes` ON `nodes`.`node_id`=`node_tags`.`node_id`", j.SQL("nodes"))
+	assert.Equal(t, "LEFT JOIN `node_tags` AS `x__node_tags` ON `nodes`.`node_id`=`x__node_tags`.`node_id`", j.sqlAs("node_tags", "nodes", "x__node_tags"))
+	assert.Equal(t, "`x`.`tag_name`", parseProperty("tags@x.tag_name", "nodes").SQL())
+}
+
+func TestCheckJoin(t *testing.T) {
+	tests := map[string]struct {
+		base, table string
+		err         bool
+	}{
+		"same table": {
+			base:  "auth_user",
+			table: "auth_user",
+		},
+		"app acl table": {
+			base:  "nodes",
+			table: "apps",
+		},
+		"app acl table through app acl tables": {
+			base:  "nodes",
+			table: "diskinfo",
+		},
+		"attachment table": {
+			base:  "nodes",
+			table: "comp_rulesets_nodes",
+		},
+		"same acl family": {
+			base:  "comp_rulesets",
+			table: "comp_rulesets_variables",
+		},
+		"auth family": {
+			base:  "auth_user",
+			table: "auth_group",
+		},
+		"users from nodes": {
+			base:  "nodes",
+			table: "auth_user",
+			err:   true,
+		},
+		"memberships from tags": {
+			base:  "tags",
+			table: "auth_membership",
+			err:   true,
+		},
+		"service configs from nodes": {
+			base:  "nodes",
+			table: "svc_configs",
+			err:   true,
+		},
+		"service config keywords from services": {
+			base:  "services",
+			table: "svc_config_keywords",
+			err:   true,
+		},
+		"ruleset variables from nodes": {
+			base:  "nodes",
+			table: "comp_rulesets_variables",
+			err:   true,
+		},
+		"dashboard acks from services": {
+			base:  "services",
+			table: "dashboard_acks",
+			err:   true,
+		},
+		"modulesets from rulesets": {
+			base:  "comp_rulesets",
+			table: "comp_moduleset",
+			err:   true,
+		},
+		"no path": {
+			base:  "nodes",
+			table: "jobs",
+			err:   true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		err := checkJoin(test.base, test.table)
+		if test.err {
+			assert.True(t, errors.Is(err, ErrBadRequest), "expected a bad request error, got %v", err)
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
+
+func TestAllowJoinedProperty(t *testing.T) {
+	defer dryRunDB(t)()
+	type authUserTestEntry struct {
+		ID    uint   `gorm:"primarykey" json:"id"`
+		Email string `gorm:"column:email" json:"email"`
+	}
+	type nodeTestEntry struct {
+		ID  uint   `gorm:"primarykey" json:"id"`
+		App string `gorm:"column:app" json:"app"`
+	}
+	for _, table := range []*Table{
+		{Name: "auth_user", Entry: authUserTestEntry{}},
+		{Name: "apps", Entry: nodeTestEntry{}},
+	} {
+		if _, ok := tables[table.Name]; ok {
+			continue
+		}
+		Register(table)
+		defer delete(tables, table.Name)
+	}
+	rq := Table{Name: "nodes"}.Request()
+	assert.True(t, rq.allowProperty(property{Table: "apps", Name: "app"}))
+	assert.Nil(t, rq.tx.Error)
+
+	rq = Table{Name: "nodes"}.Request()
+	assert.False(t, rq.allowProperty(property{Table: "auth_user", Name: "email"}))
+	assert.True(t, errors.Is(rq.tx.Error, ErrBadRequest))
+}
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type (
+	// Query is a table request described by a json document, with
+	// properties and filters from any table joinable to the base table.
+	// The tables read with an ACL of their own are not joinable, as only
+	// the base table ACL is applied.
+	//
+	// The properties use the same syntax as the query parameters of the
+	// table routes: "table.name:remap" to select, "~table.name" to order
+	// in descending order.
+	Query struct {
+		Table     string       `json:"table" example:"services"`
+		Props     []string     `json:"props,omitempty" example:"services.svcname,nodes.nodename"`
+		Filter    *QueryFilter `json:"filter,omitempty"`
+		Order     []string     `json:"order,omitempty" example:"services.svcname"`
+		Group     []string     `json:"group,omitempty"`
+		Aggregate []string     `json:"aggregate,omitempty" example:"count(*):n"`
+		Having    []string     `json:"having,omitempty" example:"n>1"`
+		Distinct  bool         `json:"distinct,omitempty"`
+		Limit     int          `json:"limit,omitempty" example:"20"`
+		Offset    int          `json:"offset,omitempty"`
+	}
+
+	// QueryFilter is a node of a query filter tree. It is either a
+	// combination of child filters, with exactly one of And, Or or Not
+	// set, or a property condition:
+	//
+	//	{"prop": "nodes.node_env", "op": "=", "value": "PRD"}
+	//
+	// The supported operators are =, !=, <, <=, >, >=, like, in and null.
+	// The "in" operator expects a list value, the "null" operator no
+	// value.
+	QueryFilter struct {
+		And   []QueryFilter `json:"and,omitempty"`
+		Or    []QueryFilter `json:"or,omitempty"`
+		Not   *QueryFilter  `json:"not,omitempty"`
+		Prop  string        `json:"prop,omitempty"`
+		Op    string        `json:"op,omitempty"`
+		Value interface{}   `json:"value,omitempty" swaggertype:"string"`
+	}
+)
+
+var (
+	// queryTables are the tables a Query can use as base table. Their
+	// read ACL is applied to the query.
+	queryTables = map[string]bool{
+		"nodes":     true,
+		"services":  true,
+		"tags":      true,
+		"node_tags": true,
+		"svc_tags":  true,
+		"apps":      true,
+	}
+)
+
+// MakeTableResponse executes the query with the read ACL of the user.
+func (t Query) MakeTableResponse(user auth.Info) (*TableResponse, error) {
+	table := Tab(t.Table)
+	if table == nil || !queryTables[t.Table] {
+		return nil, fmt.Errorf("%w: table %s can not be queried", ErrBadRequest, t.Table)
+	}
+	rq := table.Request()
+
+	props, err := table.checkedPropSlice(t.Props)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := table.checkedPropSlice(t.Group)
+	if err != nil {
+		return nil, err
+	}
+	aggs, err := table.parseAggregates(strings.Join(t.Aggregate, ","))
+	if err != nil {
+		return nil, err
+	}
+	orders := aggs.orders(table.parsePropSlice(strings.Join(t.Order, ",")), table.Name)
+	rq.aclSubquery = len(aggs) > 0
+	rq.withACL(user)
+
+	// filters
+	if t.Filter != nil {
+		where, args, err := rq.filterSQL(*t.Filter)
+		if err != nil {
+			return nil, err
+		}
+		rq.Where(where, args...)
+	}
+
+	if err := rq.withSelection(props, groups, nil, aggs, t.Having); err != nil {
+		return nil, err
+	}
+	if t.Distinct {
+		rq.tx = rq.tx.Distinct()
+	}
+
+	var total int64
+	if err := rq.count(&total, len(aggs) > 0 || t.Distinct); err != nil {
+		return nil, err
+	}
+
+	// ordering and paging
+	limit := t.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	rq.withOrders(orders)
+	rq.withPaging(t.Offset, limit)
+
+	data := make([]map[string]interface{}, 0)
+	if err := rq.tx.Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return &TableResponse{
+		Data: data,
+		Meta: &tableResponseMeta{
+			Total:          total,
+			Offset:         t.Offset,
+			Limit:          limit,
+			Count:          len(data),
+			AvailableProps: availProps(props),
+			IncludedProps:  props,
+		},
+	}, nil
+}
+
+// checkedPropSlice parses the properties, and verifies they are columns
+// of registered tables.
+func (t Table) checkedPropSlice(l []string) (propSlice, error) {
+	props := t.parsePropSlice(strings.Join(l, ","))
+	for _, prop := range props {
+		if _, err := checkProperty(t.Name, prop); err != nil {
+			return nil, err
+		}
+	}
+	return props, nil
+}
+
+// filterSQL returns the sql condition and arguments of the filter tree,
+// and joins the tables of the filtered properties.
+func (t *request) filterSQL(f QueryFilter) (string, []interface{}, error) {
+	n := 0
+	for _, set := range []bool{len(f.And) > 0, len(f.Or) > 0, f.Not != nil, f.Prop != ""} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return "", nil, fmt.Errorf("%w: a filter must have exactly one of and, or, not or prop", ErrBadRequest)
+	}
+	switch {
+	case len(f.And) > 0:
+		return t.filtersSQL(f.And, " AND ")
+	case len(f.Or) > 0:
+		return t.filtersSQL(f.Or, " OR ")
+	case f.Not != nil:
+		s, args, err := t.filterSQL(*f.Not)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + s + ")", args, nil
+	}
+
+	prop := t.table.parseProperty(f.Prop)
+	if _, err := checkProperty(t.table.Name, prop); err != nil {
+		return "", nil, err
+	}
+	t.AutoJoin(prop.Table)
+	op := strings.ToLower(f.Op)
+	switch op {
+	case "null":
+		return prop.SQL() + " IS NULL", nil, nil
+	case "in":
+		l, ok := f.Value.([]interface{})
+		if !ok || len(l) == 0 {
+			return "", nil, fmt.Errorf("%w: filter %s %s: value must be a non-empty list", ErrBadRequest, f.Prop, f.Op)
+		}
+		return prop.SQL() + " IN ?", []interface{}{l}, nil
+	case "", "=", "!=", "<", "<=", ">", ">=", "like":
+		switch f.Value.(type) {
+		case string, float64, bool:
+		default:
+			return "", nil, fmt.Errorf("%w: filter %s %s: value must be a string, a number or a boolean", ErrBadRequest, f.Prop, f.Op)
+		}
+		switch op {
+		case "":
+			op = "="
+		case "!=":
+			op = "<>"
+		case "like":
+			op = "LIKE"
+		}
+		return fmt.Sprintf("%s %s ?", prop.SQL(), op), []interface{}{f.Value}, nil
+	default:
+		return "", nil, fmt.Errorf("%w: filter %s: unsupported operator %s", ErrBadRequest, f.Prop, f.Op)
+	}
+}
+
+func (t *request) filtersSQL(filters []QueryFilter, sep string) (string, []interface{}, error) {
+	l := make([]string, len(filters))
+	args := make([]interface{}, 0)
+	for i, f := range filters {
+		s, a, err := t.filterSQL(f)
+		if err != nil {
+			return "", nil, err
+		}
+		l[i] = "(" + s + ")"
+		args = append(args, a...)
+	}
+	return strings.Join(l, sep), args, nil
+}
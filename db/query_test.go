@@ -0,0 +1,124 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+type queryTestEntry struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	Nodename string `gorm:"column:nodename" json:"nodename"`
+	NodeEnv  string `gorm:"column:node_env" json:"node_env"`
+	MemBytes int    `gorm:"column:mem_bytes" json:"mem_bytes"`
+}
+
+func TestFilterSQL(t *testing.T) {
+	table := Table{Name: "query_test", Entry: queryTestEntry{}}
+	Register(&table)
+	rq := &request{table: table}
+	tests := map[string]struct {
+		filter QueryFilter
+		where  string
+		args   []interface{}
+		err    bool
+	}{
+		"equal": {
+			filter: QueryFilter{Prop: "node_env", Value: "PRD"},
+			where:  "`query_test`.`node_env` = ?",
+			args:   []interface{}{"PRD"},
+		},
+		"tree": {
+			filter: QueryFilter{
+				And: []QueryFilter{
+					{Prop: "query_test.node_env", Op: "in", Value: []interface{}{"PRD", "DRP"}},
+					{Not: &QueryFilter{
+						Or: []QueryFilter{
+							{Prop: "nodename", Op: "like", Value: "test%"},
+							{Prop: "mem_bytes", Op: "null"},
+						},
+					}},
+				},
+			},
+			where: "(`query_test`.`node_env` IN ?) AND (NOT ((`query_test`.`nodename` LIKE ?) OR (`query_test`.`mem_bytes` IS NULL)))",
+			args:  []interface{}{[]interface{}{"PRD", "DRP"}, "test%"},
+		},
+		"unknown property": {
+			filter: QueryFilter{Prop: "foo", Value: "bar"},
+			err:    true,
+		},
+		"unsupported operator": {
+			filter: QueryFilter{Prop: "nodename", Op: "regexp", Value: "bar"},
+			err:    true,
+		},
+		"empty in list": {
+			filter: QueryFilter{Prop: "nodename", Op: "in", Value: []interface{}{}},
+			err:    true,
+		},
+		"object value": {
+			filter: QueryFilter{Prop: "nodename", Value: map[string]interface{}{}},
+			err:    true,
+		},
+		"ambiguous node": {
+			filter: QueryFilter{Prop: "nodename", Value: "n1", Not: &QueryFilter{Prop: "nodename", Value: "n2"}},
+			err:    true,
+		},
+		"empty node": {
+			filter: QueryFilter{},
+			err:    true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		where, args, err := rq.filterSQL(test.filter)
+		if test.err {
+			assert.True(t, errors.Is(err, ErrBadRequest))
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.where, where)
+		assert.Equal(t, test.args, args)
+	}
+}
+
+func TestQueryJoinedTables(t *testing.T) {
+	defer dryRunDB(t)()
+	for _, table := range []*Table{
+		{Name: "nodes", Entry: queryTestEntry{}},
+		{Name: "svc_configs", Entry: queryTestEntry{}},
+		{Name: "auth_user", Entry: queryTestEntry{}},
+	} {
+		if _, ok := tables[table.Name]; ok {
+			continue
+		}
+		Register(table)
+		defer delete(tables, table.Name)
+	}
+	user := auth.NewUserInfo("u1", "1", nil, nil)
+	tests := map[string]Query{
+		"user property": {
+			Table: "nodes",
+			Props: []string{"nodes.nodename", "auth_user.nodename"},
+		},
+		"user group": {
+			Table:     "nodes",
+			Group:     []string{"auth_user.nodename"},
+			Aggregate: []string{"count(*):n"},
+		},
+		"user aggregate": {
+			Table:     "nodes",
+			Aggregate: []string{"max(auth_user.nodename)"},
+		},
+		"service config filter": {
+			Table:  "nodes",
+			Filter: &QueryFilter{Prop: "svc_configs.node_env", Value: "PRD"},
+		},
+	}
+	for testName, q := range tests {
+		t.Logf("%s", testName)
+		_, err := q.MakeTableResponse(user)
+		assert.True(t, errors.Is(err, ErrBadRequest), "expected a bad request error, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+// Readable returns true if the table entry with the id is readable by the
+// user and matches the filters.
+func (t Table) Readable(user auth.Info, id uint, filters []string) (bool, error) {
+	rq := t.Request(TableRequestWithPaging(false))
+	rq.withACL(user)
+	return rq.matches(id, filters)
+}
+
+// Matches returns true if the table entry with the id matches the filters,
+// regardless of the read ACL.
+func (t Table) Matches(id uint, filters []string) (bool, error) {
+	rq := t.Request(TableRequestWithPaging(false), TableRequestWithACL(false))
+	return rq.matches(id, filters)
+}
+
+func (t *request) matches(id uint, filters []string) (bool, error) {
+	var i int64
+	t.withFilters(filters)
+	t.Where(property{Table: t.table.Name, Name: "id"}.SQL()+" = ?", id)
+	if err := t.tx.Count(&i).Error; err != nil {
+		return false, err
+	}
+	return i > 0, nil
+}
+
+// ReadableIDs returns the ids, among the given ones, of the table entries
+// readable by the user and matching the filters, in a single query.
+func (t Table) ReadableIDs(user auth.Info, ids []uint, filters []string) (map[uint]bool, error) {
+	m := make(map[uint]bool)
+	if len(ids) == 0 {
+		return m, nil
+	}
+	rq := t.Request(TableRequestWithPaging(false))
+	rq.withACL(user)
+	rq.withFilters(filters)
+	pk := property{Table: t.Name, Name: "id"}.SQL()
+	rq.Where(pk+" IN (?)", ids)
+	l := make([]uint, 0)
+	if err := rq.tx.Distinct(pk).Pluck(pk, &l).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range l {
+		m[id] = true
+	}
+	return m, nil
+}
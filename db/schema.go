@@ -0,0 +1,87 @@
+package db
+
+import (
+	"sort"
+
+	"gorm.io/gorm/schema"
+)
+
+type (
+	// TableSchema describes a registered table: its properties and the
+	// tables it has a join with.
+	TableSchema struct {
+		Name       string           `json:"name" example:"nodes"`
+		Props      []PropertySchema `json:"props"`
+		Neighbours []string         `json:"neighbours" example:"node_tags,svcmon,apps"`
+	}
+
+	// PropertySchema describes a table property, as usable in the table
+	// requests parameters and in the create and update payloads.
+	PropertySchema struct {
+		Name       string   `json:"name" example:"node_env"`
+		Type       string   `json:"type" example:"string"`
+		Nullable   bool     `json:"nullable"`
+		Size       int      `json:"size,omitempty" example:"256"`
+		Enum       []string `json:"enum,omitempty"`
+		Required   bool     `json:"required"`
+		Filterable bool     `json:"filterable"`
+		Sortable   bool     `json:"sortable"`
+		Writable   bool     `json:"writable"`
+	}
+)
+
+// Schema returns the description of the registered tables, sorted by name.
+func Schema() ([]TableSchema, error) {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	l := make([]TableSchema, len(names))
+	for i, name := range names {
+		s, err := tables[name].Schema()
+		if err != nil {
+			return nil, err
+		}
+		l[i] = s
+	}
+	return l, nil
+}
+
+// Schema returns the description of the table, with the properties in the
+// table entry fields order. The secret properties can not be used in the
+// requests parameters, so they are neither filterable nor sortable.
+func (t Table) Schema() (TableSchema, error) {
+	s, err := schema.Parse(t.Entry, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return TableSchema{}, err
+	}
+	rules, err := t.fieldRules()
+	if err != nil {
+		return TableSchema{}, err
+	}
+	ts := TableSchema{
+		Name:       t.Name,
+		Props:      make([]PropertySchema, 0),
+		Neighbours: neighbours(t.Name),
+	}
+	for _, f := range s.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		secret := secretProps[property{Table: t.Name, Name: f.DBName}]
+		rule := rules[f.DBName]
+		ts.Props = append(ts.Props, PropertySchema{
+			Name:       rule.Name,
+			Type:       string(rule.Type),
+			Nullable:   rule.Nullable,
+			Size:       rule.Size,
+			Enum:       rule.Enum,
+			Required:   rule.Required,
+			Filterable: !secret,
+			Sortable:   !secret && rule.Sortable,
+			Writable:   !rule.ReadOnly,
+		})
+	}
+	return ts, nil
+}
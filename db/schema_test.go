@@ -0,0 +1,96 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+type schemaTestEntry struct {
+	ID      uint           `gorm:"primarykey" json:"id"`
+	Name    string         `gorm:"column:name; size:8" json:"name"`
+	Log     string         `gorm:"column:log; type:text" json:"log"`
+	LongLog string         `gorm:"column:long_log; type:longtext" json:"long_log"`
+	Raw     []byte         `gorm:"column:raw" json:"raw"`
+	Dict    datatypes.JSON `gorm:"column:dict" json:"dict"`
+}
+
+func TestTableSchema(t *testing.T) {
+	table := Table{Name: "schema_test", Entry: validateTestEntry{}}
+	s, err := table.Schema()
+	assert.Nil(t, err)
+	assert.Equal(t, "schema_test", s.Name)
+	props := make(map[string]PropertySchema)
+	names := make([]string, len(s.Props))
+	for i, prop := range s.Props {
+		props[prop.Name] = prop
+		names[i] = prop.Name
+	}
+	assert.Equal(t, []string{"id", "created_at", "name", "env", "level", "email", "count", "hash"}, names)
+	assert.Equal(t, PropertySchema{Name: "id", Type: "uint", Filterable: true, Sortable: true}, props["id"])
+	assert.Equal(t, PropertySchema{Name: "name", Type: "string", Nullable: true, Size: 8, Required: true, Filterable: true, Sortable: true, Writable: true}, props["name"])
+	assert.Equal(t, []string{"info", "error"}, props["level"].Enum)
+	assert.False(t, props["hash"].Writable)
+}
+
+func TestTableSchemaSortable(t *testing.T) {
+	table := Table{Name: "schema_sort_test", Entry: schemaTestEntry{}}
+	s, err := table.Schema()
+	assert.Nil(t, err)
+	expected := map[string]bool{
+		"id":       true,
+		"name":     true,
+		"log":      false,
+		"long_log": false,
+		"raw":      false,
+		"dict":     false,
+	}
+	for _, prop := range s.Props {
+		t.Logf("%s", prop.Name)
+		assert.True(t, prop.Filterable)
+		assert.Equal(t, expected[prop.Name], prop.Sortable)
+	}
+}
+
+func TestTableSchemaSecret(t *testing.T) {
+	secret := property{Table: "schema_test", Name: "hash"}
+	secretProps[secret] = true
+	defer delete(secretProps, secret)
+	table := Table{Name: "schema_test", Entry: validateTestEntry{}}
+	s, err := table.Schema()
+	assert.Nil(t, err)
+	for _, prop := range s.Props {
+		t.Logf("%s", prop.Name)
+		secret := prop.Name == "hash"
+		assert.Equal(t, !secret, prop.Filterable)
+		assert.Equal(t, !secret, prop.Sortable)
+	}
+}
+
+func TestAllowSortProperty(t *testing.T) {
+	defer dryRunDB(t)()
+	table := Table{Name: "schema_sort_test", Entry: schemaTestEntry{}}
+	Register(&table)
+	defer delete(tables, table.Name)
+	tests := map[string]struct {
+		prop string
+		err  bool
+	}{
+		"string": {prop: "name"},
+		"text":   {prop: "log", err: true},
+		"blob":   {prop: "raw", err: true},
+		"json":   {prop: "dict", err: true},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		rq := table.Request()
+		rq.withOrders(propSlice{table.parseProperty(test.prop)})
+		if test.err {
+			assert.True(t, errors.Is(rq.tx.Error, ErrBadRequest), "expected a bad request error, got %v", rq.tx.Error)
+		} else {
+			assert.Nil(t, rq.tx.Error)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"gorm.io/gorm/schema"
+)
+
+type (
+	// TableSearch makes a registered table searchable. The searched
+	// columns are the columns of the table entry FULLTEXT index, declared
+	// with a "index:<name>,class:FULLTEXT" gorm tag.
+	TableSearch struct {
+		// Type is the type of the hits in this table, ie "node".
+		Type string
+
+		// Name is the column naming the hits, ie "nodename".
+		Name string
+
+		// Link is the format of the canonical route of a hit, with the
+		// entry id as the only verb, ie "/api/nodes/%d".
+		Link string
+	}
+
+	// SearchHit is a table entry matching the searched words.
+	SearchHit struct {
+		Type  string  `json:"type" example:"node"`
+		ID    int64   `json:"id" example:"12"`
+		Name  string  `json:"name" example:"node1"`
+		Score float64 `json:"score" example:"3.1"`
+		Link  string  `json:"link" example:"/api/nodes/12"`
+	}
+
+	SearchResponse struct {
+		Data []SearchHit        `json:"data"`
+		Meta searchResponseMeta `json:"meta"`
+	}
+	searchResponseMeta struct {
+		Query string   `json:"q"`
+		Types []string `json:"types"`
+		Limit int      `json:"limit"`
+		Count int      `json:"count"`
+	}
+)
+
+const (
+	maxSearchLimit = 100
+)
+
+// searchTypes returns the searchable tables indexed by hit type.
+func searchTypes() map[string]*Table {
+	m := make(map[string]*Table)
+	for _, t := range tables {
+		if t.Search != nil {
+			m[t.Search.Type] = t
+		}
+	}
+	return m
+}
+
+// searchColumns returns the columns of the table FULLTEXT index.
+func (t Table) searchColumns() ([]string, error) {
+	s, err := schema.Parse(t.Entry, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range s.ParseIndexes() {
+		if !strings.EqualFold(idx.Class, "FULLTEXT") {
+			continue
+		}
+		l := make([]string, len(idx.Fields))
+		for i, f := range idx.Fields {
+			l[i] = f.DBName
+		}
+		return l, nil
+	}
+	return nil, fmt.Errorf("table %s has no FULLTEXT index", t.Name)
+}
+
+// booleanQuery converts the searched words to a MySQL boolean mode
+// full-text query, requiring all the words, each as a prefix. The boolean
+// mode operators in the words are discarded.
+func booleanQuery(q string) string {
+	l := make([]string, 0)
+	for _, word := range strings.Fields(q) {
+		word = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`+-<>()~*"@`, r) {
+				return -1
+			}
+			return r
+		}, word)
+		if word == "" {
+			continue
+		}
+		l = append(l, "+"+word+"*")
+	}
+	return strings.Join(l, " ")
+}
+
+// querySearchTypes returns the hit types to search, from the "types" query
+// parameter. All the searchable types are returned if the parameter is
+// not set.
+func querySearchTypes(r *http.Request) ([]string, error) {
+	m := searchTypes()
+	l := make([]string, 0)
+	if s := r.URL.Query().Get("types"); s != "" {
+		for _, typ := range strings.Split(s, ",") {
+			if _, ok := m[typ]; !ok {
+				return nil, fmt.Errorf("%w: types: unknown type %s", ErrBadRequest, typ)
+			}
+			l = append(l, typ)
+		}
+	} else {
+		for typ := range m {
+			l = append(l, typ)
+		}
+	}
+	sort.Strings(l)
+	return l, nil
+}
+
+func querySearchLimit(r *http.Request) int {
+	limit := queryLimit(r)
+	if limit <= 0 || limit > maxSearchLimit {
+		return maxSearchLimit
+	}
+	return limit
+}
+
+// Search returns the entries of the searchable tables matching all the
+// words of the "q" query parameter, most relevant first. Each table read
+// ACL applies, so the hits are limited to the entries the user can read.
+func Search(r *http.Request) (*SearchResponse, error) {
+	q := r.URL.Query().Get("q")
+	bq := booleanQuery(q)
+	if bq == "" {
+		return nil, fmt.Errorf("%w: q: no word to search", ErrBadRequest)
+	}
+	types, err := querySearchTypes(r)
+	if err != nil {
+		return nil, err
+	}
+	limit := querySearchLimit(r)
+	user := auth.User(r)
+	m := searchTypes()
+	hits := make([]SearchHit, 0)
+	for _, typ := range types {
+		l, err := m[typ].search(user, bq, limit)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, l...)
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return &SearchResponse{
+		Data: hits,
+		Meta: searchResponseMeta{
+			Query: q,
+			Types: types,
+			Limit: limit,
+			Count: len(hits),
+		},
+	}, nil
+}
+
+// search returns the table entries readable by the user and matching the
+// boolean mode full-text query, most relevant first.
+func (t Table) search(user auth.Info, bq string, limit int) ([]SearchHit, error) {
+	cols, err := t.searchColumns()
+	if err != nil {
+		return nil, err
+	}
+	for i, col := range cols {
+		cols[i] = property{Table: t.Name, Name: col}.SQL()
+	}
+	match := fmt.Sprintf("MATCH (%s) AGAINST (? IN BOOLEAN MODE)", strings.Join(cols, ","))
+	id := property{Table: t.Name, Name: "id"}
+	name := property{Table: t.Name, Name: t.Search.Name}
+	rq := t.Request(TableRequestWithFilters(false), TableRequestWithPaging(false))
+	rq.withACL(user)
+	rq.tx.Select(fmt.Sprintf("DISTINCT %s AS id, %s AS name, %s AS score", id.SQL(), name.SQL(), match), bq)
+	rq.Where(match, bq)
+	rq.tx.Order("score DESC").Limit(limit)
+	hits := make([]SearchHit, 0)
+	if err := rq.tx.Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	for i := range hits {
+		hits[i].Type = t.Search.Type
+		hits[i].Link = fmt.Sprintf(t.Search.Link, hits[i].ID)
+	}
+	return hits, nil
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type searchTestEntry struct {
+	ID       uint   `gorm:"primarykey" json:"id"`
+	Nodename string `gorm:"column:nodename; index; index:idx_search_test,class:FULLTEXT" json:"nodename"`
+	MemBytes int    `gorm:"column:mem_bytes" json:"mem_bytes"`
+	LocCity  string `gorm:"column:loc_city; index:idx_search_test,class:FULLTEXT" json:"loc_city"`
+}
+
+func TestBooleanQuery(t *testing.T) {
+	tests := map[string]struct {
+		input  string
+		output string
+	}{
+		"empty": {
+			input:  "  ",
+			output: "",
+		},
+		"words": {
+			input:  "node paris",
+			output: "+node* +paris*",
+		},
+		"operators discarded": {
+			input:  `-node "paris" *`,
+			output: "+node* +paris*",
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.output, booleanQuery(test.input))
+	}
+}
+
+func TestSearchColumns(t *testing.T) {
+	table := Table{Name: "search_test", Entry: searchTestEntry{}}
+	cols, err := table.searchColumns()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nodename", "loc_city"}, cols)
+
+	table = Table{Name: "aggregate_test", Entry: aggregateTestEntry{}}
+	_, err = table.searchColumns()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type (
+	// RowWriter is the interface of the table response encoders fed by
+	// StreamTableResponse.
+	RowWriter interface {
+		// WriteHeader is called once, with the selected column names,
+		// before the first WriteRow.
+		WriteHeader(columns []string) error
+
+		// WriteRow is called for each row, with the column values in
+		// the header order.
+		WriteRow(values []interface{}) error
+	}
+)
+
+// StreamTableResponse writes the table rows selected by the request query
+// parameters to w, reading them one at a time from the database cursor
+// instead of loading the whole result set in memory.
+//
+// The props, filters, groupby, aggregate, having and order parameters are
+// honoured like in MakeTableResponse, but the rows are not paged unless the
+// limit parameter is set.
+func (t *request) StreamTableResponse(r *http.Request, w RowWriter) error {
+	if _, ok := queryCursor(r); ok {
+		return fmt.Errorf("%w: cursor paging is not supported by streamed responses", ErrBadRequest)
+	}
+
+	props := t.table.queryProps(r)
+	groups := t.table.queryGroups(r)
+	orders := t.table.queryOrders(r)
+	aggs, err := t.table.queryAggregates(r)
+	if err != nil {
+		return err
+	}
+
+	user := auth.User(r)
+	t.aclSubquery = len(aggs) > 0
+	t.withACL(user)
+	if props, orders, err = t.queryFilterset(r, props, orders); err != nil {
+		return err
+	}
+
+	// filters
+	filters := queryFilters(r)
+	t.withFilters(filters)
+
+	if err := t.withSelection(props, groups, nil, aggs, queryHaving(r)); err != nil {
+		return err
+	}
+
+	// ordering
+	t.withOrders(aggs.orders(orders, t.table.Name))
+
+	// paging, only if explicitly requested
+	if r.URL.Query().Get("limit") != "" {
+		t.withPaging(queryOffset(r), queryLimit(r))
+	}
+
+	rows, err := t.tx.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(columns); err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		if err := w.WriteRow(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
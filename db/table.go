@@ -3,8 +3,8 @@ package db
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"reflect"
 	"regexp"
@@ -20,16 +20,19 @@ import (
 
 type (
 	tableRoute struct {
-		From, To string
-		Via      []string
+		From string   `json:"from"`
+		To   string   `json:"to"`
+		Via  []string `json:"via"`
 	}
 	tableJoin struct {
-		From, To string
-		Cols     [][]string
+		From string     `json:"from"`
+		To   string     `json:"to"`
+		Cols [][]string `json:"cols"`
 	}
 	Table struct {
 		Name    string
 		Entry   interface{}
+		Search  *TableSearch
 		propMap propMapping
 	}
 	TableResponse struct {
@@ -45,9 +48,13 @@ type (
 	tableResponseData []map[string]interface{}
 	tableResponseMeta struct {
 		Total          int64     `json:"total"`
+		TotalApprox    bool      `json:"total_approx,omitempty"`
 		Offset         int       `json:"offset"`
 		Limit          int       `json:"limit"`
 		Count          int       `json:"count"`
+		NextCursor     string    `json:"next_cursor,omitempty"`
+		PrevCursor     string    `json:"prev_cursor,omitempty"`
+		Facets         facets    `json:"facets,omitempty"`
 		AvailableProps propSlice `json:"available_props"`
 		IncludedProps  propSlice `json:"included_props"`
 	}
@@ -57,6 +64,7 @@ type (
 	request      struct {
 		acl               bool
 		writeIntent       bool
+		aclSubquery       bool
 		filters           bool
 		paging            bool
 		validFiltersCount uint
@@ -64,13 +72,29 @@ type (
 		table             Table
 		joined            joinedTables
 		on                joinedTables
+
+		// locked is the user locked filterset, resolved once and shared
+		// with the ACL subrequests.
+		locked *lockedFilterset
 	}
 )
 
+const (
+	defaultLimit = 20
+)
+
 var (
+	// ErrBadRequest is wrapped by the errors caused by invalid request
+	// parameters.
+	ErrBadRequest = errors.New("bad request")
+
+	// ErrPreconditionFailed is returned by the conditional writes when the
+	// entry was modified since the client read it.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
 	tables map[string]*Table = map[string]*Table{}
 
-	reFilter   = regexp.MustCompile(`([a-zA-Z_.]+)\s*(=|>| |~|>=|<=)\s*(.*)`)
+	reFilter   = regexp.MustCompile(`([a-zA-Z0-9_.@]+)\s*(=|>| |~|>=|<=)\s*(.*)`)
 	tableJoins = []tableJoin{
 		{From: "tags", To: "node_tags", Cols: [][]string{{"tag_id", "tag_id"}}},
 		{From: "tags", To: "svc_tags", Cols: [][]string{{"tag_id", "tag_id"}}},
@@ -90,28 +114,48 @@ var (
 		{From: "auth_membership", To: "apps_responsibles", Cols: [][]string{{"group_id", "group_id"}}},
 		{From: "apps", To: "apps_publications", Cols: [][]string{{"id", "app_id"}}},
 		{From: "apps", To: "apps_responsibles", Cols: [][]string{{"id", "app_id"}}},
+		{From: "dashboard", To: "apps", Cols: [][]string{{"app", "app"}}},
+		{From: "dashboard", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "dashboard", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "dashboard_acks", To: "apps", Cols: [][]string{{"app", "app"}}},
+		{From: "comp_rulesets", To: "comp_rulesets_variables", Cols: [][]string{{"id", "ruleset_id"}}},
+		{From: "comp_rulesets", To: "comp_rulesets_nodes", Cols: [][]string{{"id", "ruleset_id"}}},
+		{From: "comp_rulesets", To: "comp_rulesets_services", Cols: [][]string{{"id", "ruleset_id"}}},
+		{From: "comp_rulesets", To: "comp_rulesets_tags", Cols: [][]string{{"id", "ruleset_id"}}},
+		{From: "comp_rulesets_nodes", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "comp_rulesets_services", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "comp_rulesets_tags", To: "tags", Cols: [][]string{{"tag_id", "tag_id"}}},
+		{From: "comp_moduleset", To: "comp_moduleset_modules", Cols: [][]string{{"id", "modset_id"}}},
+		{From: "comp_moduleset", To: "comp_modulesets_nodes", Cols: [][]string{{"id", "modset_id"}}},
+		{From: "comp_moduleset", To: "comp_modulesets_services", Cols: [][]string{{"id", "modset_id"}}},
+		{From: "comp_moduleset", To: "comp_modulesets_tags", Cols: [][]string{{"id", "modset_id"}}},
+		{From: "comp_modulesets_nodes", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "comp_modulesets_services", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "comp_modulesets_tags", To: "tags", Cols: [][]string{{"tag_id", "tag_id"}}},
+		{From: "comp_status", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "checks_live", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "node_pkg", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "node_patches", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "node_ip", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "node_hba", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "stor_zone", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "stor_zone", To: "node_hba", Cols: [][]string{{"node_id", "node_id"}, {"hba_id", "hba_id"}}},
+		{From: "svcdisks", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
+		{From: "svcdisks", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "svcdisks", To: "diskinfo", Cols: [][]string{{"disk_id", "disk_id"}}},
+		{From: "clusters", To: "nodes", Cols: [][]string{{"cluster_id", "cluster_id"}}},
+		{From: "clusters", To: "services", Cols: [][]string{{"cluster_id", "cluster_id"}}},
+		{From: "svc_configs", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "svc_config_keywords", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "svcactions", To: "services", Cols: [][]string{{"svc_id", "svc_id"}}},
+		{From: "svcactions", To: "nodes", Cols: [][]string{{"node_id", "node_id"}}},
 	}
+	// tableRoutes are the preferred routes, where the shortest path in
+	// the tableJoins graph is not the expected one.
 	tableRoutes = []tableRoute{
-		{From: "auth_user", To: "auth_group", Via: []string{"auth_membership"}},
-		{From: "auth_user", To: "apps_publications", Via: []string{"auth_membership"}},
-		{From: "auth_user", To: "apps_responsibles", Via: []string{"auth_membership"}},
-		{From: "tags", To: "nodes", Via: []string{"node_tags"}},
-		{From: "tags", To: "services", Via: []string{"svc_tags"}},
 		{From: "tags", To: "apps_publications", Via: []string{"svc_tags", "services", "apps"}},
 		{From: "tags", To: "auth_membership", Via: []string{"svc_tags", "services", "apps", "apps_publications"}},
-		{From: "nodes", To: "apps_publications", Via: []string{"apps"}},
-		{From: "nodes", To: "apps_responsibles", Via: []string{"apps"}},
-		{From: "nodes", To: "auth_membership", Via: []string{"apps", "apps_publications"}},
-		{From: "node_tags", To: "apps", Via: []string{"nodes"}},
-		{From: "node_tags", To: "apps_publications", Via: []string{"nodes", "apps"}},
-		{From: "node_tags", To: "auth_membership", Via: []string{"nodes", "apps", "apps_publications"}},
 		{From: "services", To: "nodes", Via: []string{"svcmon"}},
-		{From: "services", To: "apps_publications", Via: []string{"apps"}},
-		{From: "services", To: "apps_responsibles", Via: []string{"apps"}},
-		{From: "services", To: "auth_membership", Via: []string{"apps", "apps_publications"}},
-		{From: "svc_tags", To: "apps", Via: []string{"services"}},
-		{From: "svc_tags", To: "apps_publications", Via: []string{"services", "apps"}},
-		{From: "svc_tags", To: "auth_membership", Via: []string{"services", "apps", "apps_publications"}},
 	}
 )
 
@@ -133,11 +177,20 @@ func (t Table) Request(opts ...funcopt.O) *request {
 		acl:         true,
 		filters:     true,
 		writeIntent: false,
+		locked:      &lockedFilterset{},
 	}
 	_ = funcopt.Apply(&req, opts...)
 	return &req
 }
 
+// subRequest returns a request on the table sharing the locked filterset
+// resolved for t, so the nested ACL do not query it again.
+func (t *request) subRequest(table *Table, opts ...funcopt.O) *request {
+	sub := table.Request(opts...)
+	sub.locked = t.locked
+	return sub
+}
+
 func (t *request) Where(query interface{}, args ...interface{}) {
 	t.tx = t.tx.Where(query, args...)
 }
@@ -183,11 +236,11 @@ func (t tableJoin) String() string {
 	return t.SQL(t.To)
 }
 func (t tableJoin) SQL(s string) string {
-	cols := make([]string, len(t.Cols))
-	for i, col := range t.Cols {
-		cols[i] = fmt.Sprintf("`%s`.`%s`=`%s`.`%s`", t.From, col[0], t.To, col[1])
+	here := t.From
+	if s == t.From {
+		here = t.To
 	}
-	return fmt.Sprintf("LEFT JOIN `%s` ON %s", s, strings.Join(cols, " AND "))
+	return t.sqlAs(s, here, s)
 }
 
 func parseProperty(s string, table string) property {
@@ -256,7 +309,11 @@ func (t property) SQL() string {
 	if t.Table == "" {
 		return fmt.Sprintf("`%s`", t.Name)
 	}
-	return fmt.Sprintf("`%s`.`%s`", t.Table, t.Name)
+	table, alias := splitAlias(t.Table)
+	if alias != "" {
+		table = alias
+	}
+	return fmt.Sprintf("`%s`.`%s`", table, t.Name)
 }
 
 func (t property) String() string {
@@ -299,6 +356,18 @@ func (t *request) withFilters(filters []string) {
 			op, value string
 			neg       bool
 		)
+		col := l[1]
+		if strings.Contains(col, ".") {
+			// qualified property, join its table
+			prop := t.table.parseProperty(col)
+			if !t.allowProperty(prop) {
+				return
+			}
+			t.AutoJoin(prop.Table)
+			col = prop.SQL()
+		} else if !t.allowProperty(t.table.parseProperty(col)) {
+			return
+		}
 		switch l[2] {
 		case "~", " ":
 			op = "LIKE"
@@ -311,7 +380,7 @@ func (t *request) withFilters(filters []string) {
 			value = strings.TrimLeft(value, "!")
 		}
 		if value == "empty" {
-			where := fmt.Sprintf("%s IS NULL or %s = ?", l[1], l[1])
+			where := fmt.Sprintf("%s IS NULL or %s = ?", col, col)
 			if neg {
 				t.tx = t.tx.Not(where, "")
 			} else {
@@ -321,7 +390,7 @@ func (t *request) withFilters(filters []string) {
 			if strings.HasPrefix(value, "(") && op == " " {
 				op = "IN"
 			}
-			where := l[1] + " " + op + " ?"
+			where := col + " " + op + " ?"
 			if neg {
 				t.tx = t.tx.Not(where, value)
 			} else {
@@ -332,9 +401,51 @@ func (t *request) withFilters(filters []string) {
 	}
 }
 
-func (t *request) withJoins(props propSlice) {
+// allowProperty records an error in the request database session, so the
+// next query fails with this error, and returns false if the property is
+// not a non-secret column of a registered table joinable to the request
+// table.
+func (t *request) allowProperty(prop property) bool {
+	if _, err := checkProperty(t.table.Name, prop); err != nil {
+		t.tx.AddError(err)
+		return false
+	}
+	return true
+}
+
+// allowSortProperty is allowProperty for an ordering property, which must
+// also be a sortable column.
+func (t *request) allowSortProperty(prop property) bool {
+	rule, err := checkProperty(t.table.Name, prop)
+	if err == nil && !rule.Sortable {
+		err = fmt.Errorf("%w: property %s is not sortable", ErrBadRequest, prop)
+	}
+	if err != nil {
+		t.tx.AddError(err)
+		return false
+	}
+	return true
+}
+
+func (t *request) allowProperties(props propSlice) bool {
+	for _, prop := range props {
+		if !t.allowProperty(prop) {
+			return false
+		}
+	}
+	return true
+}
+
+// withJoins selects the props, joining their tables. The hidden props
+// are also selected, aliased as cursor columns.
+func (t *request) withJoins(props, hidden propSlice) {
 	if len(props) == 0 {
 		props = t.table.props()
+	} else if !t.allowProperties(props) {
+		return
+	}
+	if !t.allowProperties(hidden) {
+		return
 	}
 	selects := make([]string, len(props))
 	for i, prop := range props {
@@ -347,6 +458,10 @@ func (t *request) withJoins(props propSlice) {
 		t.AutoJoin(prop.Table)
 		selects[i] = fmt.Sprintf("%s as `%s`", prop.SQL(), as)
 	}
+	for i, prop := range hidden {
+		t.AutoJoin(prop.Table)
+		selects = append(selects, fmt.Sprintf("%s as `%s`", prop.SQL(), cursorColumn(i)))
+	}
 	t.tx.Select(strings.Join(selects, ","))
 }
 
@@ -358,14 +473,64 @@ func (t *request) withACL(user auth.Info) {
 	case "auth_user":
 		t.withUserACL(user)
 		return
+	case "filtersets":
+		t.withFiltersetACL(user)
+		return
+	case "webhooks", "webhook_deliveries":
+		t.withManagerACL(user)
+		return
+	case "jobs":
+		t.withOwnerACL(user)
+		return
+	case "comp_rulesets", "comp_rulesets_variables", "comp_rulesets_tags":
+		t.withCompAttachedACL(user, compRulesetAttachments)
+		return
+	case "comp_moduleset", "comp_moduleset_modules", "comp_modulesets_tags":
+		t.withCompAttachedACL(user, compModulesetAttachments)
+		return
+	case "checks_thresholds":
+		t.withPrivilegeACL(user, "CheckManager")
+		return
+	case "clusters":
+		t.withMemberNodesACL(user)
+		return
 	}
-	if t.writeIntent {
+	t.withLockedFilterset(user)
+	if t.aclSubquery {
+		t.withACLSubquery(user)
+	} else if t.writeIntent {
 		t.withWriteACL(user)
 	} else {
 		t.withReadACL(user)
 	}
 }
 
+// withACLSubquery applies the read or write ACL in a subquery selecting
+// the readable or writable entry ids. The ACL joins duplicate the entries
+// published to or under the responsibility of several groups of the user,
+// which the aggregates would count several times.
+func (t *request) withACLSubquery(user auth.Info) {
+	if authuser.IsManager(user) {
+		return
+	}
+	sub := t.subRequest(&t.table,
+		TableRequestWithFilters(false),
+		TableRequestWithPaging(false),
+	)
+	if t.writeIntent {
+		sub.withWriteACL(user)
+	} else {
+		sub.withReadACL(user)
+	}
+	if err := sub.tx.Error; err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	pk := property{Table: t.table.Name, Name: "id"}
+	sub.tx.Select(pk.SQL())
+	t.Where(pk.SQL()+" IN (?)", sub.tx)
+}
+
 func txPeerUserIDS(user auth.Info) *gorm.DB {
 	groups := authuser.OrgGroups(user)
 	return db.Table("auth_user").
@@ -388,6 +553,131 @@ func (t *request) withUserACL(user auth.Info) {
 	}
 }
 
+// withManagerACL limits the access to the table entries to managers.
+func (t *request) withManagerACL(user auth.Info) {
+	if authuser.IsManager(user) {
+		return
+	}
+	t.Where(property{Table: t.table.Name, Name: "id"}.SQL() + " < 0")
+}
+
+// withOwnerACL limits the access to the table entries to their owner, set
+// in the user_id column, and to managers.
+func (t *request) withOwnerACL(user auth.Info) {
+	if authuser.IsManager(user) {
+		return
+	}
+	id, ok := userID(user)
+	if !ok {
+		// node auth
+		id = -1
+	}
+	t.Where(property{Table: t.table.Name, Name: "user_id"}.SQL()+" = ?", id)
+}
+
+// withMemberNodesACL limits the access to the clusters to the clusters
+// with a member node the user can read, or write with write intent. The
+// member nodes are selected in a subquery, so the clusters with several
+// member nodes are not duplicated.
+func (t *request) withMemberNodesACL(user auth.Info) {
+	if authuser.IsManager(user) {
+		return
+	}
+	sub := t.subRequest(Tab("nodes"),
+		TableRequestWithFilters(false),
+		TableRequestWithPaging(false),
+		TableRequestWithWriteIntent(t.writeIntent),
+	)
+	sub.withACL(user)
+	if err := sub.tx.Error; err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	sub.tx.Select(property{Table: "nodes", Name: "cluster_id"}.SQL())
+	t.Where(property{Table: t.table.Name, Name: "cluster_id"}.SQL()+" IN (?)", sub.tx)
+}
+
+// compAttachments describes the tables attaching the compliance rulesets
+// or modulesets to nodes, services and tags.
+type compAttachments struct {
+	parent   string
+	key      string
+	nodes    string
+	services string
+	tags     string
+}
+
+var (
+	compRulesetAttachments = compAttachments{
+		parent:   "comp_rulesets",
+		key:      "ruleset_id",
+		nodes:    "comp_rulesets_nodes",
+		services: "comp_rulesets_services",
+		tags:     "comp_rulesets_tags",
+	}
+	compModulesetAttachments = compAttachments{
+		parent:   "comp_moduleset",
+		key:      "modset_id",
+		nodes:    "comp_modulesets_nodes",
+		services: "comp_modulesets_services",
+		tags:     "comp_modulesets_tags",
+	}
+)
+
+// withCompAttachedACL limits the write access to the compliance rulesets
+// or modulesets, and to their variables, modules and tags, to the users
+// with the CompManager privilege. The other users and the nodes can read
+// the ones attached to a node or a service they can read, directly or via
+// one of the node or service tags.
+func (t *request) withCompAttachedACL(user auth.Info, a compAttachments) {
+	if authuser.HasPrivilege(user, "CompManager") {
+		return
+	}
+	if t.writeIntent {
+		t.Where(property{Table: t.table.Name, Name: "id"}.SQL() + " < 0")
+		return
+	}
+	readable := func(table, col string) *gorm.DB {
+		sub := t.subRequest(Tab(table),
+			TableRequestWithFilters(false),
+			TableRequestWithPaging(false),
+		)
+		sub.withACL(user)
+		if err := sub.tx.Error; err != nil {
+			t.tx.AddError(err)
+		}
+		return sub.tx.Select(property{Table: table, Name: col}.SQL())
+	}
+	byNode := db.Table(a.nodes).
+		Select(a.key).
+		Where("node_id IN (?)", readable("nodes", "node_id"))
+	byService := db.Table(a.services).
+		Select(a.key).
+		Where("svc_id IN (?)", readable("services", "svc_id"))
+	byTag := db.Table(a.tags).
+		Select(a.key).
+		Where("tag_id IN (?) OR tag_id IN (?)",
+			db.Table("node_tags").Select("tag_id").Where("node_id IN (?)", readable("nodes", "node_id")),
+			db.Table("svc_tags").Select("tag_id").Where("svc_id IN (?)", readable("services", "svc_id")),
+		)
+	col := a.key
+	if t.table.Name == a.parent {
+		col = "id"
+	}
+	prop := property{Table: t.table.Name, Name: col}.SQL()
+	t.Where(fmt.Sprintf("(%s IN (?) OR %s IN (?) OR %s IN (?))", prop, prop, prop), byNode, byService, byTag)
+}
+
+// withPrivilegeACL limits the write access to the table entries to the
+// users with the privilege. The entries are readable by all users and
+// nodes.
+func (t *request) withPrivilegeACL(user auth.Info, priv string) {
+	if !t.writeIntent || authuser.HasPrivilege(user, priv) {
+		return
+	}
+	t.Where(property{Table: t.table.Name, Name: "id"}.SQL() + " < 0")
+}
+
 func (t *request) withWriteACL(user auth.Info) {
 	if authuser.IsManager(user) {
 		return
@@ -432,51 +722,87 @@ func (t *request) withReadACL(user auth.Info) {
 // getHops returns
 //  []string{"svc_tags", "tags"} as the "svc to tags" route
 //  []string{"tags"} as the "svc_tags to tags" route
-func getHops(from, to string) []string {
+//
+// The preferred route declared in tableRoutes is used if any, else the
+// shortest path in the tableJoins graph.
+func getHops(from, to string) ([]string, error) {
 	for _, r := range tableRoutes {
-		routeKey := genKey(r.From, r.To)
-		if routeKey == genKey(from, to) {
-			return append(r.Via, to)
-		}
-		if routeKey == genKey(to, from) {
+		switch {
+		case r.From == from && r.To == to:
+			s := make([]string, 0, len(r.Via)+1)
+			s = append(s, r.Via...)
+			return append(s, to), nil
+		case r.From == to && r.To == from:
 			// return the reversed hops
-			s := append([]string{to}, r.Via...)
-			for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-				s[i], s[j] = s[j], s[i]
+			s := make([]string, 0, len(r.Via)+1)
+			for i := len(r.Via) - 1; i >= 0; i-- {
+				s = append(s, r.Via[i])
 			}
-			return s
+			return append(s, to), nil
 		}
 	}
-	return []string{to}
+	if hops, ok := shortestPath(from, to); ok {
+		return hops, nil
+	}
+	return nil, fmt.Errorf("%w: no join path from %s to %s", ErrBadRequest, from, to)
 }
 
+// AutoJoin joins the table, and the intermediate tables of the route from
+// the request table. The table can be aliased as "table@alias" to join it
+// again, through its own aliased intermediate tables.
+//
+// A join error is recorded in the request database session, so the next
+// query fails with this error.
 func (t *request) AutoJoin(table string) {
-	if table == t.table.Name {
+	name, alias := splitAlias(table)
+	if name == t.table.Name && alias == "" {
 		// self join, noop
 		return
 	}
-	here := t.table.Name
-	for _, there := range getHops(t.table.Name, table) {
+	if alias != "" && !reTableAlias.MatchString(alias) {
+		t.tx.AddError(fmt.Errorf("%w: invalid table alias %s", ErrBadRequest, alias))
+		return
+	}
+	hops, err := getHops(t.table.Name, name)
+	if err != nil {
+		t.tx.AddError(err)
+		return
+	}
+	here, hereAs := t.table.Name, t.table.Name
+	for i, there := range hops {
 		j, ok := findJoin(here, there)
 		if !ok {
-			log.Printf("missing autojoin from %s to %s", here, there)
+			t.tx.AddError(fmt.Errorf("missing autojoin from %s to %s", here, there))
 			return
 		}
-		if t.joined.Has(j) {
+		thereAs := there
+		key := j.Key()
+		switch {
+		case alias == "":
+		case i == len(hops)-1:
+			thereAs = alias
+			key = "@" + thereAs
+		default:
+			thereAs = alias + "__" + there
+			key = "@" + thereAs
+		}
+		if _, ok := t.joined[key]; ok {
 			// already joined
 		} else {
-			sql := j.SQL(there)
-			t.joined.Add(j)
-			t.tx = t.tx.Joins(sql)
+			t.joined[key] = nil
+			t.tx = t.tx.Joins(j.sqlAs(there, hereAs, thereAs))
 		}
-		here = there
+		here, hereAs = there, thereAs
 	}
-	return
 }
 
 func (t *request) withGroups(groups propSlice) {
+	if !t.allowProperties(groups) {
+		return
+	}
 	for _, prop := range groups {
-		t.tx = t.tx.Group(prop.String())
+		t.AutoJoin(prop.Table)
+		t.tx = t.tx.Group(prop.SQL())
 	}
 }
 
@@ -487,6 +813,13 @@ func (t *request) withOrders(orders propSlice) {
 
 	sqlOrders := make([]string, len(orders))
 	for i, prop := range orders {
+		if prop.Table != "" {
+			// unqualified orders are aggregate aliases
+			if !t.allowSortProperty(prop) {
+				return
+			}
+			t.AutoJoin(prop.Table)
+		}
 		sqlOrders[i] = prop.SQLWithOrder()
 	}
 	t.tx = t.tx.Order(strings.Join(sqlOrders, ","))
@@ -499,6 +832,88 @@ func (t *request) withPaging(offset, limit int) {
 	t.tx = t.tx.Offset(offset).Limit(limit)
 }
 
+// withCursor selects the page of rows after the cursor in the keyset
+// ordering, or before the cursor if its direction is backward. One more
+// row than the limit is fetched to detect if more rows are available.
+func (t *request) withCursor(c *cursor, keyset propSlice, limit int) error {
+	orders := keyset
+	if c.Backward {
+		orders = keyset.reversed()
+	}
+	if len(c.Values) > 0 {
+		where, args, err := keysetWhere(orders, c.Values)
+		if err != nil {
+			return err
+		}
+		t.tx = t.tx.Where(where, args...)
+	}
+	t.withOrders(orders)
+	if t.paging {
+		t.tx = t.tx.Limit(limit + 1)
+	}
+	return nil
+}
+
+// cursorPage strips the cursor columns from the fetched rows, restores
+// the keyset ordering of a backward page and returns the next and
+// previous page cursors.
+func (t *request) cursorPage(data []map[string]interface{}, c *cursor, n, limit int) ([]map[string]interface{}, string, string) {
+	var next, prev string
+	more := t.paging && len(data) > limit
+	if more {
+		data = data[:limit]
+	}
+	if c.Backward {
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+	if len(data) > 0 {
+		first := data[0]
+		last := data[len(data)-1]
+		if more || c.Backward {
+			next = makeCursor(last, n, false).String()
+		}
+		if (more && c.Backward) || (!c.Backward && len(c.Values) > 0) {
+			prev = makeCursor(first, n, true).String()
+		}
+	}
+	for _, row := range data {
+		for i := 0; i < n; i++ {
+			delete(row, cursorColumn(i))
+		}
+	}
+	return data, next, prev
+}
+
+// approxTotal returns the number of rows the optimizer estimates the
+// request will examine, which is much cheaper than a count on large
+// tables. It returns -1 if the estimation is not available.
+func (t *request) approxTotal() int64 {
+	stmt := t.tx.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+	rows, err := db.Raw("EXPLAIN "+stmt.SQL.String(), stmt.Vars...).Rows()
+	if err != nil {
+		return -1
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return -1
+	}
+	m := make(map[string]interface{})
+	if err := db.ScanRows(rows, &m); err != nil {
+		return -1
+	}
+	switch v := m["rows"].(type) {
+	case int64:
+		return v
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	}
+	return -1
+}
+
 func (t *request) TX(r *http.Request) *gorm.DB {
 	user := auth.User(r)
 	t.withACL(user)
@@ -520,46 +935,95 @@ func (t *request) TX(r *http.Request) *gorm.DB {
 }
 
 func (t *request) MakeTableResponse(r *http.Request) (*TableResponse, error) {
-	var total int64
+	var (
+		total       int64 = -1
+		totalApprox bool
+		next, prev  string
+		c           *cursor
+		keyset      propSlice
+		fcts        facets
+	)
+
+	props := t.table.queryProps(r)
+	groups := t.table.queryGroups(r)
+	orders := t.table.queryOrders(r)
+	aggs, err := t.table.queryAggregates(r)
+	if err != nil {
+		return nil, err
+	}
 
 	user := auth.User(r)
+	t.aclSubquery = len(aggs) > 0
 	t.withACL(user)
+	if props, orders, err = t.queryFilterset(r, props, orders); err != nil {
+		return nil, err
+	}
 
-	// props selection
-	props := t.table.queryProps(r)
-	t.withJoins(props)
+	// keyset paging
+	if s, ok := queryCursor(r); ok {
+		if len(groups) > 0 || len(aggs) > 0 {
+			return nil, fmt.Errorf("%w: cursor paging is not supported with groupby or aggregate", ErrBadRequest)
+		}
+		if c, err = decodeCursor(s); err != nil {
+			return nil, err
+		}
+		keyset = t.keysetProps(orders)
+	}
 
 	// filters
 	filters := queryFilters(r)
 	t.withFilters(filters)
 
-	// grouping
-	groups := t.table.queryGroups(r)
-	t.withGroups(groups)
+	meta := queryMeta(r)
+	if meta {
+		// meta.facets
+		// compute before applying the selection and grouping
+		if fcts, err = t.facets(t.table.queryFacets(r), queryFacetsLimit(r)); err != nil {
+			return nil, err
+		}
+	}
 
-	// ordering
-	orders := t.table.queryOrders(r)
-	t.withOrders(orders)
+	if err := t.withSelection(props, groups, keyset, aggs, queryHaving(r)); err != nil {
+		return nil, err
+	}
+	orders = aggs.orders(orders, t.table.Name)
 
-	meta := queryMeta(r)
 	if meta {
 		// meta.total
 		// compute before applying the paging
-		if err := t.tx.Count(&total).Error; err != nil {
-			return nil, err
+		switch queryTotal(r) {
+		case "none":
+		case "approx":
+			total = t.approxTotal()
+			totalApprox = true
+		default:
+			if err := t.count(&total, len(aggs) > 0); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// paging
+	// ordering and paging
 	offset := queryOffset(r)
 	limit := queryLimit(r)
-	t.withPaging(offset, limit)
+	if c != nil {
+		offset = 0
+		if err := t.withCursor(c, keyset, limit); err != nil {
+			return nil, err
+		}
+	} else {
+		t.withOrders(orders)
+		t.withPaging(offset, limit)
+	}
 
 	// fetch data
 	data := make([]map[string]interface{}, 0)
 	if err := t.tx.Find(&data).Error; err != nil {
 		return nil, err
 	}
+	if c != nil {
+		data, next, prev = t.cursorPage(data, c, len(keyset), limit)
+	}
 
 	td := &TableResponse{}
 	td.Data = data
@@ -567,9 +1031,13 @@ func (t *request) MakeTableResponse(r *http.Request) (*TableResponse, error) {
 	if meta {
 		td.Meta = &tableResponseMeta{
 			Total:          total,
+			TotalApprox:    totalApprox,
 			Offset:         offset,
 			Limit:          limit,
 			Count:          len(td.Data),
+			NextCursor:     next,
+			PrevCursor:     prev,
+			Facets:         fcts,
 			AvailableProps: availProps(props),
 			IncludedProps:  props,
 		}
@@ -577,6 +1045,41 @@ func (t *request) MakeTableResponse(r *http.Request) (*TableResponse, error) {
 	return td, nil
 }
 
+// withSelection selects the props, or the aggregates and the grouping
+// props if aggregates are requested, and applies the grouping and the
+// having conditions.
+func (t *request) withSelection(props, groups, hidden propSlice, aggs aggregates, having []string) error {
+	// props selection
+	if len(aggs) > 0 {
+		if err := t.withAggregates(props, groups, aggs); err != nil {
+			return err
+		}
+	} else {
+		t.withJoins(props, hidden)
+	}
+
+	// grouping
+	t.withGroups(groups)
+	if len(having) > 0 {
+		if len(aggs) == 0 {
+			return fmt.Errorf("%w: having requires aggregate", ErrBadRequest)
+		}
+		return t.withHaving(having, aggs)
+	}
+	return nil
+}
+
+// count sets the number of rows the request selects, before paging.
+// Aggregated and distinct requests must be counted from a subquery,
+// because the having conditions refer to the aggregate aliases and the
+// distinct clause applies to the whole selection.
+func (t *request) count(total *int64, subquery bool) error {
+	if !subquery {
+		return t.tx.Count(total).Error
+	}
+	return db.Table("(?) AS `aggregated`", t.tx.Session(&gorm.Session{})).Count(total).Error
+}
+
 func availProps(props propSlice) propSlice {
 	done := make(map[string]interface{})
 	ap := make([]property, 0)
@@ -612,11 +1115,12 @@ func (t Table) props() propSlice {
 	if t.propMap == nil {
 		t.makePropMap()
 	}
-	props := make(propSlice, len(t.propMap))
-	i := 0
+	props := make(propSlice, 0, len(t.propMap))
 	for prop, _ := range t.propMap {
-		props[i] = prop
-		i = i + 1
+		if secretProps[prop] {
+			continue
+		}
+		props = append(props, prop)
 	}
 	return props
 }
@@ -672,6 +1176,27 @@ func queryMeta(r *http.Request) bool {
 	}
 }
 
+// queryCursor returns the keyset paging cursor, and true if the client
+// requested keyset paging. An empty cursor requests the first page.
+func queryCursor(r *http.Request) (string, bool) {
+	l, ok := r.URL.Query()["cursor"]
+	if !ok || len(l) == 0 {
+		return "", false
+	}
+	return l[0], true
+}
+
+// queryTotal returns the meta.total computation mode: "exact", "approx"
+// or "none".
+func queryTotal(r *http.Request) string {
+	switch s := r.URL.Query().Get("total"); s {
+	case "approx", "none":
+		return s
+	default:
+		return "exact"
+	}
+}
+
 func queryFilters(r *http.Request) []string {
 	if l, ok := r.URL.Query()["filters"]; ok {
 		return l
@@ -681,7 +1206,6 @@ func queryFilters(r *http.Request) []string {
 
 func queryLimit(r *http.Request) (limit int) {
 	var err error
-	defaultLimit := 20
 	limitParam := r.URL.Query().Get("limit")
 	if limitParam == "" {
 		limit = defaultLimit
@@ -0,0 +1,43 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// SvcAction is an action run by a node on a service instance, for
+	// example a start, stop or provision, as logged by the node agent.
+	//
+	// The action is begun with an empty status, and ended with the ok, warn
+	// or err status, the end date and the duration in seconds.
+	SvcAction struct {
+		ID        uint       `gorm:"primarykey" json:"id"`
+		NodeID    string     `gorm:"column:node_id; size:36; index" json:"node_id" validate:"readonly"`
+		SvcID     string     `gorm:"column:svc_id; size:36; index" json:"svc_id" validate:"required"`
+		Action    string     `gorm:"column:action; size:32; index" json:"action" validate:"required" example:"start"`
+		RID       string     `gorm:"column:rid; size:255" json:"rid" example:"fs#1,ip#1"`
+		Subset    string     `gorm:"column:subset; size:255" json:"subset"`
+		Status    string     `gorm:"column:status; size:5; index" json:"status" validate:"enum=ok|warn|err" example:"ok"`
+		Begin     time.Time  `gorm:"column:begin; index" json:"begin" validate:"readonly"`
+		End       *time.Time `gorm:"column:end" json:"end" validate:"readonly"`
+		Time      int        `gorm:"column:time" json:"time" validate:"readonly"`
+		PID       string     `gorm:"column:pid; size:32" json:"pid" example:"12345"`
+		SID       string     `gorm:"column:sid; size:36; index" json:"sid"`
+		Cron      bool       `gorm:"column:cron" json:"cron"`
+		Version   string     `gorm:"column:version; size:32" json:"version" example:"2.1-1869"`
+		StatusLog string     `gorm:"column:status_log; type:text" json:"status_log"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "svcactions",
+		Entry: SvcAction{},
+	})
+}
+
+func (SvcAction) TableName() string {
+	return "svcactions"
+}
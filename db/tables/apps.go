@@ -29,16 +29,21 @@ type App struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	App         string         `gorm:"column:app; index:i_app,unique; size:64" json:"app"`
-	AppDomain   string         `gorm:"column:app_domain; size:64" json:"app_domain"`
+	App         string         `gorm:"column:app; index:i_app,unique; size:64; index:idx_apps_search,class:FULLTEXT" json:"app"`
+	AppDomain   string         `gorm:"column:app_domain; size:64; index:idx_apps_search,class:FULLTEXT" json:"app_domain"`
 	AppTeamOps  string         `gorm:"column:app_team_ops; index:idx_app_team_ops; size:64" json:"app_team_ops"`
-	Description string         `gorm:"column:description; type:text" json:"description"`
+	Description string         `gorm:"column:description; type:text; index:idx_apps_search,class:FULLTEXT" json:"description"`
 }
 
 func init() {
 	db.Register(&db.Table{
 		Name:  "apps",
 		Entry: App{},
+		Search: &db.TableSearch{
+			Type: "app",
+			Name: "app",
+			Link: "/api/apps/%d",
+		},
 	})
 }
 
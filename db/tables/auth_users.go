@@ -50,7 +50,7 @@ type User struct {
 	Username              string         `gorm:"column:username; size:128" json:"username"`
 	FirstName             string         `gorm:"column:first_name; size:128" json:"first_name"`
 	LastName              string         `gorm:"column:last_name; size:128" json:"last_name"`
-	Email                 string         `gorm:"column:email; size:512" json:"email"`
+	Email                 string         `gorm:"column:email; size:512" json:"email" validate:"required,email"`
 	Password              string         `gorm:"column:password; size:512" json:"password"`
 	ResetPasswordKey      string         `gorm:"column:reset_password_key; size:512" json:"reset_password_key"`
 	RegistrationKey       string         `gorm:"column:registration_key; size:512" json:"registration_key"`
@@ -0,0 +1,71 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// Check is the last value pushed by a node for a check instance, for
+	// example the usage percent of a filesystem or the number of paths of
+	// a multipath device, and its evaluation against the thresholds.
+	//
+	// chk_err is 1 if the value is out of the [chk_low, chk_high]
+	// thresholds, selected by chk_threshold_provider.
+	Check struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_checks_live_key" json:"node_id" validate:"readonly"`
+		SvcID     string    `gorm:"column:svc_id; size:36; uniqueIndex:idx_checks_live_key; index" json:"svc_id"`
+		Type      string    `gorm:"column:chk_type; size:32; uniqueIndex:idx_checks_live_key; index" json:"chk_type" validate:"required" example:"fs_u"`
+		Instance  string    `gorm:"column:chk_instance; size:255; uniqueIndex:idx_checks_live_key" json:"chk_instance" example:"/var"`
+		Value     float64   `gorm:"column:chk_value" json:"chk_value" example:"85"`
+		Low       float64   `gorm:"column:chk_low" json:"chk_low" validate:"readonly"`
+		High      float64   `gorm:"column:chk_high" json:"chk_high" validate:"readonly"`
+		Provider  string    `gorm:"column:chk_threshold_provider; size:64" json:"chk_threshold_provider" validate:"readonly"`
+		Err       int       `gorm:"column:chk_err; index" json:"chk_err" validate:"readonly"`
+	}
+
+	// CheckThreshold is the range of the normal values of the checks of a
+	// type. The chk_instance is a LIKE pattern of the check instances the
+	// threshold applies to.
+	//
+	// The thresholds without node_id, svc_id and tag_id are the defaults.
+	// The others only apply to the checks of the node, of the service, of
+	// the service on the node, or of the nodes and services tagged with
+	// the tag.
+	CheckThreshold struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Type      string    `gorm:"column:chk_type; size:32; index" json:"chk_type" validate:"required" example:"fs_u"`
+		Instance  string    `gorm:"column:chk_instance; size:255; default:%" json:"chk_instance" example:"/var%"`
+		NodeID    string    `gorm:"column:node_id; size:36; index" json:"node_id"`
+		SvcID     string    `gorm:"column:svc_id; size:36; index" json:"svc_id"`
+		TagID     string    `gorm:"column:tag_id; size:40; index" json:"tag_id"`
+		Low       float64   `gorm:"column:chk_low" json:"chk_low"`
+		High      float64   `gorm:"column:chk_high" json:"chk_high" example:"90"`
+		UserID    uint      `gorm:"column:user_id" json:"user_id" validate:"readonly"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "checks_live",
+		Entry: Check{},
+	})
+	db.Register(&db.Table{
+		Name:  "checks_thresholds",
+		Entry: CheckThreshold{},
+	})
+}
+
+func (Check) TableName() string {
+	return "checks_live"
+}
+
+func (CheckThreshold) TableName() string {
+	return "checks_thresholds"
+}
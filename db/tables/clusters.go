@@ -0,0 +1,79 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/datatypes"
+)
+
+type (
+	// Cluster is a cluster of nodes, and of the services running on them,
+	// as registered by its member nodes. The cluster secret is never
+	// stored, only the metadata of its rotations.
+	//
+	// The cluster_heartbeats are the heartbeat configurations of the
+	// cluster, as a list of objects with at least a type, for example
+	// [{"type": "unicast", "port": 10000}, {"type": "disk", "dev": "/dev/sdb"}].
+	Cluster struct {
+		ID              uint           `gorm:"primarykey" json:"id"`
+		CreatedAt       time.Time      `json:"created_at"`
+		UpdatedAt       time.Time      `json:"updated_at"`
+		ClusterID       string         `gorm:"column:cluster_id; size:36; uniqueIndex" json:"cluster_id" validate:"required" example:"c4d7f1a2-5b0e-4f6a-9d3c-8e2b1a0f7c65"`
+		Name            string         `gorm:"column:cluster_name; size:128; index; index:idx_clusters_search,class:FULLTEXT" json:"cluster_name" validate:"required" example:"cluster1"`
+		SecretVersion   int            `gorm:"column:cluster_secret_version" json:"cluster_secret_version"`
+		SecretRotatedAt time.Time      `gorm:"column:cluster_secret_rotated_at" json:"cluster_secret_rotated_at"`
+		SecretRotatedBy string         `gorm:"column:cluster_secret_rotated_by; size:36" json:"cluster_secret_rotated_by"`
+		Heartbeats      datatypes.JSON `gorm:"column:cluster_heartbeats; type:text" json:"cluster_heartbeats" swaggertype:"array,object"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "clusters",
+		Entry: Cluster{},
+		Search: &db.TableSearch{
+			Type: "cluster",
+			Name: "cluster_name",
+			Link: "/api/clusters/%d",
+		},
+	})
+}
+
+func ClusterFromCtx(r *http.Request) []Cluster {
+	i := r.Context().Value("cluster")
+	if i == nil {
+		return []Cluster{}
+	}
+	return i.([]Cluster)
+}
+
+// ClusterCtx loads the cluster designated by the id path parameter,
+// either its index in database, its cluster_id or its name.
+func ClusterCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		col := "clusters.cluster_name"
+		switch {
+		case reUUID.MatchString(id):
+			col = "clusters.cluster_id"
+		case reID.MatchString(id):
+			col = "clusters.id"
+		}
+		data := make([]Cluster, 0)
+		tx := db.Tab("clusters").Request(
+			db.TableRequestWithFilters(false),
+			db.TableRequestWithPaging(false),
+		).TX(r)
+		if err := tx.Where(col+" = ?", id).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "cluster", data)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
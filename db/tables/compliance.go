@@ -0,0 +1,282 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/gorm"
+)
+
+type (
+	// CompRuleset is a named set of compliance variables. The rulesets
+	// attached to a node or service, directly or via one of its tags, are
+	// the rules checked and fixed by the compliance modules run on the
+	// node.
+	CompRuleset struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Name      string    `gorm:"column:ruleset_name; size:128; uniqueIndex; index:idx_comp_rulesets_search,class:FULLTEXT" json:"ruleset_name" validate:"required"`
+		Comment   string    `gorm:"column:ruleset_comment; size:255; index:idx_comp_rulesets_search,class:FULLTEXT" json:"ruleset_comment"`
+	}
+
+	// CompRulesetVariable is a compliance variable of a ruleset. The
+	// variable class tells the compliance modules how to interpret the
+	// value.
+	CompRulesetVariable struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		RulesetID uint      `gorm:"column:ruleset_id; uniqueIndex:idx_comp_rulesets_variables_key" json:"ruleset_id" validate:"readonly"`
+		Name      string    `gorm:"column:var_name; size:128; uniqueIndex:idx_comp_rulesets_variables_key" json:"var_name" validate:"required" example:"etc_motd"`
+		Class     string    `gorm:"column:var_class; size:64" json:"var_class" example:"file"`
+		Value     string    `gorm:"column:var_value; type:text" json:"var_value" example:"{\"path\": \"/etc/motd\", \"fmt\": \"welcome\"}"`
+	}
+
+	// CompModuleset is a named set of compliance modules.
+	CompModuleset struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Name      string    `gorm:"column:modset_name; size:128; uniqueIndex; index:idx_comp_moduleset_search,class:FULLTEXT" json:"modset_name" validate:"required"`
+		Comment   string    `gorm:"column:modset_comment; size:255; index:idx_comp_moduleset_search,class:FULLTEXT" json:"modset_comment"`
+	}
+
+	// CompModulesetModule is a compliance module of a moduleset. The
+	// nodes fix the autofix modules found non compliant by their checks.
+	CompModulesetModule struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		ModsetID  uint      `gorm:"column:modset_id; uniqueIndex:idx_comp_moduleset_modules_key" json:"modset_id" validate:"readonly"`
+		Name      string    `gorm:"column:modset_mod_name; size:128; uniqueIndex:idx_comp_moduleset_modules_key" json:"modset_mod_name" validate:"required" example:"motd"`
+		Autofix   bool      `gorm:"column:autofix; default:false" json:"autofix"`
+	}
+
+	// CompRulesetNode attaches a ruleset to a node.
+	CompRulesetNode struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		RulesetID uint      `gorm:"column:ruleset_id; uniqueIndex:idx_comp_rulesets_nodes_key" json:"ruleset_id"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_comp_rulesets_nodes_key; index" json:"node_id"`
+	}
+
+	// CompRulesetService attaches a ruleset to a service.
+	CompRulesetService struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		RulesetID uint      `gorm:"column:ruleset_id; uniqueIndex:idx_comp_rulesets_services_key" json:"ruleset_id"`
+		SvcID     string    `gorm:"column:svc_id; size:36; uniqueIndex:idx_comp_rulesets_services_key; index" json:"svc_id"`
+	}
+
+	// CompRulesetTag attaches a ruleset to the nodes and services tagged
+	// with a tag.
+	CompRulesetTag struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		RulesetID uint      `gorm:"column:ruleset_id; uniqueIndex:idx_comp_rulesets_tags_key" json:"ruleset_id"`
+		TagID     string    `gorm:"column:tag_id; size:40; uniqueIndex:idx_comp_rulesets_tags_key; index" json:"tag_id"`
+	}
+
+	// CompModulesetNode attaches a moduleset to a node.
+	CompModulesetNode struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		ModsetID  uint      `gorm:"column:modset_id; uniqueIndex:idx_comp_modulesets_nodes_key" json:"modset_id"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_comp_modulesets_nodes_key; index" json:"node_id"`
+	}
+
+	// CompModulesetService attaches a moduleset to a service.
+	CompModulesetService struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		ModsetID  uint      `gorm:"column:modset_id; uniqueIndex:idx_comp_modulesets_services_key" json:"modset_id"`
+		SvcID     string    `gorm:"column:svc_id; size:36; uniqueIndex:idx_comp_modulesets_services_key; index" json:"svc_id"`
+	}
+
+	// CompModulesetTag attaches a moduleset to the nodes and services
+	// tagged with a tag.
+	CompModulesetTag struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		ModsetID  uint      `gorm:"column:modset_id; uniqueIndex:idx_comp_modulesets_tags_key" json:"modset_id"`
+		TagID     string    `gorm:"column:tag_id; size:40; uniqueIndex:idx_comp_modulesets_tags_key; index" json:"tag_id"`
+	}
+
+	// CompStatus is the last result reported by a node for a compliance
+	// module, run for the node or for one of its services.
+	CompStatus struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_comp_status_key" json:"node_id" validate:"readonly"`
+		SvcID     string    `gorm:"column:svc_id; size:36; uniqueIndex:idx_comp_status_key; index" json:"svc_id"`
+		Module    string    `gorm:"column:run_module; size:128; uniqueIndex:idx_comp_status_key; index" json:"run_module" validate:"required"`
+		Action    string    `gorm:"column:run_action; type:enum('check','fix')" json:"run_action" validate:"required"`
+		Status    int       `gorm:"column:run_status; index" json:"run_status" example:"0"`
+		Log       string    `gorm:"column:run_log; type:text" json:"run_log"`
+		Date      time.Time `gorm:"column:run_date" json:"run_date"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "comp_rulesets",
+		Entry: CompRuleset{},
+		Search: &db.TableSearch{
+			Type: "ruleset",
+			Name: "ruleset_name",
+			Link: "/api/compliance/rulesets/%d",
+		},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_rulesets_variables",
+		Entry: CompRulesetVariable{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_moduleset",
+		Entry: CompModuleset{},
+		Search: &db.TableSearch{
+			Type: "moduleset",
+			Name: "modset_name",
+			Link: "/api/compliance/modulesets/%d",
+		},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_moduleset_modules",
+		Entry: CompModulesetModule{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_rulesets_nodes",
+		Entry: CompRulesetNode{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_rulesets_services",
+		Entry: CompRulesetService{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_rulesets_tags",
+		Entry: CompRulesetTag{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_modulesets_nodes",
+		Entry: CompModulesetNode{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_modulesets_services",
+		Entry: CompModulesetService{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_modulesets_tags",
+		Entry: CompModulesetTag{},
+	})
+	db.Register(&db.Table{
+		Name:  "comp_status",
+		Entry: CompStatus{},
+	})
+}
+
+func (CompRulesetVariable) TableName() string {
+	return "comp_rulesets_variables"
+}
+
+func (CompModuleset) TableName() string {
+	return "comp_moduleset"
+}
+
+func (CompModulesetModule) TableName() string {
+	return "comp_moduleset_modules"
+}
+
+func (CompRulesetNode) TableName() string {
+	return "comp_rulesets_nodes"
+}
+
+func (CompRulesetService) TableName() string {
+	return "comp_rulesets_services"
+}
+
+func (CompRulesetTag) TableName() string {
+	return "comp_rulesets_tags"
+}
+
+func (CompModulesetNode) TableName() string {
+	return "comp_modulesets_nodes"
+}
+
+func (CompModulesetService) TableName() string {
+	return "comp_modulesets_services"
+}
+
+func (CompModulesetTag) TableName() string {
+	return "comp_modulesets_tags"
+}
+
+func (CompStatus) TableName() string {
+	return "comp_status"
+}
+
+func CompRulesetFromCtx(r *http.Request) []CompRuleset {
+	i := r.Context().Value("compRuleset")
+	if i == nil {
+		return []CompRuleset{}
+	}
+	return i.([]CompRuleset)
+}
+
+// CompRulesetCtx loads the ruleset designated by the id path parameter,
+// either its index in database or its name.
+func CompRulesetCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		col := "comp_rulesets.ruleset_name"
+		if reID.MatchString(id) {
+			col = "comp_rulesets.id"
+		}
+		data := make([]CompRuleset, 0)
+		if err := readableCompTX(r, "comp_rulesets").Where(col+" = ?", id).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "compRuleset", data)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func CompModulesetFromCtx(r *http.Request) []CompModuleset {
+	i := r.Context().Value("compModuleset")
+	if i == nil {
+		return []CompModuleset{}
+	}
+	return i.([]CompModuleset)
+}
+
+// CompModulesetCtx loads the moduleset designated by the id path
+// parameter, either its index in database or its name.
+func CompModulesetCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		col := "comp_moduleset.modset_name"
+		if reID.MatchString(id) {
+			col = "comp_moduleset.id"
+		}
+		data := make([]CompModuleset, 0)
+		if err := readableCompTX(r, "comp_moduleset").Where(col+" = ?", id).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "compModuleset", data)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func readableCompTX(r *http.Request, table string) *gorm.DB {
+	return db.Tab(table).Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+}
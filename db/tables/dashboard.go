@@ -0,0 +1,96 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/datatypes"
+)
+
+type (
+	// Dashboard is an alert raised by a dashboard rule on a node or a
+	// service. The alert message is the dash_fmt format, whose %(key)s
+	// placeholders are the dash_dict values.
+	Dashboard struct {
+		ID        uint           `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time      `json:"created_at"`
+		UpdatedAt time.Time      `json:"updated_at"`
+		Type      string         `gorm:"column:dash_type; size:64; index:idx_dashboard_key,unique" json:"dash_type" example:"node warranty end"`
+		NodeID    string         `gorm:"column:node_id; size:36; index:idx_dashboard_key,unique; index" json:"node_id"`
+		SvcID     string         `gorm:"column:svc_id; size:36; index:idx_dashboard_key,unique; index" json:"svc_id"`
+		App       string         `gorm:"column:app; size:64; index" json:"app"`
+		Env       string         `gorm:"column:dash_env; size:10" json:"dash_env"`
+		Severity  int            `gorm:"column:dash_severity; index" json:"dash_severity"`
+		Fmt       string         `gorm:"column:dash_fmt; size:255" json:"dash_fmt" example:"warranty ends on %(date)s"`
+		Dict      datatypes.JSON `gorm:"column:dash_dict; type:text" json:"dash_dict" swaggertype:"object"`
+		Acked     bool           `gorm:"column:acked; default:false" json:"acked"`
+	}
+
+	// DashboardAck is the acknowledgement of the dashboard alerts of a
+	// type on a node or service, until it expires. The acknowledged
+	// alerts are not notified.
+	DashboardAck struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Type      string    `gorm:"column:dash_type; size:64; index:idx_dashboard_acks_key,unique" json:"dash_type"`
+		NodeID    string    `gorm:"column:node_id; size:36; index:idx_dashboard_acks_key,unique" json:"node_id"`
+		SvcID     string    `gorm:"column:svc_id; size:36; index:idx_dashboard_acks_key,unique" json:"svc_id"`
+		App       string    `gorm:"column:app; size:64; index" json:"app"`
+		UserID    uint      `gorm:"column:user_id" json:"user_id"`
+		Comment   string    `gorm:"column:comment; size:255" json:"comment"`
+		Expires   time.Time `gorm:"column:expires; index" json:"expires"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "dashboard",
+		Entry: Dashboard{},
+	})
+	db.Register(&db.Table{
+		Name:  "dashboard_acks",
+		Entry: DashboardAck{},
+	})
+}
+
+func (Dashboard) TableName() string {
+	return "dashboard"
+}
+
+func DashboardFromCtx(r *http.Request) []Dashboard {
+	i := r.Context().Value("dashboard")
+	if i == nil {
+		return []Dashboard{}
+	}
+	return i.([]Dashboard)
+}
+
+func DashboardCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		n, err := readableGetDashboardByID(r, id)
+		if err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "dashboard", n)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func readableGetDashboardByID(r *http.Request, id string) ([]Dashboard, error) {
+	data := make([]Dashboard, 0)
+	tx := db.Tab("dashboard").Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+	if err := tx.Where("dashboard.id = ?", id).Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -0,0 +1,68 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// ServiceDisk is a disk seen by a node, used by a service on the node
+	// or by the node itself if svc_id is empty.
+	//
+	// The disk_id is the disk wwid, shared by the nodes seeing the same
+	// disk, and the key of the disk storage array information in
+	// diskinfo. The disk_size and disk_used are in MB.
+	ServiceDisk struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_svcdisks_key" json:"node_id" validate:"readonly"`
+		SvcID     string    `gorm:"column:svc_id; size:36; uniqueIndex:idx_svcdisks_key; index" json:"svc_id"`
+		DiskID    string    `gorm:"column:disk_id; size:128; uniqueIndex:idx_svcdisks_key; index" json:"disk_id" validate:"required" example:"600507680c80821e5000000000000abc"`
+		DiskSize  int64     `gorm:"column:disk_size" json:"disk_size" example:"51200"`
+		DiskUsed  int64     `gorm:"column:disk_used" json:"disk_used" example:"20480"`
+		Vendor    string    `gorm:"column:disk_vendor; size:64" json:"disk_vendor" example:"IBM"`
+		Model     string    `gorm:"column:disk_model; size:64" json:"disk_model" example:"2145"`
+		DG        string    `gorm:"column:disk_dg; size:255" json:"disk_dg" example:"vg_app"`
+		Local     bool      `gorm:"column:disk_local" json:"disk_local"`
+	}
+
+	// DiskInfo is the storage array information of a disk, reported by
+	// the nodes with access to the array management interfaces. The
+	// disk_size is in MB.
+	DiskInfo struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; index" json:"node_id" validate:"readonly"`
+		DiskID    string    `gorm:"column:disk_id; size:128; uniqueIndex" json:"disk_id" validate:"required" example:"600507680c80821e5000000000000abc"`
+		DevID     string    `gorm:"column:disk_devid; size:64" json:"disk_devid" example:"0ABC"`
+		ArrayID   string    `gorm:"column:disk_arrayid; size:64; index" json:"disk_arrayid" example:"svc01"`
+		Name      string    `gorm:"column:disk_name; size:128" json:"disk_name" example:"app_data_01"`
+		DiskSize  int64     `gorm:"column:disk_size" json:"disk_size" example:"51200"`
+		Alloc     int64     `gorm:"column:disk_alloc" json:"disk_alloc" example:"51200"`
+		Raid      string    `gorm:"column:disk_raid; size:32" json:"disk_raid" example:"RAID5"`
+		Group     string    `gorm:"column:disk_group; size:64" json:"disk_group" example:"pool0"`
+		Level     int       `gorm:"column:disk_level" json:"disk_level"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "svcdisks",
+		Entry: ServiceDisk{},
+	})
+	db.Register(&db.Table{
+		Name:  "diskinfo",
+		Entry: DiskInfo{},
+	})
+}
+
+func (ServiceDisk) TableName() string {
+	return "svcdisks"
+}
+
+func (DiskInfo) TableName() string {
+	return "diskinfo"
+}
@@ -0,0 +1,141 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type (
+	// Filterset is a saved set of table request filters, props and
+	// ordering. The filters are a json list of filters in the "filters"
+	// query parameter syntax.
+	Filterset struct {
+		ID        uint           `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time      `json:"created_at"`
+		UpdatedAt time.Time      `json:"updated_at"`
+		UserID    uint           `gorm:"column:user_id; uniqueIndex:idx_filtersets_user_name" json:"user_id" validate:"readonly"`
+		Name      string         `gorm:"column:name; uniqueIndex:idx_filtersets_user_name; size:128" json:"name" validate:"required"`
+		TableName string         `gorm:"column:table_name; size:64" json:"table_name" validate:"required"`
+		Filters   datatypes.JSON `gorm:"column:filters; type:text" json:"filters" swaggertype:"array,string"`
+		Props     string         `gorm:"column:props; size:1024" json:"props"`
+		Orderby   string         `gorm:"column:orderby; size:256" json:"orderby"`
+	}
+
+	// FiltersetGroup shares a filterset with the members of a group.
+	FiltersetGroup struct {
+		ID          uint      `gorm:"primarykey" json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		FiltersetID uint      `gorm:"column:filterset_id; uniqueIndex:idx_filterset_groups" json:"filterset_id"`
+		GroupID     uint      `gorm:"column:group_id; uniqueIndex:idx_filterset_groups" json:"group_id"`
+	}
+
+	// FiltersetUser assigns a filterset to a user. If the user lock_filter
+	// is "T", all the user table requests are limited to the entries
+	// matching the filterset filters.
+	FiltersetUser struct {
+		ID          uint      `gorm:"primarykey" json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		UserID      uint      `gorm:"column:user_id; uniqueIndex" json:"user_id"`
+		FiltersetID uint      `gorm:"column:filterset_id; index" json:"filterset_id"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "filtersets",
+		Entry: Filterset{},
+	})
+	db.Register(&db.Table{
+		Name:  "filterset_groups",
+		Entry: FiltersetGroup{},
+	})
+	db.Register(&db.Table{
+		Name:  "filterset_users",
+		Entry: FiltersetUser{},
+	})
+}
+
+func FiltersetFromCtx(r *http.Request) []Filterset {
+	i := r.Context().Value("filterset")
+	if i == nil {
+		return []Filterset{}
+	}
+	return i.([]Filterset)
+}
+
+func FiltersetCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		n, err := readableGetFiltersetByID(r, id)
+		if err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "filterset", n)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func readableGetFiltersetByID(r *http.Request, id string) ([]Filterset, error) {
+	data := make([]Filterset, 0)
+	tx := db.Tab("filtersets").Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+	if err := tx.Where("filtersets.id = ?", id).Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// IsOwnedBy returns true if the user created the filterset.
+func (t Filterset) IsOwnedBy(userID string) bool {
+	return fmt.Sprint(t.UserID) == userID
+}
+
+// GetFiltersetGroups returns the groups the filterset is shared with.
+func GetFiltersetGroups(id uint) ([]Group, error) {
+	data := make([]Group, 0)
+	tx := db.DB().Table("auth_group").
+		Joins("JOIN filterset_groups ON filterset_groups.group_id = auth_group.id").
+		Where("filterset_groups.filterset_id = ?", id)
+	if err := tx.Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// GetUserFilterset returns the filterset assigned to the user.
+func GetUserFilterset(userID uint) ([]Filterset, error) {
+	data := make([]Filterset, 0)
+	tx := db.DB().Table("filtersets").
+		Joins("JOIN filterset_users ON filterset_users.filterset_id = filtersets.id").
+		Where("filterset_users.user_id = ?", userID)
+	if err := tx.Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// CountFiltersetUsers returns the number of users the filterset is
+// assigned to, limited to the users with a locked filter if locked is true.
+func CountFiltersetUsers(tx *gorm.DB, id uint, locked bool) (int64, error) {
+	var i int64
+	tx = tx.Table("filterset_users").Where("filterset_users.filterset_id = ?", id)
+	if locked {
+		tx = tx.Joins("JOIN auth_user ON auth_user.id = filterset_users.user_id").
+			Where("auth_user.lock_filter = ?", "T")
+	}
+	if err := tx.Count(&i).Error; err != nil {
+		return 0, err
+	}
+	return i, nil
+}
@@ -0,0 +1,72 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// NodeIP is an address of a network interface of a node.
+	NodeIP struct {
+		ID         uint      `gorm:"primarykey" json:"id"`
+		CreatedAt  time.Time `json:"created_at"`
+		UpdatedAt  time.Time `json:"updated_at"`
+		NodeID     string    `gorm:"column:node_id; size:36; uniqueIndex:idx_node_ip_key" json:"node_id" validate:"readonly"`
+		Intf       string    `gorm:"column:intf; size:64; uniqueIndex:idx_node_ip_key" json:"intf" validate:"required" example:"eth0"`
+		Addr       string    `gorm:"column:addr; size:64; uniqueIndex:idx_node_ip_key; index" json:"addr" validate:"required" example:"192.168.0.10"`
+		AddrType   string    `gorm:"column:addr_type; size:8" json:"addr_type" example:"ipv4"`
+		Mask       string    `gorm:"column:mask; size:64" json:"mask" example:"24"`
+		Mac        string    `gorm:"column:mac; size:32; index" json:"mac" example:"52:54:00:12:34:56"`
+		Deprecated bool      `gorm:"column:flag_deprecated" json:"flag_deprecated"`
+	}
+
+	// NodeHBA is a host bus adapter port of a node, identified by its
+	// fibre channel WWN or iSCSI initiator name.
+	NodeHBA struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_node_hba_key" json:"node_id" validate:"readonly"`
+		HBAID     string    `gorm:"column:hba_id; size:128; uniqueIndex:idx_node_hba_key; index" json:"hba_id" validate:"required" example:"10000000c9a1b2c3"`
+		HBAType   string    `gorm:"column:hba_type; size:16" json:"hba_type" example:"fc"`
+	}
+
+	// StorZone is a storage target port reachable from a HBA port of a
+	// node.
+	StorZone struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		NodeID    string    `gorm:"column:node_id; size:36; uniqueIndex:idx_stor_zone_key" json:"node_id" validate:"readonly"`
+		HBAID     string    `gorm:"column:hba_id; size:128; uniqueIndex:idx_stor_zone_key" json:"hba_id" validate:"required" example:"10000000c9a1b2c3"`
+		TgtID     string    `gorm:"column:tgt_id; size:128; uniqueIndex:idx_stor_zone_key; index" json:"tgt_id" validate:"required" example:"50060e8007e2c410"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "node_ip",
+		Entry: NodeIP{},
+	})
+	db.Register(&db.Table{
+		Name:  "node_hba",
+		Entry: NodeHBA{},
+	})
+	db.Register(&db.Table{
+		Name:  "stor_zone",
+		Entry: StorZone{},
+	})
+}
+
+func (NodeIP) TableName() string {
+	return "node_ip"
+}
+
+func (NodeHBA) TableName() string {
+	return "node_hba"
+}
+
+func (StorZone) TableName() string {
+	return "stor_zone"
+}
@@ -0,0 +1,78 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/datatypes"
+)
+
+type (
+	// Job is a long-running operation run in the background by the jobs
+	// workers. A failed attempt is retried with a backoff until the
+	// maximum number of attempts is reached.
+	Job struct {
+		ID              uint           `gorm:"primarykey" json:"id"`
+		CreatedAt       time.Time      `json:"created_at"`
+		UpdatedAt       time.Time      `json:"updated_at"`
+		UserID          uint           `gorm:"column:user_id; index" json:"user_id"`
+		Type            string         `gorm:"column:type; size:64; index" json:"type" example:"dashboard_alerts_refresh"`
+		Args            datatypes.JSON `gorm:"column:args; type:text" json:"args" swaggertype:"object"`
+		Status          string         `gorm:"column:status; type:enum('queued','running','succeeded','failed','canceled'); default:queued; index:idx_jobs_due" json:"status"`
+		Progress        int            `gorm:"column:progress; default:0" json:"progress"`
+		Message         string         `gorm:"column:message; size:255" json:"message"`
+		Result          datatypes.JSON `gorm:"column:result; type:text" json:"result" swaggertype:"object"`
+		Error           string         `gorm:"column:error; type:text" json:"error"`
+		Attempts        int            `gorm:"column:attempts; default:0" json:"attempts"`
+		MaxAttempts     int            `gorm:"column:max_attempts; default:1" json:"max_attempts"`
+		RunAfter        time.Time      `gorm:"column:run_after; index:idx_jobs_due" json:"run_after"`
+		LeaseUntil      *time.Time     `gorm:"column:lease_until" json:"lease_until"`
+		CancelRequested bool           `gorm:"column:cancel_requested; default:false" json:"cancel_requested"`
+		StartedAt       *time.Time     `gorm:"column:started_at" json:"started_at"`
+		FinishedAt      *time.Time     `gorm:"column:finished_at" json:"finished_at"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "jobs",
+		Entry: Job{},
+	})
+}
+
+func JobFromCtx(r *http.Request) []Job {
+	i := r.Context().Value("job")
+	if i == nil {
+		return []Job{}
+	}
+	return i.([]Job)
+}
+
+func JobCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		n, err := readableGetJobByID(r, id)
+		if err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "job", n)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func readableGetJobByID(r *http.Request, id string) ([]Job, error) {
+	data := make([]Job, 0)
+	tx := db.Tab("jobs").Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+	if err := tx.Where("jobs.id = ?", id).Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
@@ -17,10 +17,10 @@ type NodeTag struct {
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	NodeID        string         `gorm:"column:node_id; size:36" json:"node_id"`
-	TagID         string         `gorm:"column:tag_id; size:40; index" json:"tag_id"`
+	NodeID        string         `gorm:"column:node_id; size:36" json:"node_id" validate:"required"`
+	TagID         string         `gorm:"column:tag_id; size:40; index" json:"tag_id" validate:"required"`
 	TagAttachData datatypes.JSON `gorm:"column:tag_attach_data; type:text" json:"tag_attach_data"`
-	Created       time.Time      `gorm:"column:created; autoCreateTime" json:"created"`
+	Created       time.Time      `gorm:"column:created; autoCreateTime" json:"created" validate:"readonly"`
 }
 
 func init() {
@@ -18,8 +18,8 @@ type Node struct {
 	CreatedAt           time.Time      `json:"created_at"`
 	UpdatedAt           time.Time      `json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	Nodename            string         `gorm:"column:nodename; index; index:ns,unique" json:"nodename"`
-	NodeID              string         `gorm:"column:node_id; uniqueIndex; size:36" json:"node_id"`
+	Nodename            string         `gorm:"column:nodename; index; index:ns,unique; index:idx_nodes_search,class:FULLTEXT" json:"nodename" validate:"required"`
+	NodeID              string         `gorm:"column:node_id; uniqueIndex; size:36" json:"node_id" validate:"readonly"`
 	ClusterID           string         `gorm:"column:cluster_id; size:36; index" json:"cluster_id"`
 	WarrantyEnd         time.Time      `gorm:"column:warranty_end; autoCreateTime" json:"warranty_end"`
 	MaintenanceEnd      time.Time      `gorm:"column:maintenance_end; autoCreateTime" json:"maintenance_end"`
@@ -32,7 +32,7 @@ type Node struct {
 	LastComm            time.Time      `gorm:"column:last_comm" json:"last_comm"`
 	TZ                  string         `gorm:"column:tz" json:"tz"`
 	AssetEnv            string         `gorm:"column:asset_env" json:"asset_env"`
-	NodeEnv             string         `gorm:"column:node_env; default:TST" json:"node_env"`
+	NodeEnv             string         `gorm:"column:node_env; default:TST" json:"node_env" validate:"enum=DEV|DRP|FOR|INT|PRA|PRD|PRJ|PPRD|QUAL|REC|STG|TMP|TST|UAT"`
 	MemBytes            int            `gorm:"column:mem_bytes" json:"mem_bytes"`
 	MemBanks            int            `gorm:"column:mem_banks" json:"mem_banks"`
 	MemSlots            int            `gorm:"column:mem_slots" json:"mem_slots"`
@@ -49,24 +49,24 @@ type Node struct {
 	CPUModel            string         `gorm:"column:cpu_model" json:"cpu_model"`
 	CPUVendor           string         `gorm:"column:cpu_vendor" json:"cpu_vendor"`
 	Type                string         `gorm:"column:type" json:"type"`
-	FQDN                string         `gorm:"column:fqdn" json:"fqdn"`
+	FQDN                string         `gorm:"column:fqdn; index:idx_nodes_search,class:FULLTEXT" json:"fqdn"`
 	TeamResponsible     string         `gorm:"column:team_responsible; index" json:"team_responsible"`
 	TeamInteg           string         `gorm:"column:team_integ" json:"team_integ"`
 	TeamSupport         string         `gorm:"column:team_support" json:"team_support"`
 	App                 string         `gorm:"column:app; index:ns,unique" json:"app"`
-	Serial              string         `gorm:"column:serial" json:"serial"`
+	Serial              string         `gorm:"column:serial; index:idx_nodes_search,class:FULLTEXT" json:"serial"`
 	SPVersion           string         `gorm:"column:sp_version" json:"sp_version"`
 	BIOSVersion         string         `gorm:"column:bios_version" json:"bios_version"`
-	Manufacturer        string         `gorm:"column:manufacturer" json:"manufacturer"`
-	Model               string         `gorm:"column:model; index" json:"model"`
+	Manufacturer        string         `gorm:"column:manufacturer; index:idx_nodes_search,class:FULLTEXT" json:"manufacturer"`
+	Model               string         `gorm:"column:model; index; index:idx_nodes_search,class:FULLTEXT" json:"model"`
 	LocAddr             string         `gorm:"column:loc_addr" json:"loc_addr"`
-	LocCity             string         `gorm:"column:loc_city" json:"loc_city"`
+	LocCity             string         `gorm:"column:loc_city; index:idx_nodes_search,class:FULLTEXT" json:"loc_city"`
 	LocZIP              string         `gorm:"column:loc_zip" json:"loc_zip"`
 	LocRack             string         `gorm:"column:loc_rack" json:"loc_rack"`
 	LocFloor            string         `gorm:"column:loc_floor" json:"loc_floor"`
 	LocCountry          string         `gorm:"column:loc_country" json:"loc_country"`
-	LocBuilding         string         `gorm:"column:loc_building" json:"loc_building"`
-	LocRoom             string         `gorm:"column:loc_room" json:"loc_room"`
+	LocBuilding         string         `gorm:"column:loc_building; index:idx_nodes_search,class:FULLTEXT" json:"loc_building"`
+	LocRoom             string         `gorm:"column:loc_room; index:idx_nodes_search,class:FULLTEXT" json:"loc_room"`
 	PowerSupplyNb       int            `gorm:"column:power_supply_nb; default:0" json:"power_supply_nb"`
 	PowerCabinet1       string         `gorm:"column:power_cabinet1" json:"power_cabinet1"`
 	PowerCabinet2       string         `gorm:"column:power_cabinet2" json:"power_cabinet2"`
@@ -77,7 +77,7 @@ type Node struct {
 	Updated             time.Time      `gorm:"column:updated" json:"updated"`
 	Enclosure           string         `gorm:"column:enclosure" json:"enclosure"`
 	EnclosureSlot       string         `gorm:"column:enclosureslot" json:"enclosureslot"`
-	AssetName           string         `gorm:"column:assetname" json:"assetname"`
+	AssetName           string         `gorm:"column:assetname; index:idx_nodes_search,class:FULLTEXT" json:"assetname"`
 	SecZone             string         `gorm:"column:sec_zone" json:"sec_zone"`
 	LastBoot            time.Time      `gorm:"column:last_boot" json:"last_boot"`
 	ActionType          string         `gorm:"column:action_type; type:enum('push', 'pull'); default:pull" json:"action_type"`
@@ -104,6 +104,11 @@ func init() {
 	db.Register(&db.Table{
 		Name:  "nodes",
 		Entry: Node{},
+		Search: &db.TableSearch{
+			Type: "node",
+			Name: "nodename",
+			Link: "/api/nodes/%d",
+		},
 	})
 }
 
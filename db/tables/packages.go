@@ -0,0 +1,55 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// NodePackage is a package installed on a node, as reported by the
+	// node inventory.
+	NodePackage struct {
+		ID          uint      `gorm:"primarykey" json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		NodeID      string    `gorm:"column:node_id; size:36; uniqueIndex:idx_node_pkg_key" json:"node_id" validate:"readonly"`
+		Name        string    `gorm:"column:pkg_name; size:255; uniqueIndex:idx_node_pkg_key; index" json:"pkg_name" validate:"required" example:"openssl"`
+		Version     string    `gorm:"column:pkg_version; size:128; uniqueIndex:idx_node_pkg_key" json:"pkg_version" example:"1:1.1.1k-7.el8_6"`
+		Arch        string    `gorm:"column:pkg_arch; size:32; uniqueIndex:idx_node_pkg_key" json:"pkg_arch" example:"x86_64"`
+		Type        string    `gorm:"column:pkg_type; size:16" json:"pkg_type" example:"rpm"`
+		Sig         string    `gorm:"column:pkg_sig; size:64" json:"pkg_sig"`
+		InstallDate time.Time `gorm:"column:pkg_install_date" json:"pkg_install_date"`
+	}
+
+	// NodePatch is a patch installed on a node, as reported by the node
+	// inventory.
+	NodePatch struct {
+		ID          uint      `gorm:"primarykey" json:"id"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		NodeID      string    `gorm:"column:node_id; size:36; uniqueIndex:idx_node_patches_key" json:"node_id" validate:"readonly"`
+		Num         string    `gorm:"column:patch_num; size:64; uniqueIndex:idx_node_patches_key; index" json:"patch_num" validate:"required" example:"KB5034441"`
+		Rev         string    `gorm:"column:patch_rev; size:32; uniqueIndex:idx_node_patches_key" json:"patch_rev"`
+		InstallDate time.Time `gorm:"column:patch_install_date" json:"patch_install_date"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "node_pkg",
+		Entry: NodePackage{},
+	})
+	db.Register(&db.Table{
+		Name:  "node_patches",
+		Entry: NodePatch{},
+	})
+}
+
+func (NodePackage) TableName() string {
+	return "node_pkg"
+}
+
+func (NodePatch) TableName() string {
+	return "node_patches"
+}
@@ -57,13 +57,13 @@ type Service struct {
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	Svcname          string         `gorm:"column:svcname; index" json:"svcname"`
-	SvcID            string         `gorm:"column:svc_id; size:36; uniqueIndex; size:36" json:"svc_id"`
+	Svcname          string         `gorm:"column:svcname; index; index:idx_services_search,class:FULLTEXT" json:"svcname" validate:"required"`
+	SvcID            string         `gorm:"column:svc_id; size:36; uniqueIndex; size:36" json:"svc_id" validate:"readonly"`
 	ClusterID        string         `gorm:"column:cluster_id; size:36; index" json:"cluster_id"`
-	SvcConfigUpdated time.Time      `gorm:"column:svc_config_updated" json:"svc_config_updated"`
+	SvcConfigUpdated time.Time      `gorm:"column:svc_config_updated" json:"svc_config_updated" validate:"readonly"`
 	SvcSnoozeTill    time.Time      `gorm:"column:svc_snooze_till" json:"svc_snooze_till"`
-	SvcApp           string         `gorm:"column:svc_app; index" json:"svc_app"`
-	SvcEnv           string         `gorm:"column:svc_env" json:"svc_env"`
+	SvcApp           string         `gorm:"column:svc_app; index; index:idx_services_search,class:FULLTEXT" json:"svc_app"`
+	SvcEnv           string         `gorm:"column:svc_env" json:"svc_env" validate:"enum=DEV|DRP|FOR|INT|PRA|PRD|PRJ|PPRD|QUAL|REC|STG|TMP|TST|UAT"`
 	SvcTopology      string         `gorm:"column:svc_topology" json:"svc_topology"`
 	SvcStatus        string         `gorm:"column:svc_status; size:10; index" json:"svc_status"`
 	SvcAvailStatus   string         `gorm:"column:svc_avail_status; size:10; index" json:"svc_avail_status"`
@@ -75,8 +75,8 @@ type Service struct {
 	SvcFlexMinNodes  int            `gorm:"column:svc_flex_min_nodes" json:"svc_flex_min_nodes"`
 	SvcFlexMaxNodes  int            `gorm:"column:svc_flex_max_nodes" json:"svc_flex_max_nodes"`
 	SvcWave          string         `gorm:"column:svc_wave; default:'3'" json:"svc_wave"`
-	SvcConfig        string         `gorm:"column:svc_config; type:mediumtext" json:"svc_wave"`
-	SvcComment       string         `gorm:"column:svc_config; type:mediumtext" json:"svc_wave"`
+	SvcConfig        string         `gorm:"column:svc_config; type:mediumtext" json:"svc_config" validate:"readonly"`
+	SvcComment       string         `gorm:"column:svc_comment; size:1000" json:"svc_comment"`
 	Updated          time.Time      `gorm:"column:created" json:"created"`
 	Created          time.Time      `gorm:"column:updated" json:"updated"`
 }
@@ -85,6 +85,11 @@ func init() {
 	db.Register(&db.Table{
 		Name:  "services",
 		Entry: Service{},
+		Search: &db.TableSearch{
+			Type: "service",
+			Name: "svcname",
+			Link: "/api/services/%d",
+		},
 	})
 }
 
@@ -0,0 +1,55 @@
+package tables
+
+import (
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type (
+	// ServiceConfig is a version of a service configuration, pushed by a
+	// node. A version is stored each time the configuration changes, the
+	// last one being also the services svc_config.
+	ServiceConfig struct {
+		ID        uint      `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		SvcID     string    `gorm:"column:svc_id; size:36; index" json:"svc_id" validate:"readonly"`
+		NodeID    string    `gorm:"column:node_id; size:36" json:"node_id" validate:"readonly"`
+		Hash      string    `gorm:"column:cfg_hash; size:64; index" json:"cfg_hash" validate:"readonly"`
+		Text      string    `gorm:"column:cfg_text; type:mediumtext" json:"cfg_text" validate:"readonly"`
+	}
+
+	// ServiceConfigKeyword is a keyword of the last version of a service
+	// configuration. The cfg_group is the driver group of the section, for
+	// example fs for the fs#1 section, and the cfg_rtype the resource type,
+	// for example fs.ext4, or the driver group if the section has no type
+	// keyword.
+	ServiceConfigKeyword struct {
+		ID      uint   `gorm:"primarykey" json:"id"`
+		SvcID   string `gorm:"column:svc_id; size:36; index" json:"svc_id" validate:"readonly"`
+		Section string `gorm:"column:cfg_section; size:64" json:"cfg_section" validate:"readonly" example:"fs#1"`
+		Group   string `gorm:"column:cfg_group; size:32; index" json:"cfg_group" validate:"readonly" example:"fs"`
+		RType   string `gorm:"column:cfg_rtype; size:64; index" json:"cfg_rtype" validate:"readonly" example:"fs.ext4"`
+		Keyword string `gorm:"column:cfg_keyword; size:128; index" json:"cfg_keyword" validate:"readonly" example:"mnt"`
+		Value   string `gorm:"column:cfg_value; type:text" json:"cfg_value" validate:"readonly" example:"/srv"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "svc_configs",
+		Entry: ServiceConfig{},
+	})
+	db.Register(&db.Table{
+		Name:  "svc_config_keywords",
+		Entry: ServiceConfigKeyword{},
+	})
+}
+
+func (ServiceConfig) TableName() string {
+	return "svc_configs"
+}
+
+func (ServiceConfigKeyword) TableName() string {
+	return "svc_config_keywords"
+}
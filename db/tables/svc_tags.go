@@ -17,10 +17,10 @@ type ServiceTag struct {
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"deleted_at"`
-	SvcID         string         `gorm:"column:svc_id; size:36" json:"svc_id"`
-	TagID         string         `gorm:"column:tag_id; size:40; index" json:"tag_id"`
+	SvcID         string         `gorm:"column:svc_id; size:36" json:"svc_id" validate:"required"`
+	TagID         string         `gorm:"column:tag_id; size:40; index" json:"tag_id" validate:"required"`
 	TagAttachData datatypes.JSON `gorm:"column:tag_attach_data; type:text" json:"tag_attach_data"`
-	Created       time.Time      `gorm:"column:created; autoCreateTime" json:"created"`
+	Created       time.Time      `gorm:"column:created; autoCreateTime" json:"created" validate:"readonly"`
 }
 
 func init() {
@@ -69,7 +69,7 @@ func getServiceTagBySvcIDAndTagID(r *http.Request, svcID, tagID string) ([]Servi
 		db.TableRequestWithFilters(false),
 		db.TableRequestWithPaging(false),
 	).TX(r)
-	if err := tx.Where("svc_tags.node_id = ? AND svc_tags.tag_id = ?", svcID, tagID).Find(&data).Error; err != nil {
+	if err := tx.Where("svc_tags.svc_id = ? AND svc_tags.tag_id = ?", svcID, tagID).Find(&data).Error; err != nil {
 		return data, err
 	}
 	return data, nil
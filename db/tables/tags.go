@@ -18,10 +18,10 @@ type Tag struct {
 	UpdatedAt  time.Time      `json:"updated_at"`
 	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 	TagID      string         `gorm:"->;column:tag_id; size:40; index; type:GENERATED ALWAYS AS (sha(tag_name)) STORED" json:"tag_id"`
-	TagName    string         `gorm:"column:tag_name; unique; size:128" json:"tag_name"`
+	TagName    string         `gorm:"column:tag_name; unique; size:128; index:idx_tags_search,class:FULLTEXT" json:"tag_name" validate:"required"`
 	TagExclude string         `gorm:"column:tag_exclude; size:128" json:"tag_exclude"`
 	TagData    string         `gorm:"column:tag_data; type:text" json:"tag_data"`
-	TagCreated time.Time      `gorm:"column:tag_created; autoCreateTime" json:"tag_created"`
+	TagCreated time.Time      `gorm:"column:tag_created; autoCreateTime" json:"tag_created" validate:"readonly"`
 }
 
 var (
@@ -32,6 +32,11 @@ func init() {
 	db.Register(&db.Table{
 		Name:  "tags",
 		Entry: Tag{},
+		Search: &db.TableSearch{
+			Type: "tag",
+			Name: "tag_name",
+			Link: "/api/tags/%d",
+		},
 	})
 }
 
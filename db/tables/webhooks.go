@@ -0,0 +1,112 @@
+package tables
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"gorm.io/datatypes"
+)
+
+type (
+	// Webhook is a subscription of an url to the table entries change
+	// events. The deliveries are signed with the secret.
+	Webhook struct {
+		ID        uint           `gorm:"primarykey" json:"id"`
+		CreatedAt time.Time      `json:"created_at"`
+		UpdatedAt time.Time      `json:"updated_at"`
+		UserID    uint           `gorm:"column:user_id; index" json:"user_id" validate:"readonly"`
+		URL       string         `gorm:"column:url; size:512" json:"url" validate:"required"`
+		Secret    string         `gorm:"column:secret; size:128" json:"secret,omitempty"`
+		Events    string         `gorm:"column:events; size:64" json:"events" example:"create,delete"`
+		Tables    string         `gorm:"column:tables; size:512" json:"tables" example:"nodes"`
+		Filters   datatypes.JSON `gorm:"column:filters; type:text" json:"filters" swaggertype:"array,string"`
+		Enabled   bool           `gorm:"column:enabled; default:true" json:"enabled"`
+	}
+
+	// WebhookDelivery is the delivery of an event to a webhook url, and
+	// its attempts log. A delivery still failing after the maximum
+	// number of attempts is "dead".
+	WebhookDelivery struct {
+		ID            uint       `gorm:"primarykey" json:"id"`
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+		WebhookID     uint       `gorm:"column:webhook_id; index" json:"webhook_id"`
+		EventID       uint64     `gorm:"column:event_id" json:"event_id"`
+		EventKind     string     `gorm:"column:event_kind; size:16" json:"event_kind"`
+		Payload       string     `gorm:"column:payload; type:text" json:"payload"`
+		Status        string     `gorm:"column:status; type:enum('pending','failed','delivered','dead'); default:pending; index:idx_webhook_deliveries_due" json:"status"`
+		Attempts      int        `gorm:"column:attempts; default:0" json:"attempts"`
+		NextAttemptAt time.Time  `gorm:"column:next_attempt_at; index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+		LastAttemptAt *time.Time `gorm:"column:last_attempt_at" json:"last_attempt_at"`
+		ResponseCode  int        `gorm:"column:response_code" json:"response_code"`
+		Error         string     `gorm:"column:error; type:text" json:"error"`
+	}
+)
+
+func init() {
+	db.Register(&db.Table{
+		Name:  "webhooks",
+		Entry: Webhook{},
+	})
+	db.Register(&db.Table{
+		Name:  "webhook_deliveries",
+		Entry: WebhookDelivery{},
+	})
+}
+
+func WebhookFromCtx(r *http.Request) []Webhook {
+	i := r.Context().Value("webhook")
+	if i == nil {
+		return []Webhook{}
+	}
+	return i.([]Webhook)
+}
+
+func WebhookCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		n, err := readableGetWebhookByID(r, id)
+		if err != nil {
+			http.Error(w, fmt.Sprint(err), 500)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "webhook", n)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func readableGetWebhookByID(r *http.Request, id string) ([]Webhook, error) {
+	data := make([]Webhook, 0)
+	tx := db.Tab("webhooks").Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+	if err := tx.Where("webhooks.id = ?", id).Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// WebhooksWithoutSecret returns the webhooks with their secret blanked, for
+// responses.
+func WebhooksWithoutSecret(l []Webhook) []Webhook {
+	m := make([]Webhook, len(l))
+	for i, t := range l {
+		t.Secret = ""
+		m[i] = t
+	}
+	return m
+}
+
+// GetEnabledWebhooks returns the webhooks to deliver the events to.
+func GetEnabledWebhooks() ([]Webhook, error) {
+	data := make([]Webhook, 0)
+	if err := db.DB().Where("enabled = ?", true).Find(&data).Error; err != nil {
+		return data, err
+	}
+	return data, nil
+}
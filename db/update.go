@@ -0,0 +1,100 @@
+package db
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/opensvc/collector-api/events"
+	"github.com/opensvc/collector-api/funcopt"
+	"github.com/opensvc/collector-api/xmap"
+	"gorm.io/gorm/schema"
+)
+
+type (
+	update struct {
+		version *time.Time
+	}
+)
+
+// UpdateWithVersion makes the update conditional to the entry updated_at
+// being unchanged since the entry was read with this value. Update returns
+// ErrPreconditionFailed if the entry was modified meanwhile.
+func UpdateWithVersion(updatedAt time.Time) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*update)
+		t.version = &updatedAt
+		return nil
+	})
+}
+
+// Update sets the properties of the table entry with the specified id, and
+// publishes the change event. The data properties are expected to be
+// validated by Validate.
+func (t Table) Update(id uint, data map[string]interface{}, opts ...funcopt.O) error {
+	var u update
+	if err := funcopt.Apply(&u, opts...); err != nil {
+		return err
+	}
+	rules, err := t.fieldRules()
+	if err != nil {
+		return err
+	}
+	values, err := rules.columnValues(data)
+	if err != nil {
+		return err
+	}
+	if _, ok := rules["updated_at"]; ok {
+		values["updated_at"] = time.Now()
+	}
+	props := xmap.Keys(values)
+	tx := t.Table().Select(props).Where("id = ?", id)
+	if u.version != nil {
+		tx = tx.Where("updated_at = ?", *u.version)
+	}
+	if result := tx.Updates(values); result.Error != nil {
+		return result.Error
+	} else if u.version != nil && result.RowsAffected == 0 {
+		// the updated_at change makes a matched entry always affected
+		return ErrPreconditionFailed
+	}
+	changed := xmap.Keys(data)
+	sort.Strings(changed)
+	events.Publish(events.Update, t.Name, id, changed...)
+	return nil
+}
+
+// columnValues converts the json decoded property values to values the
+// database driver can store: datetime strings are parsed, and objects or
+// lists are serialized for json columns.
+func (t fieldRules) columnValues(data map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for k, v := range data {
+		switch i := v.(type) {
+		case map[string]interface{}, []interface{}:
+			b, err := json.Marshal(i)
+			if err != nil {
+				return nil, err
+			}
+			values[k] = string(b)
+		case string:
+			if t[k].Type == schema.Time {
+				values[k] = parseTime(i)
+			} else {
+				values[k] = i
+			}
+		default:
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+func parseTime(s string) time.Time {
+	for _, layout := range timeLayouts {
+		if tm, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return tm
+		}
+	}
+	return time.Time{}
+}
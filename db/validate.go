@@ -0,0 +1,346 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/opensvc/collector-api/funcopt"
+	"gorm.io/gorm/schema"
+)
+
+type (
+	// FieldError describes a property value rejected by the validation
+	// rules of a table.
+	FieldError struct {
+		Index int    `json:"index"`
+		Field string `json:"field"`
+		Error string `json:"error"`
+	}
+	FieldErrors []FieldError
+
+	// fieldRule is the set of constraints a property value must satisfy,
+	// merged from the gorm schema and the "validate" struct tag of the
+	// table entry.
+	fieldRule struct {
+		Name     string
+		Type     schema.DataType
+		Size     int
+		Enum     []string
+		Email    bool
+		Required bool
+		ReadOnly bool
+		Nullable bool
+
+		// Sortable is false for the text, blob and json columns.
+		Sortable bool
+	}
+	fieldRules map[string]fieldRule
+
+	validation struct {
+		create   bool
+		keys     map[string]bool
+		readOnly map[string]bool
+		writable map[string]bool
+		funcs    []func(map[string]interface{}) FieldErrors
+	}
+)
+
+var (
+	schemaCache = &sync.Map{}
+	reEnumType  = regexp.MustCompile(`(?i)^enum\s*\((.*)\)$`)
+	timeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+	}
+)
+
+func (t FieldErrors) Error() string {
+	l := make([]string, len(t))
+	for i, e := range t {
+		l[i] = fmt.Sprintf("%s: %s", e.Field, e.Error)
+	}
+	return strings.Join(l, ", ")
+}
+
+// WithIndex sets the index of the entry the errors were found in, for
+// requests submitting a list of entries.
+func (t FieldErrors) WithIndex(i int) FieldErrors {
+	for j := range t {
+		t[j].Index = i
+	}
+	return t
+}
+
+// isSortable returns false if the field column is a text, blob or json
+// column, which the database can not order by efficiently, or at all.
+func isSortable(f *schema.Field) bool {
+	if f.GORMDataType == schema.Bytes {
+		return false
+	}
+	typ := strings.ToLower(string(f.DataType))
+	return typ != "json" && !strings.Contains(typ, "text") && !strings.Contains(typ, "blob")
+}
+
+// fieldRules returns the validation rules indexed by column name.
+//
+// The gorm schema provides the type, the size and the enum values of
+// "type:enum(...)" columns. Primary keys, timestamps and columns without
+// write permission are read-only. The "validate" struct tag can add:
+//
+//	required        the property must be set on create
+//	readonly        the property can not be set by clients
+//	email           the value must be a valid email address
+//	enum=A|B|C      the value must be one of A, B or C
+func (t Table) fieldRules() (fieldRules, error) {
+	s, err := schema.Parse(t.Entry, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+	rules := make(fieldRules)
+	for _, f := range s.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		rule := fieldRule{
+			Name:     f.DBName,
+			Type:     f.GORMDataType,
+			Nullable: !f.NotNull && !f.PrimaryKey,
+			Sortable: isSortable(f),
+		}
+		switch f.Name {
+		case "CreatedAt", "UpdatedAt", "DeletedAt":
+			rule.ReadOnly = true
+		}
+		if f.PrimaryKey || (!f.Creatable && !f.Updatable) {
+			rule.ReadOnly = true
+		}
+		if typ, ok := f.TagSettings["TYPE"]; ok {
+			if l := reEnumType.FindStringSubmatch(strings.TrimSpace(typ)); l != nil {
+				rule.Enum = parseEnumType(l[1])
+			}
+		} else if f.GORMDataType == schema.String {
+			if _, ok := f.TagSettings["SIZE"]; ok {
+				rule.Size = f.Size
+			} else {
+				rule.Size = defaultStringSize
+			}
+		}
+		for _, s := range strings.Split(f.Tag.Get("validate"), ",") {
+			s = strings.TrimSpace(s)
+			switch {
+			case s == "required":
+				rule.Required = true
+			case s == "readonly":
+				rule.ReadOnly = true
+			case s == "email":
+				rule.Email = true
+			case strings.HasPrefix(s, "enum="):
+				rule.Enum = strings.Split(strings.TrimPrefix(s, "enum="), "|")
+			}
+		}
+		rules[f.DBName] = rule
+	}
+	return rules, nil
+}
+
+func parseEnumType(s string) []string {
+	l := strings.Split(s, ",")
+	for i, v := range l {
+		l[i] = strings.Trim(strings.TrimSpace(v), `'"`)
+	}
+	return l
+}
+
+// Validate verifies the properties of a create or update request payload
+// against the table validation rules, and returns the list of invalid
+// properties.
+func (t Table) Validate(data map[string]interface{}, opts ...funcopt.O) FieldErrors {
+	v := validation{
+		keys:     make(map[string]bool),
+		readOnly: make(map[string]bool),
+		writable: make(map[string]bool),
+	}
+	_ = funcopt.Apply(&v, opts...)
+	errs := make(FieldErrors, 0)
+	rules, err := t.fieldRules()
+	if err != nil {
+		return append(errs, FieldError{Field: t.Name, Error: err.Error()})
+	}
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rule, ok := rules[k]
+		if !ok {
+			errs = append(errs, FieldError{Field: k, Error: "unknown property"})
+			continue
+		}
+		if (rule.ReadOnly && !v.keys[k]) || v.readOnly[k] || (len(v.writable) > 0 && !v.writable[k] && !v.keys[k]) {
+			errs = append(errs, FieldError{Field: k, Error: "read-only property"})
+			continue
+		}
+		if err := rule.check(data[k]); err != nil {
+			errs = append(errs, FieldError{Field: k, Error: err.Error()})
+		}
+	}
+	if v.create {
+		for _, k := range rules.required() {
+			if _, ok := data[k]; !ok {
+				errs = append(errs, FieldError{Field: k, Error: "required property"})
+			}
+		}
+	}
+	for _, f := range v.funcs {
+		errs = append(errs, f(data)...)
+	}
+	return errs
+}
+
+func (t fieldRules) required() []string {
+	l := make([]string, 0)
+	for k, rule := range t {
+		if rule.Required {
+			l = append(l, k)
+		}
+	}
+	sort.Strings(l)
+	return l
+}
+
+func (t fieldRule) check(i interface{}) error {
+	if i == nil {
+		if t.Required {
+			return fmt.Errorf("can not be null")
+		}
+		return nil
+	}
+	switch t.Type {
+	case schema.String:
+		s, ok := i.(string)
+		if !ok {
+			return fmt.Errorf("must be a string")
+		}
+		if t.Required && s == "" {
+			return fmt.Errorf("can not be empty")
+		}
+		if t.Size > 0 && utf8.RuneCountInString(s) > t.Size {
+			return fmt.Errorf("must be at most %d characters long", t.Size)
+		}
+		if len(t.Enum) > 0 && !t.hasEnum(s) {
+			return fmt.Errorf("must be one of %s", strings.Join(t.Enum, ", "))
+		}
+		if t.Email && s != "" {
+			if _, err := mail.ParseAddress(s); err != nil {
+				return fmt.Errorf("must be an email address")
+			}
+		}
+	case schema.Int, schema.Uint:
+		f, ok := i.(float64)
+		if !ok {
+			return fmt.Errorf("must be an integer")
+		}
+		if f != math.Trunc(f) {
+			return fmt.Errorf("must be an integer")
+		}
+		if t.Type == schema.Uint && f < 0 {
+			return fmt.Errorf("must be a positive integer")
+		}
+	case schema.Float:
+		if _, ok := i.(float64); !ok {
+			return fmt.Errorf("must be a number")
+		}
+	case schema.Bool:
+		if _, ok := i.(bool); !ok {
+			return fmt.Errorf("must be a boolean")
+		}
+	case schema.Time:
+		s, ok := i.(string)
+		if !ok || !isTime(s) {
+			return fmt.Errorf("must be a date or a RFC3339 datetime")
+		}
+	}
+	return nil
+}
+
+func (t fieldRule) hasEnum(s string) bool {
+	for _, v := range t.Enum {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func isTime(s string) bool {
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWithCreate enables the required properties verification.
+func ValidateWithCreate(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*validation)
+		t.create = v
+		return nil
+	})
+}
+
+// ValidateWithKeys allows read-only properties used to identify the entry
+// to update.
+func ValidateWithKeys(keys ...string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*validation)
+		for _, k := range keys {
+			t.keys[k] = true
+		}
+		return nil
+	})
+}
+
+// ValidateWithReadOnly makes properties read-only in addition to the
+// read-only properties of the table rules.
+func ValidateWithReadOnly(keys ...string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*validation)
+		for _, k := range keys {
+			t.readOnly[k] = true
+		}
+		return nil
+	})
+}
+
+// ValidateWithWritable makes all properties but these and the keys
+// read-only.
+func ValidateWithWritable(keys ...string) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*validation)
+		for _, k := range keys {
+			t.writable[k] = true
+		}
+		return nil
+	})
+}
+
+// ValidateWithFunc adds a verification of the properties the table rules
+// can not express.
+func ValidateWithFunc(f func(map[string]interface{}) FieldErrors) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*validation)
+		t.funcs = append(t.funcs, f)
+		return nil
+	})
+}
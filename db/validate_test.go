@@ -0,0 +1,86 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validateTestEntry struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Name      string    `gorm:"column:name; size:8" json:"name" validate:"required"`
+	Env       string    `gorm:"column:env" json:"env" validate:"enum=PRD|TST"`
+	Level     string    `gorm:"column:level; type:enum('info','error')" json:"level"`
+	Email     string    `gorm:"column:email" json:"email" validate:"email"`
+	Count     int       `gorm:"column:count" json:"count"`
+	Hash      string    `gorm:"->;column:hash" json:"hash"`
+}
+
+func TestValidate(t *testing.T) {
+	table := Table{Name: "validate_test", Entry: validateTestEntry{}}
+	tests := map[string]struct {
+		data     map[string]interface{}
+		create   bool
+		keys     []string
+		writable []string
+		output   []string
+	}{
+		"valid update": {
+			data:   map[string]interface{}{"env": "PRD", "level": "error", "count": 2.0},
+			output: []string{},
+		},
+		"missing required on create": {
+			data:   map[string]interface{}{"env": "PRD"},
+			create: true,
+			output: []string{"name"},
+		},
+		"read-only properties": {
+			data:   map[string]interface{}{"id": 1.0, "created_at": "2021-01-01", "hash": "a"},
+			output: []string{"created_at", "hash", "id"},
+		},
+		"read-only key": {
+			data:   map[string]interface{}{"id": 1.0},
+			keys:   []string{"id"},
+			output: []string{},
+		},
+		"writable whitelist": {
+			data:     map[string]interface{}{"id": 1.0, "name": "a", "env": "PRD", "count": 2.0},
+			keys:     []string{"id"},
+			writable: []string{"name", "count"},
+			output:   []string{"env"},
+		},
+		"unknown property": {
+			data:   map[string]interface{}{"foo": "bar"},
+			output: []string{"foo"},
+		},
+		"invalid values": {
+			data:   map[string]interface{}{"name": "123456789", "env": "DEV", "level": "debug", "email": "foo", "count": 1.5},
+			output: []string{"count", "email", "env", "level", "name"},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		errs := table.Validate(test.data, ValidateWithCreate(test.create), ValidateWithKeys(test.keys...), ValidateWithWritable(test.writable...))
+		fields := make([]string, len(errs))
+		for i, e := range errs {
+			fields[i] = e.Field
+		}
+		assert.Equal(t, test.output, fields)
+	}
+}
+
+func TestValidateWithFunc(t *testing.T) {
+	table := Table{Name: "validate_test", Entry: validateTestEntry{}}
+	f := func(data map[string]interface{}) FieldErrors {
+		if data["name"] == "root" {
+			return FieldErrors{{Field: "name", Error: "reserved name"}}
+		}
+		return nil
+	}
+	errs := table.Validate(map[string]interface{}{"name": "root"}, ValidateWithFunc(f))
+	assert.Equal(t, FieldErrors{{Field: "name", Error: "reserved name"}}, errs)
+	errs = table.Validate(map[string]interface{}{"name": "foo"}, ValidateWithFunc(f))
+	assert.Len(t, errs, 0)
+}
@@ -0,0 +1,189 @@
+// Package diff computes the line differences between two texts, and
+// formats them as a unified diff.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op is the kind of a line difference.
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpDelete
+	OpInsert
+)
+
+type (
+	// Line is a line of the difference between two texts.
+	Line struct {
+		Op   Op
+		Text string
+	}
+
+	// hunk is a group of changed lines, with their context lines, and
+	// their position in the old and new texts, starting at 1.
+	hunk struct {
+		oldStart, oldLines int
+		newStart, newLines int
+		lines              []Line
+	}
+)
+
+// DefaultContext is the number of unchanged lines around the changes in a
+// unified diff hunk.
+const DefaultContext = 3
+
+// SplitLines returns the lines of the text, without line terminators. A
+// final line terminator does not add an empty line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	return strings.Split(s, "\n")
+}
+
+// Lines returns the differences between the old and new lines, as the
+// shortest edit script: the lines of a longest common subsequence are
+// equal, the other old lines deleted and the other new lines inserted.
+// The deletions are listed before the insertions at the same position.
+func Lines(a, b []string) []Line {
+	// lcs[i][j] is the length of the longest common subsequence of
+	// a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	l := make([]Line, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			l = append(l, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			l = append(l, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			l = append(l, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		l = append(l, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		l = append(l, Line{Op: OpInsert, Text: b[j]})
+	}
+	return l
+}
+
+// Unified returns the unified diff of the old and new texts, labeled with
+// the old and new names, with context unchanged lines around the changes.
+// It returns an empty string if the texts have the same lines.
+func Unified(oldName, newName, a, b string, context int) string {
+	hunks := makeHunks(Lines(SplitLines(a), SplitLines(b)), context)
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines))
+		for _, line := range h.lines {
+			switch line.Op {
+			case OpEqual:
+				sb.WriteString(" ")
+			case OpDelete:
+				sb.WriteString("-")
+			case OpInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(line.Text)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange formats the position of a hunk in a text the way diff -u
+// does: the line count is omitted if 1, and the start is the line before
+// the hunk if the hunk has no line in the text.
+func hunkRange(start, lines int) string {
+	switch lines {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	default:
+		return fmt.Sprintf("%d,%d", start, lines)
+	}
+}
+
+// makeHunks groups the changed lines separated by at most 2*context
+// unchanged lines in hunks.
+func makeHunks(l []Line, context int) []hunk {
+	if context < 0 {
+		context = 0
+	}
+	changed := make([]int, 0)
+	for i, line := range l {
+		if line.Op != OpEqual {
+			changed = append(changed, i)
+		}
+	}
+	hunks := make([]hunk, 0)
+	for k := 0; k < len(changed); {
+		first := changed[k]
+		last := first
+		for k++; k < len(changed) && changed[k]-last <= 2*context+1; k++ {
+			last = changed[k]
+		}
+		start := first - context
+		if start < 0 {
+			start = 0
+		}
+		end := last + context + 1
+		if end > len(l) {
+			end = len(l)
+		}
+		// position of the hunk start in the old and new texts
+		oldLine, newLine := 1, 1
+		for _, line := range l[:start] {
+			if line.Op != OpInsert {
+				oldLine++
+			}
+			if line.Op != OpDelete {
+				newLine++
+			}
+		}
+		h := hunk{oldStart: oldLine, newStart: newLine, lines: l[start:end]}
+		for _, line := range h.lines {
+			if line.Op != OpInsert {
+				h.oldLines++
+			}
+			if line.Op != OpDelete {
+				h.newLines++
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLines(t *testing.T) {
+	assert.Equal(t, []string{}, SplitLines(""))
+	assert.Equal(t, []string{"a", "b"}, SplitLines("a\nb\n"))
+	assert.Equal(t, []string{"a", "b"}, SplitLines("a\r\nb"))
+	assert.Equal(t, []string{"a", ""}, SplitLines("a\n\n"))
+}
+
+func TestLines(t *testing.T) {
+	l := Lines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	assert.Equal(t, []Line{
+		{Op: OpEqual, Text: "a"},
+		{Op: OpDelete, Text: "b"},
+		{Op: OpInsert, Text: "x"},
+		{Op: OpEqual, Text: "c"},
+		{Op: OpInsert, Text: "d"},
+	}, l)
+}
+
+func TestUnified(t *testing.T) {
+	tests := map[string]struct {
+		a, b     string
+		context  int
+		expected string
+	}{
+		"same": {
+			a:        "a\nb\n",
+			b:        "a\nb",
+			context:  3,
+			expected: "",
+		},
+		"change": {
+			a:       "[DEFAULT]\nnodes = n1\n[fs#1]\ntype = ext4\n",
+			b:       "[DEFAULT]\nnodes = n1 n2\n[fs#1]\ntype = ext4\n",
+			context: 3,
+			expected: "--- v1\n+++ v2\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				" [DEFAULT]\n-nodes = n1\n+nodes = n1 n2\n [fs#1]\n type = ext4\n",
+		},
+		"from empty": {
+			a:       "",
+			b:       "a\nb\n",
+			context: 3,
+			expected: "--- v1\n+++ v2\n" +
+				"@@ -0,0 +1,2 @@\n" +
+				"+a\n+b\n",
+		},
+		"to empty": {
+			a:       "a\n",
+			b:       "",
+			context: 3,
+			expected: "--- v1\n+++ v2\n" +
+				"@@ -1 +0,0 @@\n" +
+				"-a\n",
+		},
+		"separate hunks": {
+			a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:       "0\n2\n3\n4\n5\n6\n7\n8\n9x\n",
+			context: 1,
+			expected: "--- v1\n+++ v2\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				"-1\n+0\n 2\n" +
+				"@@ -8,2 +8,2 @@\n" +
+				" 8\n-9\n+9x\n",
+		},
+		"merged hunks": {
+			a:       "1\n2\n3\n4\n5\n",
+			b:       "0\n2\n3\n4\n5x\n",
+			context: 2,
+			expected: "--- v1\n+++ v2\n" +
+				"@@ -1,5 +1,5 @@\n" +
+				"-1\n+0\n 2\n 3\n 4\n-5\n+5x\n",
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, Unified("v1", "v2", test.a, test.b, test.context))
+	}
+}
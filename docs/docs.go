@@ -31,87 +31,7 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/node/token": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get an authentication token from a node's credentials submitted with basic login.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Get a node authentication token",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.TokenResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/user/token": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get an authentication token from a user's credentials submitted with basic login.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Get a user authentication token",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/routes.TokenResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/nodes": {
+        "/actions": {
             "get": {
                 "security": [
                     {
@@ -121,17 +41,19 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "List nodes",
+                "description": "List the actions run by the nodes on the services of the apps published to the user.\nThe status is empty while the action is running, then ok, warn or err.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "nodes"
+                    "actions"
                 ],
-                "summary": "List nodes",
+                "summary": "List service actions",
                 "parameters": [
                     {
                         "type": "string",
@@ -145,6 +67,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -156,10 +93,16 @@ var doc = `{
                         "items": {
                             "type": "string"
                         },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example status=err",
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -172,11 +115,57 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -186,6 +175,18 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -193,7 +194,9 @@ var doc = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/actions/begin": {
             "post": {
                 "security": [
                     {
@@ -203,7 +206,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "The app code of the nodes is forced to one the user is responsible of.\nThe team responsible of the nodes defaults to the user's primary group.\nThe user must be in the NodeManager privilege group.",
+                "description": "Requires node authentication, from a node running the services.\nThe actions are logged with an empty status and the current begin date. The node must end them with their index in database.",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,19 +214,19 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nodes"
+                    "actions"
                 ],
-                "summary": "Create or update nodes",
+                "summary": "Begin service actions",
                 "parameters": [
                     {
-                        "description": "list of nodes to create or update",
-                        "name": "nodes",
+                        "description": "list of actions to begin",
+                        "name": "actions",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.Node"
+                                "$ref": "#/definitions/tables.SvcAction"
                             }
                         }
                     }
@@ -234,10 +237,22 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.Node"
+                                "$ref": "#/definitions/tables.SvcAction"
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -247,8 +262,8 @@ var doc = `{
                 }
             }
         },
-        "/nodes/tags": {
-            "get": {
+        "/actions/end": {
+            "post": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -257,6 +272,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requires node authentication, from the node that began the actions.\nThe entries are the index in database of the actions, their ok, warn or err status, and their optional status_log. The end date and the duration of the actions are set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -264,61 +280,43 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "actions"
                 ],
-                "summary": "List tags attachments to nodes",
+                "summary": "End service actions",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
+                        "description": "list of actions to end",
+                        "name": "actions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.SvcAction"
+                            }
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.SvcAction"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
                         }
                     },
                     "500": {
@@ -330,7 +328,7 @@ var doc = `{
                 }
             }
         },
-        "/nodes/tags/{id}": {
+        "/actions/failed": {
             "get": {
                 "security": [
                     {
@@ -340,6 +338,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Count the actions ended with the err status since a duration ago, on the services of the apps published to the user, per app, service or node.\nThe entries have the failed count and the last failed action begin date, and the app, the svc_id and svcname, or the node_id and nodename, depending on the by parameter.",
                 "consumes": [
                     "application/json"
                 ],
@@ -347,17 +346,35 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags",
-                    "nodes"
+                    "actions"
                 ],
-                "summary": "Show a tag attachment to a node",
+                "summary": "Count the failed service actions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "the duration to count the failed actions over (default 24h)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "app",
+                            "service",
+                            "node"
+                        ],
+                        "type": "string",
+                        "description": "the grouping of the counts (default app)",
+                        "name": "by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example action=start",
+                        "name": "filters",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -366,12 +383,12 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.NodeTag"
+                                "type": "object"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -385,7 +402,7 @@ var doc = `{
                 }
             }
         },
-        "/nodes/{id}": {
+        "/apps": {
             "get": {
                 "security": [
                     {
@@ -395,38 +412,147 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Show a node by index, id or name",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "nodes"
+                    "apps"
                 ],
-                "summary": "Show a node",
+                "summary": "List apps",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Node"
-                            }
+                            "$ref": "#/definitions/db.TableResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
@@ -438,8 +564,10 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/apps/{id}": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -448,7 +576,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "The user must be in the NodeManager privilege group.",
+                "description": "Show an app by index or name",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,25 +584,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nodes"
+                    "apps"
                 ],
-                "summary": "Update a node",
+                "summary": "Show an app",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
+                        "description": "the index of the entry in database, or name",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "node properties to create or update",
-                        "name": "nodes",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/tables.Node"
-                        }
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -483,18 +608,18 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.Node"
+                                "$ref": "#/definitions/tables.App"
                             }
                         }
                     },
-                    "401": {
-                        "description": "missing NodeManager privilege",
+                    "304": {
+                        "description": "Not Modified",
                         "schema": {
                             "type": "string"
                         }
                     },
                     "404": {
-                        "description": "the entry to update does not exist",
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -506,8 +631,10 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/node/token": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -516,43 +643,65 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a node by index, id or name.\nThe user must have the NodeManager privilege.\nThe user must be responsible for the node, via app responsibles.\nCascade delete on services instances, dashboard entries, checks, packages and patches.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get an authentication token from a node's credentials submitted with basic login.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "nodes"
+                    "auth"
                 ],
-                "summary": "Delete a node",
-                "parameters": [
+                "summary": "Get a node authentication token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/routes.TokenResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/user/token": {
+            "get": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Get an authentication token from a user's credentials submitted with basic login.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get a user authentication token",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Node"
-                            }
+                            "$ref": "#/definitions/routes.TokenResponse"
                         }
                     },
-                    "204": {
-                        "description": "No Content",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -564,7 +713,7 @@ var doc = `{
                 }
             }
         },
-        "/nodes/{id}/candidate_tags": {
+        "/checks": {
             "get": {
                 "security": [
                     {
@@ -574,16 +723,19 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "List the last check values pushed by the nodes of the apps published to the user, and their evaluation.\nThe chk_err is 1 if the chk_value is out of the [chk_low, chk_high] thresholds, selected by chk_threshold_provider.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "tags"
+                    "checks"
                 ],
-                "summary": "List existing tags not already attached to a node",
+                "summary": "List checks",
                 "parameters": [
                     {
                         "type": "string",
@@ -597,6 +749,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -608,10 +775,16 @@ var doc = `{
                         "items": {
                             "type": "string"
                         },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example chk_err=1",
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -624,11 +797,57 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -638,6 +857,18 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -645,10 +876,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/nodes/{id}/tags": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -657,6 +886,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requires node authentication.\nThe values replace the last values of the node for the same service, type and instance, and are evaluated against the thresholds.\nThe checks of the node with one of the pushed types, but not pushed, are removed.\nThe svc_id is the svc_id or svcname of a service running on the node, or empty for the node itself.",
                 "consumes": [
                     "application/json"
                 ],
@@ -664,101 +894,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "checks"
                 ],
-                "summary": "List tags attached to a node",
+                "summary": "Push check values",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "list of check values",
+                        "name": "checks",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Check"
+                            }
                         }
                     }
-                }
-            }
-        },
-        "/nodes/{id}/tags/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags",
-                    "nodes"
-                ],
-                "summary": "Show a tag attachment to a node",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
                 ],
                 "responses": {
                     "200": {
@@ -766,16 +917,22 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.NodeTag"
+                                "$ref": "#/definitions/tables.Check"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -785,7 +942,7 @@ var doc = `{
                 }
             }
         },
-        "/services": {
+        "/checks/thresholds": {
             "get": {
                 "security": [
                     {
@@ -795,16 +952,19 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "The chk_instance is a LIKE pattern of the check instances the threshold applies to.\nThe thresholds without node_id, svc_id and tag_id are the defaults.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "services"
+                    "checks"
                 ],
-                "summary": "List services",
+                "summary": "List checks thresholds",
                 "parameters": [
                     {
                         "type": "string",
@@ -818,6 +978,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -833,6 +1008,12 @@ var doc = `{
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -845,87 +1026,44 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
-                    {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/services/tags": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags"
-                ],
-                "summary": "List tags attachments to services",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
                         "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
                         "in": "query"
                     },
                     {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
                         "in": "query"
                     },
                     {
@@ -933,6 +1071,12 @@ var doc = `{
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -942,6 +1086,18 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -949,10 +1105,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/services/tags/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -961,6 +1115,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "The node_id, svc_id and tag_id are the uuid or name of the node, service and tag the threshold applies to.\nA threshold of a node or service requires the user to be responsible for its app. A tag or default threshold requires the CheckManager privilege.\nThe checks of the thresholds types are reevaluated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -968,17 +1123,21 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags",
-                    "services"
+                    "checks"
                 ],
-                "summary": "Show a tag attachment to a service",
+                "summary": "Create checks thresholds",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "list of thresholds to create",
+                        "name": "thresholds",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CheckThreshold"
+                            }
+                        }
                     }
                 ],
                 "responses": {
@@ -987,16 +1146,34 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.ServiceTag"
+                                "$ref": "#/definitions/tables.CheckThreshold"
                             }
                         }
                     },
+                    "401": {
+                        "description": "missing CheckManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1006,8 +1183,8 @@ var doc = `{
                 }
             }
         },
-        "/services/{id}": {
-            "get": {
+        "/checks/thresholds/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -1016,7 +1193,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Show a service by index, id or name",
+                "description": "A threshold of a node or service requires the user to be responsible for its app. A tag or default threshold requires the CheckManager privilege.\nThe checks of the threshold type are reevaluated.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1024,26 +1201,35 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "checks"
                 ],
-                "summary": "Show a service",
+                "summary": "Delete a checks threshold",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
+                        "type": "integer",
+                        "description": "the index of the threshold in database",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Service"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CheckManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "404": {
@@ -1061,7 +1247,7 @@ var doc = `{
                 }
             }
         },
-        "/services/{id}/candidate_tags": {
+        "/clusters": {
             "get": {
                 "security": [
                     {
@@ -1071,16 +1257,19 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "List the clusters with a member node of an app published to the user.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters"
                 ],
-                "summary": "List existing tags not already attached to a service",
+                "summary": "List clusters",
                 "parameters": [
                     {
                         "type": "string",
@@ -1096,7 +1285,22 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
                         "name": "order",
                         "in": "query"
                     },
@@ -1105,10 +1309,16 @@ var doc = `{
                         "items": {
                             "type": "string"
                         },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example cluster_name=prd%",
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -1121,87 +1331,44 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
-                    {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/services/{id}/tags": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags"
-                ],
-                "summary": "List tags attached to a service",
-                "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
                         "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
                         "in": "query"
                     },
                     {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
                         "in": "query"
                     },
                     {
@@ -1209,6 +1376,12 @@ var doc = `{
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1218,6 +1391,18 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1225,10 +1410,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/services/{id}/tags/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -1237,6 +1420,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
+                "description": "Requires node authentication.\nThe cluster is created, or updated if a cluster with the same cluster_id exists, and the node becomes a member of the cluster.\nAn existing cluster can only be updated by one of its member nodes.\nThe cluster secret is never sent, only the version, date and author node_id of its last rotation.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1244,17 +1428,18 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags",
-                    "services"
+                    "clusters"
                 ],
-                "summary": "Show a tag attachment to a service",
+                "summary": "Register the cluster of the node",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "the cluster of the node",
+                        "name": "cluster",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tables.Cluster"
+                        }
                     }
                 ],
                 "responses": {
@@ -1263,16 +1448,22 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.ServiceTag"
+                                "$ref": "#/definitions/tables.Cluster"
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1282,7 +1473,7 @@ var doc = `{
                 }
             }
         },
-        "/tags": {
+        "/clusters/{id}": {
             "get": {
                 "security": [
                     {
@@ -1299,61 +1490,44 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters"
                 ],
-                "summary": "List tags",
+                "summary": "Show a cluster",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
-                        "in": "query"
+                        "description": "the index of the entry in database, or cluster_id, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Cluster"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -1364,7 +1538,7 @@ var doc = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -1373,7 +1547,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creating tags requires no privilege.",
+                "description": "The user must be responsible for the app of one of the cluster member nodes.\nThe cluster_id of the member nodes and services is unset.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1381,21 +1555,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters"
                 ],
-                "summary": "Create or update tags",
+                "summary": "Delete a cluster",
                 "parameters": [
                     {
-                        "description": "list of tags to create or update",
-                        "name": "tags",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Tag"
-                            }
-                        }
+                        "type": "string",
+                        "description": "the index of the entry in database, or cluster_id, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1404,10 +1579,28 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.Tag"
+                                "$ref": "#/definitions/tables.Cluster"
                             }
                         }
                     },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1416,7 +1609,7 @@ var doc = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -1425,44 +1618,40 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deleting tags requires the TagManager privilege.",
+                "description": "The user must be responsible for the app of one of the cluster member nodes.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters"
                 ],
-                "summary": "Delete tags",
+                "summary": "Patch a cluster",
                 "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
+                        "description": "the index of the entry in database, or cluster_id, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
@@ -1471,7 +1660,7 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.Tag"
+                                "$ref": "#/definitions/tables.Cluster"
                             }
                         }
                     },
@@ -1481,117 +1670,34 @@ var doc = `{
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "the entry to update does not exist",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/tags/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags"
-                ],
-                "summary": "Show a tag",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Tag"
-                            }
-                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "a patch test operation failed",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "412": {
+                        "description": "the entry was modified since the client read it",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BasicAuth": []
-                    },
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a tag by index, uuid or name.\nRequires the TagManager privilege.\nCascade deletes the tag attachements to nodes and services.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "tags"
-                ],
-                "summary": "Delete a tag",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/tables.Tag"
-                            }
-                        }
                     },
-                    "204": {
-                        "description": "No Content",
+                    "415": {
+                        "description": "unsupported patch media type",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/routes.validationErrorResponse"
                         }
                     },
                     "500": {
@@ -1603,7 +1709,7 @@ var doc = `{
                 }
             }
         },
-        "/tags/{id}/nodes": {
+        "/clusters/{id}/nodes": {
             "get": {
                 "security": [
                     {
@@ -1617,13 +1723,23 @@ var doc = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters",
+                    "nodes"
                 ],
-                "summary": "List nodes having a specific tag",
+                "summary": "List the nodes of a cluster",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or cluster_id, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "properties to include, and optionally remap (comma separated)",
@@ -1636,6 +1752,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -1651,6 +1782,12 @@ var doc = `{
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -1663,11 +1800,57 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1677,6 +1860,24 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1686,7 +1887,7 @@ var doc = `{
                 }
             }
         },
-        "/tags/{id}/services": {
+        "/clusters/{id}/services": {
             "get": {
                 "security": [
                     {
@@ -1700,13 +1901,23 @@ var doc = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "tags"
+                    "clusters",
+                    "services"
                 ],
-                "summary": "List services having a specific tag",
+                "summary": "List the services of a cluster",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or cluster_id, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "properties to include, and optionally remap (comma separated)",
@@ -1719,6 +1930,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -1734,6 +1960,12 @@ var doc = `{
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -1746,11 +1978,57 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1760,6 +2038,24 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1769,7 +2065,7 @@ var doc = `{
                 }
             }
         },
-        "/users": {
+        "/compliance/modulesets": {
             "get": {
                 "security": [
                     {
@@ -1779,17 +2075,19 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "List users",
+                "description": "The users without the CompManager privilege, and the nodes, can read the modulesets attached to a node or service they can read, directly or via one of its tags.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "List users",
+                "summary": "List compliance modulesets",
                 "parameters": [
                     {
                         "type": "string",
@@ -1803,6 +2101,21 @@ var doc = `{
                         "name": "groupby",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "properties to order by (comma separated, prefix with '~' to reverse)",
@@ -1818,6 +2131,12 @@ var doc = `{
                         "name": "filters",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "number of objets to include in response",
@@ -1831,26 +2150,84 @@ var doc = `{
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
                         "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
             },
             "post": {
                 "security": [
@@ -1861,7 +2238,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "The user must be in the UserManager privilege group to modify tiers users properties.\nThe user must be in the SelfManager privilege group to modify its user properties.",
+                "description": "Requires the CompManager privilege.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1869,19 +2246,19 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "Create or update users",
+                "summary": "Create compliance modulesets",
                 "parameters": [
                     {
-                        "description": "list of users to create or update",
-                        "name": "users",
+                        "description": "list of modulesets to create",
+                        "name": "modulesets",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.User"
+                                "$ref": "#/definitions/tables.CompModuleset"
                             }
                         }
                     }
@@ -1892,10 +2269,22 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.User"
+                                "$ref": "#/definitions/tables.CompModuleset"
                             }
                         }
                     },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1905,7 +2294,7 @@ var doc = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/compliance/modulesets/{id}": {
             "get": {
                 "security": [
                     {
@@ -1915,7 +2304,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Show a user by index, email or login",
+                "description": "The users without the CompManager privilege, and the nodes, can read the modulesets attached to a node or service they can read, directly or via one of its tags.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1923,16 +2312,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "Show a user",
+                "summary": "Show a compliance moduleset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the index of the entry in database, or uuid, or name",
+                        "description": "the index of the entry in database, or name",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1941,10 +2336,16 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.User"
+                                "$ref": "#/definitions/tables.CompModuleset"
                             }
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1968,7 +2369,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a user by index, email or login",
+                "description": "Requires the CompManager privilege.\nCascade deletes the moduleset modules, and its attachments to nodes, services and tags.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1976,16 +2377,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete a compliance moduleset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "the index of the entry in database, or email, or login name",
+                        "description": "the index of the entry in database, or name",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1994,7 +2401,7 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/tables.User"
+                                "$ref": "#/definitions/tables.CompModuleset"
                             }
                         }
                     },
@@ -2004,8 +2411,14 @@ var doc = `{
                             "type": "string"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
                         "schema": {
                             "type": "string"
                         }
@@ -2017,10 +2430,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}/apps/publication": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -2029,69 +2440,86 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "description": "Requires the CompManager privilege.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "List apps the user can read",
+                "summary": "Patch a compliance moduleset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
-                        "in": "query"
+                        "description": "the index of the entry in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
                     },
                     {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompModuleset"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
                         }
                     },
                     "500": {
@@ -2103,7 +2531,7 @@ var doc = `{
                 }
             }
         },
-        "/users/{id}/apps/responsible": {
+        "/compliance/modulesets/{id}/modules": {
             "get": {
                 "security": [
                     {
@@ -2113,28 +2541,31 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "description": "The users without the CompManager privilege, and the nodes, can read the modulesets attached to a node or service they can read, directly or via one of its tags.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "List apps the user is a responsible of",
+                "summary": "List the modules of a compliance moduleset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
+                        "description": "the index of the moduleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
                         "in": "query"
                     },
                     {
@@ -2164,11 +2595,34 @@ var doc = `{
                         "name": "offset",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
                         "description": "turn off metadata in response",
                         "name": "meta",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -2178,6 +2632,24 @@ var doc = `{
                             "$ref": "#/definitions/db.TableResponse"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2185,10 +2657,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{id}/groups": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BasicAuth": []
@@ -2197,7 +2667,7 @@ var doc = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "description": "Requires the CompManager privilege.\nThe modules are identified by their modset_mod_name in the moduleset.\nThe nodes fix the autofix modules their checks found non compliant.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2205,61 +2675,285 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "compliance"
                 ],
-                "summary": "List groups the user is a member of",
+                "summary": "Create or update the modules of a compliance moduleset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "properties to include, and optionally remap (comma separated)",
-                        "name": "props",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "properties to group by (comma separated)",
-                        "name": "groupby",
-                        "in": "query"
+                        "description": "the index of the moduleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
-                        "name": "order",
-                        "in": "query"
+                        "description": "list of modules to create or update",
+                        "name": "modules",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompModulesetModule"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompModulesetModule"
+                            }
+                        }
                     },
-                    {
-                        "type": "array",
-                        "items": {
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
                             "type": "string"
-                        },
-                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
-                        "name": "filters",
-                        "in": "query"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/modulesets/{id}/modules/{module_id}": {
+            "delete": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "number of objets to include in response",
-                        "name": "limit",
-                        "in": "query"
+                        "BasicAuth": []
                     },
                     {
-                        "type": "integer",
-                        "description": "offset of the first objet to include in response",
-                        "name": "offset",
-                        "in": "query"
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Delete a module of a compliance moduleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the moduleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "boolean",
-                        "description": "turn off metadata in response",
-                        "name": "meta",
-                        "in": "query"
+                        "type": "string",
+                        "description": "the index of the module in database, or name",
+                        "name": "module_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/modulesets/{id}/{kind}/{target_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaching to a node or service requires the user to be responsible for its app.\nAttaching to a tag requires the CompManager privilege. The moduleset then applies to the nodes and services with the tag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Attach a compliance moduleset to a node, service or tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the moduleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "nodes",
+                            "services",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "the kind of attachment target",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the index of the node, service or tag in database, or uuid, or name",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/db.TableResponse"
+                            "$ref": "#/definitions/tables.CompModulesetNode"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Detaching from a node or service requires the user to be responsible for its app.\nDetaching from a tag requires the CompManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Detach a compliance moduleset from a node, service or tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the moduleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "nodes",
+                            "services",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "the kind of attachment target",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the index of the node, service or tag in database, or uuid, or name",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -2270,95 +2964,13723 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "db.TableResponse": {
+        },
+        "/compliance/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe rules are the variables of the rulesets and the modules of the modulesets attached to the node, directly or via one of the node tags.\nWith the svc parameter, the rules also include the rulesets and modulesets attached to the service, directly or via one of the service tags.\nA variable defined by several rulesets takes the value of the ruleset attached with the highest precedence: node tag, node, service tag, then service.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Show the compliance rules of the node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the svc_id or svcname of a service running on the node",
+                        "name": "svc",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/compliance.Rules"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/rulesets": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "List compliance rulesets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Create compliance rulesets",
+                "parameters": [
+                    {
+                        "description": "list of rulesets to create",
+                        "name": "rulesets",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRuleset"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRuleset"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/rulesets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Show a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRuleset"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.\nCascade deletes the ruleset variables, and its attachments to nodes, services and tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Delete a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRuleset"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Patch a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRuleset"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/rulesets/{id}/variables": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "List the variables of a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the ruleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.\nThe variables are identified by their var_name in the ruleset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Create or update the variables of a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the ruleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "list of variables to create or update",
+                        "name": "variables",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRulesetVariable"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompRulesetVariable"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/rulesets/{id}/variables/{variable_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the CompManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Delete a variable of a compliance ruleset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the ruleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the index of the variable in database, or name",
+                        "name": "variable_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/rulesets/{id}/{kind}/{target_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaching to a node or service requires the user to be responsible for its app.\nAttaching to a tag requires the CompManager privilege. The ruleset then applies to the nodes and services with the tag.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Attach a compliance ruleset to a node, service or tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the ruleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "nodes",
+                            "services",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "the kind of attachment target",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the index of the node, service or tag in database, or uuid, or name",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.CompRulesetNode"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Detaching from a node or service requires the user to be responsible for its app.\nDetaching from a tag requires the CompManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Detach a compliance ruleset from a node, service or tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the ruleset in database, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "nodes",
+                            "services",
+                            "tags"
+                        ],
+                        "type": "string",
+                        "description": "the kind of attachment target",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the index of the node, service or tag in database, or uuid, or name",
+                        "name": "target_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing CompManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/compliance/status": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the last results reported by the nodes of the apps published to the user, per node, service and module.\nThe run_status is 0 for compliant, 1 for non compliant and 2 for not applicable.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "List the compliance modules status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example run_status=1",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe results replace the last results of the node for the same service and module.\nThe svc_id is the svc_id or svcname of a service running on the node, or empty for the node itself.\nThe run_action is check or fix, the run_status 0 for compliant, 1 for non compliant and 2 for not applicable. The run_date defaults to now.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "compliance"
+                ],
+                "summary": "Report compliance modules results",
+                "parameters": [
+                    {
+                        "description": "list of module results",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompStatus"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.CompStatus"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the alerts raised by the dashboard rules on the nodes and services of the apps published to the user.\nThe dash_severity is 0 for info, 1 for notice, 2 for warning, 3 for error and 4 for critical.\nThe alert message is the dash_fmt format, whose %(key)s placeholders are the dash_dict values.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "List dashboard alerts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example dash_severity\u003e=3",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard/acks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the unexpired acknowledgements of the alerts on the nodes and services of the apps published to the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "List active dashboard alert acknowledgements",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nEnqueue the refresh of the alerts of all nodes and services, and respond with the background job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Refresh the dashboard alerts",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/tables.Job"
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/dashboard/{id}/ack": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the app of the alert, via app responsibles.\nThe alerts of the same type on the same node or service are acknowledged until the expiry date, 24 hours from now by default.\nThe acknowledged alerts are still listed, flagged as acked, but not notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Acknowledge a dashboard alert",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the alert in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the acknowledgement comment and expiry date",
+                        "name": "ack",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.dashboardAckRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.DashboardAck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the app of the alert, via app responsibles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Remove the acknowledgement of a dashboard alert",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the alert in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Dashboard"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/disks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The disk storage array information is available as the diskinfo properties, for example props=svcdisks.disk_id,services.svcname,diskinfo.disk_arrayid.\nThe disks with an empty svc_id are used by the node itself.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "disks"
+                ],
+                "summary": "List the disks of the nodes and services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example disk_local=false",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe disks replace the disks inventory of the node atomically.\nA disk is identified by its disk_id wwid and the svc_id of the service using it, empty if used by the node itself.\nThe svc_id is the svc_id or svcname of a service running on the node.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disks"
+                ],
+                "summary": "Replace the disks inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the node disks",
+                        "name": "disks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceDisk"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceDisk"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/disks/info": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The disk_id is the disk wwid, the key of the disks of the nodes and services.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "disks"
+                ],
+                "summary": "List the disks storage array information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example disk_arrayid=svc01",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication, from a node with access to the array management interface.\nThe information replaces the information of the disks with the same disk_id, if reported by the same node.\nThe node_id is set to the reporting node. The information of a disk reported by another node can not be replaced, until that node is deleted or a manager deletes the information.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disks"
+                ],
+                "summary": "Report disks storage array information",
+                "parameters": [
+                    {
+                        "description": "list of disks information",
+                        "name": "disks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.DiskInfo"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.DiskInfo"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/disks/info/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nThe next node reporting the disk information becomes its reporting node.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disks"
+                ],
+                "summary": "Delete a disk storage array information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the create, update and delete events of the table entries the user can read, as Server-Sent Events.\nThe event data is the json encoded event, without the entry itself: read it from the table routes.\nA client reconnecting with the Last-Event-ID header receives the events it missed, or a reset event if they are no longer available.\nThe delete events of entries removed from the database are streamed if the entry was found readable in the last 10 minutes of the stream, and to managers without filters.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream the table entries change events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "tables to stream the events of (comma separated, default all)",
+                        "name": "tables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), with a single table",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "resume after this event id, if the Last-Event-ID header is not set",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "resume after this event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/events.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/filtersets": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the filtersets owned by the user or shared with one of the user groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "List filtersets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The filtersets are owned by the user.\nA filterset can be applied to any table request with the filterset query parameter.\nThe unqualified properties of its filters refer to the filterset table.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Create filtersets",
+                "parameters": [
+                    {
+                        "description": "list of filtersets to create",
+                        "name": "filtersets",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/filtersets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Show a filterset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must own the filterset, or have the Manager privilege.\nThe UserManager privilege is required if the filterset is assigned to a user with a locked filter.\nThe filterset can not be deleted while assigned to users. Cascade deletes the filterset shares with groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Delete a filterset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the filterset is assigned to users",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must own the filterset, or have the Manager privilege.\nThe UserManager privilege is required if the filterset is assigned to a user with a locked filter.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Patch a filterset",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/filtersets/{id}/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "List the groups a filterset is shared with",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Group"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/filtersets/{id}/groups/{group_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must own the filterset, or have the Manager privilege.\nUsers without the Manager privilege can only share with their own groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Share a filterset with a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the index of the group in database",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.FiltersetGroup"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must own the filterset, or have the Manager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "filtersets"
+                ],
+                "summary": "Stop sharing a filterset with a group",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the index of the group in database",
+                        "name": "group_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The users see the jobs they enqueued, the managers see all jobs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show the job status, progress and result.\nThe users see the jobs they enqueued, the managers see all jobs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Show a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Job"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "A queued job is canceled immediately. A running job is canceled by its worker, which can take a few seconds.\nThe users can cancel the jobs they enqueued, the managers can cancel all jobs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Job"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the job is already finished",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue again a failed or canceled job, with its attempts reset.\nThe users can retry the jobs they enqueued, the managers can retry all jobs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Retry a background job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Job"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the job is not failed nor canceled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List nodes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The app code of the nodes is forced to one the user is responsible of.\nThe team responsible of the nodes defaults to the user's primary group.\nThe user must be in the NodeManager privilege group.\nThe dashboard alerts of the nodes are refreshed in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Create or update nodes",
+                "parameters": [
+                    {
+                        "description": "list of nodes to create or update",
+                        "name": "nodes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/hbas": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the nodes host bus adapters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example hba_type=fc",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe host bus adapters replace the host bus adapters inventory of the node atomically.\nA host bus adapter port is identified by its hba_id, the fibre channel WWN or iSCSI initiator name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Replace the host bus adapters inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the node host bus adapters",
+                        "name": "hbas",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeHBA"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeHBA"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/ips": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the nodes network addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example addr=192.168.%",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe network addresses replace the network addresses inventory of the node atomically.\nA network address is identified by its intf and addr.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Replace the network addresses inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the node network addresses",
+                        "name": "ips",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeIP"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeIP"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags attachments to nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "nodes"
+                ],
+                "summary": "Show a tag attachment to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeTag"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the node, via app responsibles.\nThe attachment node_id and tag_id can not be modified.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "nodes"
+                ],
+                "summary": "Patch a tag attachment to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeTag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/targets": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the nodes storage targets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example tgt_id=5006%",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe storage targets replace the storage targets inventory of the node atomically.\nA storage target is identified by the hba_id of the node port it is reachable from, and its tgt_id port WWN.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Replace the storage targets inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the node storage targets",
+                        "name": "targets",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.StorZone"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.StorZone"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show a node by index, id or name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Show a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the NodeManager privilege group.\nThe dashboard alerts of the node are refreshed in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Update a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "node properties to create or update",
+                        "name": "nodes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/tables.Node"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing NodeManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a node by index, id or name.\nThe user must have the NodeManager privilege.\nThe user must be responsible for the node, via app responsibles.\nCascade delete on services instances, dashboard entries, compliance attachments and status, checks, packages, patches, network addresses, host bus adapters, storage targets, disks and service actions.\nThe disks information reported by the node are kept, without reporting node.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Delete a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the NodeManager privilege group.\nThe user must be responsible for the node, via app responsibles.\nThe dashboard alerts of the node are refreshed in the background.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Patch a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing NodeManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/actions": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "actions",
+                    "nodes"
+                ],
+                "summary": "List the service actions run by a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example status=err",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/candidate_tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List existing tags not already attached to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/checks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "checks",
+                    "nodes"
+                ],
+                "summary": "List the checks of a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example chk_err=1",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/disks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "disks",
+                    "nodes"
+                ],
+                "summary": "List the disks of a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/hbas": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the host bus adapters of a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/ips": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the network addresses of a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/packages": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "packages",
+                    "nodes"
+                ],
+                "summary": "List the packages installed on a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/patches": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "patches",
+                    "nodes"
+                ],
+                "summary": "List the patches installed on a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/snooze": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the NodeManager privilege group.\nThe user must be responsible for the node, via app responsibles.\nNo dashboard alert is raised on the node, nor notified, until the snooze expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Snooze a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the snooze expiry date or duration",
+                        "name": "snooze",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.snoozeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing NodeManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the NodeManager privilege group.\nThe user must be responsible for the node, via app responsibles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "Unsnooze a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Node"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing NodeManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags attached to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "nodes"
+                ],
+                "summary": "Show a tag attachment to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeTag"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the node, via app responsibles.\nThe attachment node_id and tag_id can not be modified.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "nodes"
+                ],
+                "summary": "Patch a tag attachment to a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodeTag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/nodes/{id}/targets": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "nodes"
+                ],
+                "summary": "List the storage targets of a node",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/packages": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "List the packages installed on nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe packages replace the packages inventory of the node atomically: the packages not listed are removed.\nA package is identified by its pkg_name, pkg_version and pkg_arch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "Replace the packages inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the installed packages",
+                        "name": "packages",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodePackage"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodePackage"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/packages/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the nodes of the apps published to the user with the package installed in a version satisfying the comparison, for example below a version with op=lt.\nThe versions are compared the dpkg way: epoch, then upstream version and revision compared by numeric and non-numeric segments, where 1.10 is greater than 1.9 and 1.0~rc1 lower than 1.0.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packages"
+                ],
+                "summary": "List the nodes with a version of a package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the package name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "the version to compare the installed versions to",
+                        "name": "version",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "lt",
+                            "le",
+                            "eq",
+                            "ne",
+                            "ge",
+                            "gt"
+                        ],
+                        "type": "string",
+                        "description": "the comparison operator (default lt)",
+                        "name": "op",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the package architecture",
+                        "name": "arch",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/routes.packageNode"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/patches": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "patches"
+                ],
+                "summary": "List the patches installed on nodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication.\nThe patches replace the patches inventory of the node atomically: the patches not listed are removed.\nA patch is identified by its patch_num and patch_rev.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "patches"
+                ],
+                "summary": "Replace the patches inventory of the node",
+                "parameters": [
+                    {
+                        "description": "list of the installed patches",
+                        "name": "patches",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodePatch"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.NodePatch"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/query": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Select properties of the base table entries and of any table joinable to the base table,\nfiltered by a tree of property conditions combined with and, or and not.\nThe read ACL of the base table applies.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "query"
+                ],
+                "summary": "Query table entries",
+                "parameters": [
+                    {
+                        "description": "query",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/db.Query"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schema": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tables with their properties and the tables they have a join with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schema"
+                ],
+                "summary": "Show the registered tables schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/db.TableSchema"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schema/joins": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the joins the table requests use to reach the properties of other tables,\nand the preferred routes used instead of the shortest join paths.\nA table can be joined again under an alias with the \"table@alias.property\" syntax.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schema"
+                ],
+                "summary": "Show the table join graph",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.JoinGraph"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search the entries having words starting with all the searched words in their full-text indexed properties.\nThe hits are ranked by relevance, and limited to the entries the user can read.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search nodes, services, tags and apps",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the words to search",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "app",
+                            "cluster",
+                            "moduleset",
+                            "node",
+                            "ruleset",
+                            "service",
+                            "tag"
+                        ],
+                        "type": "string",
+                        "description": "hit types to search (comma separated, default all)",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of hits to include in response (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/config/keywords": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the keywords of the last configuration of the services of the apps published to the user, for example the services using a resource type with filters=cfg_rtype=fs.ext4\u0026props=services.svcname\u0026groupby=services.svcname.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List the services configuration keywords",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example cfg_rtype=fs.ext4",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags attachments to services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "services"
+                ],
+                "summary": "Show a tag attachment to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceTag"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the service, via app responsibles.\nThe attachment svc_id and tag_id can not be modified.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "services"
+                ],
+                "summary": "Patch a tag attachment to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceTag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show a service by index, id or name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Show a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Service"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the service, via app responsibles.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.\nThe dashboard alerts of the service are refreshed in the background.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Patch a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Service"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/actions": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "actions",
+                    "services"
+                ],
+                "summary": "List the actions run on a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example status=err",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/candidate_tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List existing tags not already attached to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/config": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Respond the text of the last configuration pushed by a node of the service.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Show the configuration of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the configuration text",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires node authentication, from a node running the service.\nThe request body is the configuration text. A new version is stored if the configuration changed since the last version, and the configuration keywords are parsed.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Push the configuration of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the configuration text",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.ServiceConfig"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/config/diff": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Respond the unified diff from a version to another, designated by their index in the configuration history.\nThe to version defaults to the last version, and the from version to the version preceding the to version, or an empty configuration if none.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Show the differences between two configuration versions of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the index of the old version in the configuration history",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the index of the new version in the configuration history",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the number of unchanged lines around the changes (default 3)",
+                        "name": "context",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the unified diff, empty if the versions are the same",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/config/history": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the configuration versions pushed by the nodes of the service. Use props to exclude the cfg_text.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List the configuration versions of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/config/keywords": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List the configuration keywords of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%), for example cfg_group=fs",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/disks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "disks",
+                    "services"
+                ],
+                "summary": "List the disks of a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/snooze": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the service, via app responsibles.\nNo dashboard alert is raised on the service, nor notified, until the snooze expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Snooze a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the snooze expiry date or duration",
+                        "name": "snooze",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.snoozeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Service"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the service, via app responsibles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Unsnooze a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Service"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags attached to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "services"
+                ],
+                "summary": "Show a tag attachment to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceTag"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be responsible for the service, via app responsibles.\nThe attachment svc_id and tag_id can not be modified.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags",
+                    "services"
+                ],
+                "summary": "Patch a tag attachment to a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.ServiceTag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creating tags requires no privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Create or update tags",
+                "parameters": [
+                    {
+                        "description": "list of tags to create or update",
+                        "name": "tags",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deleting tags requires the TagManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete tags",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Show a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tag by index, uuid or name.\nRequires the TagManager privilege.\nCascade deletes the tag attachements to nodes and services.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the TagManager privilege.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "Patch a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing TagManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List nodes having a specific tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}/services": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "tags"
+                ],
+                "summary": "List services having a specific tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the UserManager privilege group to create users, to modify tiers users properties, and to modify the credentials, quotas and lock_filter properties.\nOther users can only update their own entry, identified by id, with their names, phone and notification preferences.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create or update users",
+                "parameters": [
+                    {
+                        "description": "list of users to create or update",
+                        "name": "users",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.User"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing UserManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Show a user by index, email or login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Show a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or uuid, or name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.User"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a user by index, email or login",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or email, or login name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.User"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The user must be in the UserManager privilege group to modify tiers users properties.\nThe user must be in the UserManager privilege group to modify the credentials, quotas and lock_filter properties. Other users can only modify their names, phone and notification preferences.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Patch a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or email, or login name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing UserManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/apps/publication": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List apps the user can read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/apps/responsible": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List apps the user is a responsible of",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/filterset": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "If the user lock_filter is \"T\", all the user table requests are limited to the entries matching the filters of this filterset.\nUsers can see their own filterset. The UserManager privilege is required to see other users' filterset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Show the filterset assigned to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or email, or login name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Filterset"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing UserManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the UserManager privilege.\nThe filterset is enforced on the user table requests if the user lock_filter is \"T\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Assign a filterset to a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or email, or login name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "the filterset to assign",
+                        "name": "filterset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/routes.userFiltersetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.FiltersetUser"
+                        }
+                    },
+                    "401": {
+                        "description": "missing UserManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the UserManager privilege.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unassign the filterset of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the index of the entry in database, or email, or login name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing UserManager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Managers and UserManager are allowed to see all users' information.\nOthers can only see information for users in their organization groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List groups the user is a member of",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege. The webhook secrets are not included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nThe table entries change events matching the webhook events, tables and filters are posted to the webhook url.\nThe deliveries are signed with the secret, in the X-Collector-Signature header, and retried with an exponential backoff until the url responds a 2xx status.\nThe filters require a single table.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhooks",
+                "parameters": [
+                    {
+                        "description": "list of webhooks to create",
+                        "name": "webhooks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Webhook"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege. The webhook secret is not included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Show a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the entry matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Webhook"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nCascade deletes the webhook deliveries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Webhook"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nThe request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Patch a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the entry in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 412 Precondition Failed if the entity tag of the entry does not match",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "the merge patch or the list of patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tables.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "the entry to update does not exist",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "a patch test operation failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "the entry was modified since the client read it",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported patch media type",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/routes.validationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nThe deliveries with the \"dead\" status failed all their attempts. They can be sent again with the retry route.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List the deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the webhook in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to include, and optionally remap (comma separated)",
+                        "name": "props",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to group by (comma separated)",
+                        "name": "groupby",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "aggregate alias value filter (n\u003e10)",
+                        "name": "having",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to order by (comma separated, prefix with '~' to reverse)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "description": "property value filter (a, !a, a\u0026b, a|b, (a,b),  a%,  a%\u0026!ab%)",
+                        "name": "filters",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name of a saved filterset to apply, owned by the user or shared with one of the user groups",
+                        "name": "filterset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of objets to include in response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset of the first objet to include in response",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "approx",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "meta.total computation: exact, approx or none",
+                        "name": "total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response encoding, streamed without metadata if ndjson or csv (default from Accept)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "properties to count the rows per value of in meta.facets (comma separated)",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "maximum number of values per facet, most frequent first (default 10)",
+                        "name": "facets_limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "turn off metadata in response",
+                        "name": "meta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "respond 304 Not Modified if the entity tag of the response matches",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/db.TableResponse"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Requires the Manager privilege.\nReset the delivery attempts, so it is sent again as soon as possible, including if it is dead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "the index of the webhook in database",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the index of the delivery in database",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tables.WebhookDelivery"
+                        }
+                    },
+                    "401": {
+                        "description": "missing Manager privilege",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "compliance.Module": {
+            "type": "object",
+            "properties": {
+                "autofix": {
+                    "type": "boolean"
+                },
+                "modset_mod_name": {
+                    "type": "string"
+                },
+                "modsets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "compliance.Rules": {
+            "type": "object",
+            "properties": {
+                "modules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/compliance.Module"
+                    }
+                },
+                "modulesets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "rulesets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/compliance.Variable"
+                    }
+                }
+            }
+        },
+        "compliance.Variable": {
+            "type": "object",
+            "properties": {
+                "ruleset_name": {
+                    "type": "string"
+                },
+                "var_class": {
+                    "type": "string"
+                },
+                "var_name": {
+                    "type": "string"
+                },
+                "var_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.FieldError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "db.JoinGraph": {
+            "type": "object",
+            "properties": {
+                "joins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.tableJoin"
+                    }
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.tableRoute"
+                    }
+                }
+            }
+        },
+        "db.PropertySchema": {
+            "type": "object",
+            "properties": {
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filterable": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "node_env"
+                },
+                "nullable": {
+                    "type": "boolean"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 256
+                },
+                "sortable": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "string"
+                },
+                "writable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "db.Query": {
+            "type": "object",
+            "properties": {
+                "aggregate": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "count(*):n"
+                    ]
+                },
+                "distinct": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/db.QueryFilter"
+                },
+                "group": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "having": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "n\u003e1"
+                    ]
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "order": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "services.svcname"
+                    ]
+                },
+                "props": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "services.svcname",
+                        "nodes.nodename"
+                    ]
+                },
+                "table": {
+                    "type": "string",
+                    "example": "services"
+                }
+            }
+        },
+        "db.QueryFilter": {
+            "type": "object",
+            "properties": {
+                "and": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.QueryFilter"
+                    }
+                },
+                "not": {
+                    "$ref": "#/definitions/db.QueryFilter"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "or": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.QueryFilter"
+                    }
+                },
+                "prop": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.SearchHit": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "link": {
+                    "type": "string",
+                    "example": "/api/nodes/12"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "node1"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 3.1
+                },
+                "type": {
+                    "type": "string",
+                    "example": "node"
+                }
+            }
+        },
+        "db.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.SearchHit"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/db.searchResponseMeta"
+                }
+            }
+        },
+        "db.TableResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": true
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/db.tableResponseMeta"
+                }
+            }
+        },
+        "db.TableSchema": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "nodes"
+                },
+                "neighbours": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "node_tags",
+                        "svcmon",
+                        "apps"
+                    ]
+                },
+                "props": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.PropertySchema"
+                    }
+                }
+            }
+        },
+        "db.facets": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "object",
+                "additionalProperties": {
+                    "type": "integer"
+                }
+            }
+        },
+        "db.property": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "remap": {
+                    "type": "string"
+                },
+                "table": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.searchResponseMeta": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "q": {
+                    "type": "string"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "db.tableJoin": {
+            "type": "object",
+            "properties": {
+                "cols": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "db.tableResponseMeta": {
+            "type": "object",
+            "properties": {
+                "available_props": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.property"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "facets": {
+                    "$ref": "#/definitions/db.facets"
+                },
+                "included_props": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.property"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_approx": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "db.tableRoute": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "via": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "events.Event": {
+            "type": "object",
+            "properties": {
+                "entry_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "props": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "table": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "routes.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "token_expire_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.dashboardAckRequest": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "expires": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.packageNode": {
+            "type": "object",
+            "properties": {
+                "node_id": {
+                    "type": "string"
+                },
+                "nodename": {
+                    "type": "string"
+                },
+                "pkg_arch": {
+                    "type": "string"
+                },
+                "pkg_name": {
+                    "type": "string"
+                },
+                "pkg_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "routes.snoozeRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Duration is the snooze duration from now, as a go duration\nstring, if Till is not set.",
+                    "type": "string",
+                    "example": "2h30m"
+                },
+                "till": {
+                    "description": "Till is the snooze expiry date.",
+                    "type": "string"
+                }
+            }
+        },
+        "routes.userFiltersetRequest": {
+            "type": "object",
+            "properties": {
+                "filterset_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "routes.validationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/db.FieldError"
+                    }
+                }
+            }
+        },
+        "tables.App": {
+            "type": "object",
+            "properties": {
+                "app": {
+                    "type": "string"
+                },
+                "app_domain": {
+                    "type": "string"
+                },
+                "app_team_ops": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.Check": {
+            "type": "object",
+            "required": [
+                "chk_type"
+            ],
+            "properties": {
+                "chk_err": {
+                    "type": "integer"
+                },
+                "chk_high": {
+                    "type": "number"
+                },
+                "chk_instance": {
+                    "type": "string",
+                    "example": "/var"
+                },
+                "chk_low": {
+                    "type": "number"
+                },
+                "chk_threshold_provider": {
+                    "type": "string"
+                },
+                "chk_type": {
+                    "type": "string",
+                    "example": "fs_u"
+                },
+                "chk_value": {
+                    "type": "number",
+                    "example": 85
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CheckThreshold": {
+            "type": "object",
+            "required": [
+                "chk_type"
+            ],
+            "properties": {
+                "chk_high": {
+                    "type": "number",
+                    "example": 90
+                },
+                "chk_instance": {
+                    "type": "string",
+                    "example": "/var%"
+                },
+                "chk_low": {
+                    "type": "number"
+                },
+                "chk_type": {
+                    "type": "string",
+                    "example": "fs_u"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.Cluster": {
+            "type": "object",
+            "required": [
+                "cluster_id",
+                "cluster_name"
+            ],
+            "properties": {
+                "cluster_heartbeats": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "cluster_id": {
+                    "type": "string",
+                    "example": "c4d7f1a2-5b0e-4f6a-9d3c-8e2b1a0f7c65"
+                },
+                "cluster_name": {
+                    "type": "string",
+                    "example": "cluster1"
+                },
+                "cluster_secret_rotated_at": {
+                    "type": "string"
+                },
+                "cluster_secret_rotated_by": {
+                    "type": "string"
+                },
+                "cluster_secret_version": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CompModuleset": {
+            "type": "object",
+            "required": [
+                "modset_name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modset_comment": {
+                    "type": "string"
+                },
+                "modset_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CompModulesetModule": {
+            "type": "object",
+            "required": [
+                "modset_mod_name"
+            ],
+            "properties": {
+                "autofix": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modset_id": {
+                    "type": "integer"
+                },
+                "modset_mod_name": {
+                    "type": "string",
+                    "example": "motd"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CompModulesetNode": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modset_id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CompRuleset": {
+            "type": "object",
+            "required": [
+                "ruleset_name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ruleset_comment": {
+                    "type": "string"
+                },
+                "ruleset_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.CompRulesetNode": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "additionalProperties": true
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "meta": {
-                    "$ref": "#/definitions/db.tableResponseMeta"
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "ruleset_id": {
+                    "type": "integer"
                 }
             }
         },
-        "db.property": {
+        "tables.CompRulesetVariable": {
             "type": "object",
+            "required": [
+                "var_name"
+            ],
             "properties": {
-                "desc": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ruleset_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "var_class": {
+                    "type": "string",
+                    "example": "file"
+                },
+                "var_name": {
+                    "type": "string",
+                    "example": "etc_motd"
+                },
+                "var_value": {
+                    "type": "string",
+                    "example": "{\"path\": \"/etc/motd\", \"fmt\": \"welcome\"}"
+                }
+            }
+        },
+        "tables.CompStatus": {
+            "type": "object",
+            "required": [
+                "run_action",
+                "run_module"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "run_action": {
+                    "type": "string"
+                },
+                "run_date": {
+                    "type": "string"
+                },
+                "run_log": {
+                    "type": "string"
+                },
+                "run_module": {
+                    "type": "string"
+                },
+                "run_status": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.Dashboard": {
+            "type": "object",
+            "properties": {
+                "acked": {
                     "type": "boolean"
                 },
-                "name": {
+                "app": {
                     "type": "string"
                 },
-                "remap": {
+                "created_at": {
                     "type": "string"
                 },
-                "table": {
+                "dash_dict": {
+                    "type": "object"
+                },
+                "dash_env": {
+                    "type": "string"
+                },
+                "dash_fmt": {
+                    "type": "string",
+                    "example": "warranty ends on %(date)s"
+                },
+                "dash_severity": {
+                    "type": "integer"
+                },
+                "dash_type": {
+                    "type": "string",
+                    "example": "node warranty end"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "db.tableResponseMeta": {
+        "tables.DashboardAck": {
             "type": "object",
             "properties": {
-                "available_props": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/db.property"
-                    }
+                "app": {
+                    "type": "string"
                 },
-                "count": {
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dash_type": {
+                    "type": "string"
+                },
+                "expires": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "included_props": {
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.DiskInfo": {
+            "type": "object",
+            "required": [
+                "disk_id"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disk_alloc": {
+                    "type": "integer",
+                    "example": 51200
+                },
+                "disk_arrayid": {
+                    "type": "string",
+                    "example": "svc01"
+                },
+                "disk_devid": {
+                    "type": "string",
+                    "example": "0ABC"
+                },
+                "disk_group": {
+                    "type": "string",
+                    "example": "pool0"
+                },
+                "disk_id": {
+                    "type": "string",
+                    "example": "600507680c80821e5000000000000abc"
+                },
+                "disk_level": {
+                    "type": "integer"
+                },
+                "disk_name": {
+                    "type": "string",
+                    "example": "app_data_01"
+                },
+                "disk_raid": {
+                    "type": "string",
+                    "example": "RAID5"
+                },
+                "disk_size": {
+                    "type": "integer",
+                    "example": 51200
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.Filterset": {
+            "type": "object",
+            "required": [
+                "name",
+                "table_name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filters": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/db.property"
+                        "type": "string"
                     }
                 },
-                "limit": {
+                "id": {
                     "type": "integer"
                 },
-                "offset": {
+                "name": {
+                    "type": "string"
+                },
+                "orderby": {
+                    "type": "string"
+                },
+                "props": {
+                    "type": "string"
+                },
+                "table_name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.FiltersetGroup": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "filterset_id": {
                     "type": "integer"
                 },
-                "total": {
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "integer"
                 }
             }
         },
-        "gorm.DeletedAt": {
+        "tables.FiltersetUser": {
             "type": "object",
             "properties": {
-                "time": {
+                "created_at": {
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
+                "filterset_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.Group": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "privilege": {
                     "type": "boolean"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "routes.TokenResponse": {
+        "tables.Job": {
             "type": "object",
             "properties": {
-                "token": {
+                "args": {
+                    "type": "object"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "cancel_requested": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lease_until": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "run_after": {
+                    "type": "string"
+                },
+                "started_at": {
                     "type": "string"
                 },
-                "token_expire_at": {
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "dashboard_alerts_refresh"
+                },
+                "updated_at": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
         "tables.Node": {
             "type": "object",
+            "required": [
+                "nodename"
+            ],
             "properties": {
                 "action_type": {
                     "type": "string"
@@ -2593,8 +16915,156 @@ var doc = `{
                 }
             }
         },
+        "tables.NodeHBA": {
+            "type": "object",
+            "required": [
+                "hba_id"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hba_id": {
+                    "type": "string",
+                    "example": "10000000c9a1b2c3"
+                },
+                "hba_type": {
+                    "type": "string",
+                    "example": "fc"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.NodeIP": {
+            "type": "object",
+            "required": [
+                "addr",
+                "intf"
+            ],
+            "properties": {
+                "addr": {
+                    "type": "string",
+                    "example": "192.168.0.10"
+                },
+                "addr_type": {
+                    "type": "string",
+                    "example": "ipv4"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "flag_deprecated": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "intf": {
+                    "type": "string",
+                    "example": "eth0"
+                },
+                "mac": {
+                    "type": "string",
+                    "example": "52:54:00:12:34:56"
+                },
+                "mask": {
+                    "type": "string",
+                    "example": "24"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.NodePackage": {
+            "type": "object",
+            "required": [
+                "pkg_name"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "pkg_arch": {
+                    "type": "string",
+                    "example": "x86_64"
+                },
+                "pkg_install_date": {
+                    "type": "string"
+                },
+                "pkg_name": {
+                    "type": "string",
+                    "example": "openssl"
+                },
+                "pkg_sig": {
+                    "type": "string"
+                },
+                "pkg_type": {
+                    "type": "string",
+                    "example": "rpm"
+                },
+                "pkg_version": {
+                    "type": "string",
+                    "example": "1:1.1.1k-7.el8_6"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.NodePatch": {
+            "type": "object",
+            "required": [
+                "patch_num"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "patch_install_date": {
+                    "type": "string"
+                },
+                "patch_num": {
+                    "type": "string",
+                    "example": "KB5034441"
+                },
+                "patch_rev": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "tables.NodeTag": {
             "type": "object",
+            "required": [
+                "node_id",
+                "tag_id"
+            ],
             "properties": {
                 "created": {
                     "type": "string"
@@ -2627,6 +17097,9 @@ var doc = `{
         },
         "tables.Service": {
             "type": "object",
+            "required": [
+                "svcname"
+            ],
             "properties": {
                 "cluster_id": {
                     "type": "string"
@@ -2649,6 +17122,12 @@ var doc = `{
                 "svc_avail_status": {
                     "type": "string"
                 },
+                "svc_comment": {
+                    "type": "string"
+                },
+                "svc_config": {
+                    "type": "string"
+                },
                 "svc_config_updated": {
                     "type": "string"
                 },
@@ -2702,8 +17181,85 @@ var doc = `{
                 }
             }
         },
+        "tables.ServiceConfig": {
+            "type": "object",
+            "properties": {
+                "cfg_hash": {
+                    "type": "string"
+                },
+                "cfg_text": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.ServiceDisk": {
+            "type": "object",
+            "required": [
+                "disk_id"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disk_dg": {
+                    "type": "string",
+                    "example": "vg_app"
+                },
+                "disk_id": {
+                    "type": "string",
+                    "example": "600507680c80821e5000000000000abc"
+                },
+                "disk_local": {
+                    "type": "boolean"
+                },
+                "disk_model": {
+                    "type": "string",
+                    "example": "2145"
+                },
+                "disk_size": {
+                    "type": "integer",
+                    "example": 51200
+                },
+                "disk_used": {
+                    "type": "integer",
+                    "example": 20480
+                },
+                "disk_vendor": {
+                    "type": "string",
+                    "example": "IBM"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
         "tables.ServiceTag": {
             "type": "object",
+            "required": [
+                "svc_id",
+                "tag_id"
+            ],
             "properties": {
                 "created": {
                     "type": "string"
@@ -2734,8 +17290,99 @@ var doc = `{
                 }
             }
         },
+        "tables.StorZone": {
+            "type": "object",
+            "required": [
+                "hba_id",
+                "tgt_id"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "hba_id": {
+                    "type": "string",
+                    "example": "10000000c9a1b2c3"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "tgt_id": {
+                    "type": "string",
+                    "example": "50060e8007e2c410"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "tables.SvcAction": {
+            "type": "object",
+            "required": [
+                "action",
+                "svc_id"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "start"
+                },
+                "begin": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "boolean"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "pid": {
+                    "type": "string",
+                    "example": "12345"
+                },
+                "rid": {
+                    "type": "string",
+                    "example": "fs#1,ip#1"
+                },
+                "sid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "status_log": {
+                    "type": "string"
+                },
+                "subset": {
+                    "type": "string"
+                },
+                "svc_id": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "2.1-1869"
+                }
+            }
+        },
         "tables.Tag": {
             "type": "object",
+            "required": [
+                "tag_name"
+            ],
             "properties": {
                 "created_at": {
                     "type": "string"
@@ -2768,6 +17415,9 @@ var doc = `{
         },
         "tables.User": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
                 "created_at": {
                     "type": "string"
@@ -2842,6 +17492,93 @@ var doc = `{
                     "type": "string"
                 }
             }
+        },
+        "tables.Webhook": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "string",
+                    "example": "create,delete"
+                },
+                "filters": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "tables": {
+                    "type": "string",
+                    "example": "nodes"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tables.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_kind": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,176 @@
+// Package events is the bus of the table entries change events.
+//
+// The write handlers publish an event for each created, updated or deleted
+// entry. The subscribers receive the events published after they
+// subscribed, and can resume a stream from the last event they received,
+// as long as this event is still in the bus ring buffer.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Kind is the type of change of an event.
+	Kind string
+
+	// Event describes a change of a table entry. The entry itself is not
+	// included, so subscribers read it with their own permissions.
+	Event struct {
+		ID      uint64    `json:"id"`
+		Kind    Kind      `json:"kind"`
+		Table   string    `json:"table,omitempty"`
+		EntryID uint      `json:"entry_id,omitempty"`
+		Props   []string  `json:"props,omitempty"`
+		Time    time.Time `json:"time"`
+	}
+
+	// Bus dispatches the published events to the subscribers, and keeps
+	// the last events in a ring buffer for the subscribers resuming a
+	// stream.
+	Bus struct {
+		sync.Mutex
+		last  uint64
+		ring  []Event
+		head  int
+		count int
+		subs  map[*Subscription]interface{}
+	}
+
+	// Subscription receives the events published on a bus. C is closed
+	// when the subscriber is too slow to keep up with the published
+	// events, in which case it should resume from the last event it
+	// received.
+	Subscription struct {
+		C <-chan Event
+		c chan Event
+	}
+)
+
+const (
+	Create Kind = "create"
+	Update Kind = "update"
+	Delete Kind = "delete"
+
+	// Reset is sent to the subscribers resuming from an event no longer
+	// in the ring buffer. They missed events and should read the tables
+	// again.
+	Reset Kind = "reset"
+
+	defaultRingSize         = 4096
+	defaultSubscriptionSize = 256
+)
+
+var (
+	bus = NewBus(defaultRingSize)
+)
+
+// NewBus returns a bus keeping the last size events. The event ids are
+// seeded with the current time, so they keep increasing across restarts
+// and the subscribers resuming from an event published before a restart
+// receive a Reset event.
+func NewBus(size int) *Bus {
+	return &Bus{
+		last: uint64(time.Now().UnixNano()),
+		ring: make([]Event, size),
+		subs: make(map[*Subscription]interface{}),
+	}
+}
+
+// Publish sets the event id and time, stores the event in the ring buffer
+// and sends it to the subscribers.
+func (t *Bus) Publish(e Event) Event {
+	t.Lock()
+	defer t.Unlock()
+	t.last++
+	e.ID = t.last
+	e.Time = time.Now()
+	t.ring[t.head] = e
+	t.head = (t.head + 1) % len(t.ring)
+	if t.count < len(t.ring) {
+		t.count++
+	}
+	for s := range t.subs {
+		select {
+		case s.c <- e:
+		default:
+			// slow subscriber
+			t.unsubscribe(s)
+		}
+	}
+	return e
+}
+
+// Subscribe returns a subscription to the events published from now on.
+//
+// If resume is true, it also returns the events published after lastID,
+// or a Reset event if some of these events are no longer in the ring
+// buffer.
+func (t *Bus) Subscribe(lastID uint64, resume bool) (*Subscription, []Event) {
+	t.Lock()
+	defer t.Unlock()
+	c := make(chan Event, defaultSubscriptionSize)
+	s := &Subscription{C: c, c: c}
+	t.subs[s] = nil
+	if !resume {
+		return s, []Event{}
+	}
+	return s, t.since(lastID)
+}
+
+// Unsubscribe stops sending events to the subscription, and closes its
+// channel.
+func (t *Bus) Unsubscribe(s *Subscription) {
+	t.Lock()
+	defer t.Unlock()
+	t.unsubscribe(s)
+}
+
+func (t *Bus) unsubscribe(s *Subscription) {
+	if _, ok := t.subs[s]; !ok {
+		return
+	}
+	delete(t.subs, s)
+	close(s.c)
+}
+
+// since returns the events of the ring buffer published after lastID, in
+// publication order.
+func (t *Bus) since(lastID uint64) []Event {
+	if lastID == t.last {
+		return []Event{}
+	}
+	oldest := (t.head - t.count + len(t.ring)) % len(t.ring)
+	if lastID > t.last || t.count == 0 || lastID+1 < t.ring[oldest].ID {
+		return []Event{{ID: t.last, Kind: Reset, Time: time.Now()}}
+	}
+	l := make([]Event, 0)
+	for i := 0; i < t.count; i++ {
+		e := t.ring[(oldest+i)%len(t.ring)]
+		if e.ID > lastID {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+// Publish publishes a change event of a table entry on the default bus.
+func Publish(kind Kind, table string, id uint, props ...string) Event {
+	return bus.Publish(Event{
+		Kind:    kind,
+		Table:   table,
+		EntryID: id,
+		Props:   props,
+	})
+}
+
+// Subscribe subscribes to the events of the default bus.
+func Subscribe(lastID uint64, resume bool) (*Subscription, []Event) {
+	return bus.Subscribe(lastID, resume)
+}
+
+// Unsubscribe unsubscribes from the default bus.
+func Unsubscribe(s *Subscription) {
+	bus.Unsubscribe(s)
+}
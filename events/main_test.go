@@ -0,0 +1,97 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kinds(l []Event) []Kind {
+	k := make([]Kind, len(l))
+	for i, e := range l {
+		k[i] = e.Kind
+	}
+	return k
+}
+
+func TestBusResume(t *testing.T) {
+	b := NewBus(3)
+	first := b.Publish(Event{Kind: Create, Table: "nodes", EntryID: 1})
+	b.Publish(Event{Kind: Update, Table: "nodes", EntryID: 1})
+	last := b.Publish(Event{Kind: Delete, Table: "nodes", EntryID: 1})
+
+	tests := map[string]struct {
+		lastID uint64
+		resume bool
+		kinds  []Kind
+	}{
+		"no resume": {
+			lastID: first.ID,
+			kinds:  []Kind{},
+		},
+		"resume from first": {
+			lastID: first.ID,
+			resume: true,
+			kinds:  []Kind{Update, Delete},
+		},
+		"resume from before first": {
+			lastID: first.ID - 1,
+			resume: true,
+			kinds:  []Kind{Create, Update, Delete},
+		},
+		"resume from last": {
+			lastID: last.ID,
+			resume: true,
+			kinds:  []Kind{},
+		},
+		"resume from evicted": {
+			lastID: first.ID - 2,
+			resume: true,
+			kinds:  []Kind{Reset},
+		},
+		"resume from the future": {
+			lastID: last.ID + 1,
+			resume: true,
+			kinds:  []Kind{Reset},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		s, backlog := b.Subscribe(test.lastID, test.resume)
+		assert.Equal(t, test.kinds, kinds(backlog))
+		b.Unsubscribe(s)
+	}
+}
+
+func TestBusRingEviction(t *testing.T) {
+	b := NewBus(2)
+	first := b.Publish(Event{Kind: Create})
+	b.Publish(Event{Kind: Update})
+	b.Publish(Event{Kind: Delete})
+	_, backlog := b.Subscribe(first.ID, true)
+	assert.Equal(t, []Kind{Update, Delete}, kinds(backlog))
+}
+
+func TestBusSubscription(t *testing.T) {
+	b := NewBus(8)
+	s, _ := b.Subscribe(0, false)
+	e := b.Publish(Event{Kind: Create, Table: "tags", EntryID: 2})
+	assert.Equal(t, e, <-s.C)
+	b.Unsubscribe(s)
+	_, ok := <-s.C
+	assert.False(t, ok)
+	b.Unsubscribe(s)
+}
+
+func TestBusSlowSubscriber(t *testing.T) {
+	b := NewBus(8)
+	s, _ := b.Subscribe(0, false)
+	for i := 0; i <= defaultSubscriptionSize; i++ {
+		b.Publish(Event{Kind: Update})
+	}
+	n := 0
+	for range s.C {
+		n++
+	}
+	assert.Equal(t, defaultSubscriptionSize, n)
+}
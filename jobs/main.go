@@ -0,0 +1,204 @@
+// Package jobs runs the long-running operations in the background, out of
+// the api requests timeout.
+//
+// The handlers enqueue a job of a registered type, and respond with the
+// job, whose status, progress and result can then be read from the jobs
+// table. The jobs are stored in the database, so several api instances
+// can run workers on the same queue: a job is claimed before being run,
+// for a lease renewed while the job runs. A job whose lease expired, for
+// example because its api instance stopped, is claimed again.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"github.com/opensvc/collector-api/funcopt"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+
+	backoffBase = 10 * time.Second
+	backoffMax  = 10 * time.Minute
+)
+
+type (
+	// Handler runs a job, and returns the job result, json encoded in the
+	// jobs table. The context is canceled when the job is canceled or
+	// times out.
+	Handler func(ctx context.Context, job *Job) (interface{}, error)
+
+	// Type describes how the jobs of a type are run.
+	Type struct {
+		Name    string
+		Handler Handler
+
+		// MaxAttempts is the number of attempts after which a failing
+		// job is failed.
+		MaxAttempts int
+
+		// Timeout is the maximum duration of an attempt.
+		Timeout time.Duration
+
+		// Concurrency is the maximum number of jobs of this type run at
+		// the same time by an api instance.
+		Concurrency int
+
+		// Unique makes Enqueue return the queued job of this type with
+		// the same args, if any, instead of enqueueing a new one.
+		Unique bool
+	}
+
+	// Job is the handle of the job passed to its handler.
+	Job struct {
+		tables.Job
+	}
+)
+
+var (
+	ErrUnknownType = errors.New("unknown job type")
+
+	typesMu sync.RWMutex
+	types   = make(map[string]*Type)
+)
+
+// Register declares a job type. It is meant to be called from the init
+// function of the package implementing the handler.
+func Register(name string, handler Handler, opts ...funcopt.O) {
+	t := &Type{
+		Name:        name,
+		Handler:     handler,
+		MaxAttempts: 3,
+		Timeout:     10 * time.Minute,
+		Concurrency: 1,
+	}
+	if err := funcopt.Apply(t, opts...); err != nil {
+		panic(fmt.Sprintf("register job type %s: %s", name, err))
+	}
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	types[name] = t
+}
+
+func WithMaxAttempts(n int) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		if n < 1 {
+			return fmt.Errorf("max attempts must be positive")
+		}
+		t := i.(*Type)
+		t.MaxAttempts = n
+		return nil
+	})
+}
+
+func WithTimeout(d time.Duration) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*Type)
+		t.Timeout = d
+		return nil
+	})
+}
+
+func WithConcurrency(n int) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		if n < 1 {
+			return fmt.Errorf("concurrency must be positive")
+		}
+		t := i.(*Type)
+		t.Concurrency = n
+		return nil
+	})
+}
+
+func WithUnique(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*Type)
+		t.Unique = v
+		return nil
+	})
+}
+
+// GetType returns the registered job type.
+func GetType(name string) (*Type, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	t, ok := types[name]
+	return t, ok
+}
+
+// TypeNames returns the sorted names of the registered job types.
+func TypeNames() []string {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	l := make([]string, 0, len(types))
+	for name := range types {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Backoff returns the delay before the next attempt of a job that failed
+// the attempts number of times.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+	return d
+}
+
+// DecodeArgs decodes the job args into v.
+func (t *Job) DecodeArgs(v interface{}) error {
+	if len(t.Args) == 0 {
+		return nil
+	}
+	return json.Unmarshal(t.Args, v)
+}
+
+// SetProgress records the job progress, as a percentage and a message.
+// The progress of an attempt whose lease was lost to another attempt is
+// not recorded.
+func (t *Job) SetProgress(percent int, message string) error {
+	switch {
+	case percent < 0:
+		percent = 0
+	case percent > 100:
+		percent = 100
+	}
+	tx := db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", t.ID, StatusRunning, t.Attempts).
+		Updates(map[string]interface{}{
+			"progress": percent,
+			"message":  message,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return nil
+	}
+	t.Progress = percent
+	t.Message = message
+	events.Publish(events.Update, "jobs", t.ID, "message", "progress")
+	return nil
+}
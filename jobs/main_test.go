@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := map[string]struct {
+		attempts int
+		delay    time.Duration
+	}{
+		"no attempt": {
+			attempts: 0,
+			delay:    0,
+		},
+		"first failure": {
+			attempts: 1,
+			delay:    10 * time.Second,
+		},
+		"third failure": {
+			attempts: 3,
+			delay:    40 * time.Second,
+		},
+		"capped": {
+			attempts: 20,
+			delay:    10 * time.Minute,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.delay, Backoff(test.attempts))
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test_default", nil)
+	Register("test_options", nil,
+		WithMaxAttempts(5),
+		WithTimeout(time.Minute),
+		WithConcurrency(2),
+		WithUnique(true),
+	)
+	tests := map[string]struct {
+		name     string
+		expected Type
+	}{
+		"defaults": {
+			name: "test_default",
+			expected: Type{
+				Name:        "test_default",
+				MaxAttempts: 3,
+				Timeout:     10 * time.Minute,
+				Concurrency: 1,
+			},
+		},
+		"options": {
+			name: "test_options",
+			expected: Type{
+				Name:        "test_options",
+				MaxAttempts: 5,
+				Timeout:     time.Minute,
+				Concurrency: 2,
+				Unique:      true,
+			},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		jt, ok := GetType(test.name)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, *jt)
+	}
+	assert.Contains(t, TypeNames(), "test_default")
+	assert.Panics(t, func() { Register("test_invalid", nil, WithConcurrency(0)) })
+	_, ok := GetType("test_invalid")
+	assert.False(t, ok)
+}
+
+func TestJobDecodeArgs(t *testing.T) {
+	type args struct {
+		NodeID uint `json:"node_id"`
+	}
+	tests := map[string]struct {
+		raw      string
+		expected args
+		err      bool
+	}{
+		"empty": {
+			raw: "",
+		},
+		"set": {
+			raw:      `{"node_id":12}`,
+			expected: args{NodeID: 12},
+		},
+		"invalid": {
+			raw: `{"node_id":"a"}`,
+			err: true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		job := Job{Job: tables.Job{Args: datatypes.JSON(test.raw)}}
+		v := args{}
+		err := job.DecodeArgs(&v)
+		if test.err {
+			assert.NotNil(t, err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, v)
+	}
+}
@@ -0,0 +1,407 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"gorm.io/gorm"
+)
+
+const (
+	pollInterval = 5 * time.Second
+
+	// lease is the delay before another worker can claim a running job
+	// whose lease was not renewed.
+	lease         = time.Minute
+	leaseInterval = lease / 3
+)
+
+var (
+	ErrNotCancelable = errors.New("job is not queued nor running")
+	ErrNotRetryable  = errors.New("job is not failed nor canceled")
+
+	wake = make(chan interface{}, 1)
+)
+
+type (
+	pool struct {
+		sync.Mutex
+		free    int
+		running map[string]int
+		done    chan string
+	}
+)
+
+// Start runs the jobs workers until the context is done. At most workers
+// jobs are run at the same time, within the concurrency limit of their
+// type.
+func Start(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &pool{
+		free:    workers,
+		running: make(map[string]int),
+		done:    make(chan string, workers),
+	}
+	go p.loop(ctx)
+}
+
+// Enqueue creates a queued job of the registered type, owned by the user
+// with the specified id, and returns it.
+func Enqueue(typeName string, userID uint, args interface{}) (tables.Job, error) {
+	job := tables.Job{}
+	t, ok := GetType(typeName)
+	if !ok {
+		return job, fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return job, fmt.Errorf("encode args: %w", err)
+	}
+	if t.Unique {
+		err := db.DB().
+			Where("type = ? AND args = ? AND status = ?", typeName, string(b), StatusQueued).
+			Take(&job).Error
+		switch {
+		case err == nil:
+			return job, nil
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return job, err
+		}
+	}
+	job = tables.Job{
+		UserID:      userID,
+		Type:        typeName,
+		Args:        b,
+		Status:      StatusQueued,
+		MaxAttempts: t.MaxAttempts,
+		RunAfter:    time.Now(),
+	}
+	if err := db.DB().Create(&job).Error; err != nil {
+		return job, err
+	}
+	events.Publish(events.Create, "jobs", job.ID)
+	notify()
+	return job, nil
+}
+
+// Cancel cancels a queued job, or requests the cancellation of a running
+// job, whose context is canceled when its worker renews its lease.
+func Cancel(id uint) error {
+	now := time.Now()
+	tx := db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status = ?", id, StatusQueued).
+		Updates(map[string]interface{}{
+			"status":      StatusCanceled,
+			"finished_at": now,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 1 {
+		events.Publish(events.Update, "jobs", id, "finished_at", "status")
+		return nil
+	}
+	tx = db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status = ?", id, StatusRunning).
+		Update("cancel_requested", true)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 1 {
+		events.Publish(events.Update, "jobs", id, "cancel_requested")
+		return nil
+	}
+	return ErrNotCancelable
+}
+
+// Retry queues again a failed or canceled job, with its attempts reset.
+func Retry(id uint) error {
+	tx := db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status IN ?", id, []string{StatusFailed, StatusCanceled}).
+		Updates(map[string]interface{}{
+			"status":           StatusQueued,
+			"attempts":         0,
+			"progress":         0,
+			"message":          "",
+			"error":            "",
+			"cancel_requested": false,
+			"run_after":        time.Now(),
+			"started_at":       nil,
+			"finished_at":      nil,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return ErrNotRetryable
+	}
+	events.Publish(events.Update, "jobs", id, "status")
+	notify()
+	return nil
+}
+
+func notify() {
+	select {
+	case wake <- nil:
+	default:
+	}
+}
+
+func (t *pool) loop(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		t.runDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		case typeName := <-t.done:
+			t.Lock()
+			t.free++
+			t.running[typeName]--
+			t.Unlock()
+		}
+	}
+}
+
+// availableTypes returns the names of the job types the pool can run a
+// job of.
+func (t *pool) availableTypes() []string {
+	t.Lock()
+	defer t.Unlock()
+	l := make([]string, 0)
+	if t.free == 0 {
+		return l
+	}
+	for _, name := range TypeNames() {
+		if jt, ok := GetType(name); ok && t.running[name] < jt.Concurrency {
+			l = append(l, name)
+		}
+	}
+	return l
+}
+
+// runDue claims and runs due jobs until the pool is full or no job is due.
+func (t *pool) runDue(ctx context.Context) {
+	for {
+		names := t.availableTypes()
+		if len(names) == 0 {
+			return
+		}
+		job, ok, err := claimNext(names)
+		if err != nil {
+			log.Printf("jobs: claim: %s", err)
+			return
+		}
+		if !ok {
+			return
+		}
+		jt, _ := GetType(job.Type)
+		t.Lock()
+		t.free--
+		t.running[job.Type]++
+		t.Unlock()
+		go func() {
+			run(ctx, jt, job)
+			t.done <- job.Type
+		}()
+	}
+}
+
+// claimNext claims the next due job of one of the types: a queued job
+// whose run_after date is passed, or a running job whose lease expired.
+// It returns false if no job is due or another worker claimed it first.
+func claimNext(typeNames []string) (tables.Job, bool, error) {
+	for {
+		now := time.Now()
+		job := tables.Job{}
+		err := db.DB().
+			Where("type IN ?", typeNames).
+			Where("(status = ? AND run_after <= ?) OR (status = ? AND lease_until < ?)", StatusQueued, now, StatusRunning, now).
+			Order("run_after").
+			Take(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return job, false, nil
+		} else if err != nil {
+			return job, false, err
+		}
+		if job.Status == StatusRunning && job.Attempts >= job.MaxAttempts {
+			// the last attempt worker stopped
+			if err := expire(job, now); err != nil {
+				return job, false, err
+			}
+			continue
+		}
+		leaseUntil := now.Add(lease)
+		changes := map[string]interface{}{
+			"status":      StatusRunning,
+			"attempts":    job.Attempts + 1,
+			"lease_until": leaseUntil,
+			"started_at":  now,
+		}
+		tx := db.DB().Model(&tables.Job{}).
+			Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts).
+			Updates(changes)
+		if tx.Error != nil {
+			return job, false, tx.Error
+		}
+		if tx.RowsAffected == 0 {
+			// claimed by another worker, try the next one
+			continue
+		}
+		job.Status = StatusRunning
+		job.Attempts++
+		job.LeaseUntil = &leaseUntil
+		job.StartedAt = &now
+		events.Publish(events.Update, "jobs", job.ID, "attempts", "started_at", "status")
+		return job, true, nil
+	}
+}
+
+// expire fails a running job whose last attempt lease expired.
+func expire(job tables.Job, now time.Time) error {
+	tx := db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":      StatusFailed,
+			"error":       "lease expired",
+			"lease_until": nil,
+			"finished_at": now,
+		})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 1 {
+		events.Publish(events.Update, "jobs", job.ID, "status")
+	}
+	return nil
+}
+
+// run runs a claimed job, renewing its lease until the handler returns,
+// and records the attempt result.
+func run(ctx context.Context, jt *Type, job tables.Job) {
+	ctx, cancel := context.WithTimeout(ctx, jt.Timeout)
+	defer cancel()
+	stop := make(chan interface{})
+	canceled := make(chan interface{})
+	go keepLease(job, stop, canceled)
+	result, err := callHandler(ctx, jt, job, canceled, cancel)
+	close(stop)
+	if err := finish(jt, job, result, err); err != nil {
+		log.Printf("jobs: job %d: %s", job.ID, err)
+	}
+}
+
+func callHandler(ctx context.Context, jt *Type, job tables.Job, canceled <-chan interface{}, cancel func()) (result interface{}, err error) {
+	go func() {
+		select {
+		case <-canceled:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return jt.Handler(ctx, &Job{Job: job})
+}
+
+// keepLease renews the job attempt lease until stop is closed, and closes
+// canceled when the job cancellation is requested or when the lease was
+// lost to another attempt.
+func keepLease(job tables.Job, stop <-chan interface{}, canceled chan<- interface{}) {
+	id := job.ID
+	ticker := time.NewTicker(leaseInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		current := tables.Job{}
+		if err := db.DB().Select("cancel_requested").Where("id = ?", id).Take(&current).Error; err != nil {
+			log.Printf("jobs: job %d: renew lease: %s", id, err)
+			continue
+		}
+		if current.CancelRequested {
+			close(canceled)
+			return
+		}
+		tx := db.DB().Model(&tables.Job{}).
+			Where("id = ? AND status = ? AND attempts = ?", id, StatusRunning, job.Attempts).
+			Update("lease_until", time.Now().Add(lease))
+		if tx.Error != nil {
+			log.Printf("jobs: job %d: renew lease: %s", id, tx.Error)
+		} else if tx.RowsAffected == 0 {
+			log.Printf("jobs: job %d: attempt %d lease lost", id, job.Attempts)
+			close(canceled)
+			return
+		}
+	}
+}
+
+// finish records the result of a job attempt. A failed attempt is queued
+// again after a backoff delay, unless the job was canceled or has no
+// attempt left.
+func finish(jt *Type, job tables.Job, result interface{}, runErr error) error {
+	now := time.Now()
+	changes := map[string]interface{}{
+		"lease_until": nil,
+	}
+	current := tables.Job{}
+	if err := db.DB().Select("cancel_requested").Where("id = ?", job.ID).Take(&current).Error; err != nil {
+		return err
+	}
+	switch {
+	case current.CancelRequested:
+		changes["status"] = StatusCanceled
+		changes["finished_at"] = now
+		if runErr != nil {
+			changes["error"] = runErr.Error()
+		}
+	case runErr == nil:
+		b, err := json.Marshal(result)
+		if err != nil {
+			return finish(jt, job, nil, fmt.Errorf("encode result: %w", err))
+		}
+		changes["status"] = StatusSucceeded
+		changes["progress"] = 100
+		changes["result"] = string(b)
+		changes["error"] = ""
+		changes["finished_at"] = now
+	case job.Attempts >= job.MaxAttempts:
+		changes["status"] = StatusFailed
+		changes["error"] = runErr.Error()
+		changes["finished_at"] = now
+	default:
+		changes["status"] = StatusQueued
+		changes["error"] = runErr.Error()
+		changes["run_after"] = now.Add(Backoff(job.Attempts))
+	}
+	tx := db.DB().Model(&tables.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, StatusRunning, job.Attempts).
+		Updates(changes)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return fmt.Errorf("attempt %d lease lost, result discarded", job.Attempts)
+	}
+	events.Publish(events.Update, "jobs", job.ID, "status")
+	return nil
+}
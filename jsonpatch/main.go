@@ -0,0 +1,329 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7396) and JSON Patch
+// (RFC 6902) documents to decoded json values.
+//
+// Documents are the interface{} values produced by encoding/json:
+// map[string]interface{}, []interface{}, string, float64, bool and nil.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	PatchContentType      = "application/json-patch+json"
+)
+
+type (
+	// Operation is a RFC 6902 patch operation.
+	Operation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		From  string          `json:"from,omitempty"`
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+
+	// Patch is a RFC 6902 patch document.
+	Patch []Operation
+)
+
+// MergePatch returns the result of the application of the RFC 7396 merge
+// patch to doc. doc is not modified.
+func MergePatch(doc, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		d = make(map[string]interface{})
+	}
+	result := make(map[string]interface{})
+	for k, v := range d {
+		result[k] = v
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(result, k)
+		} else {
+			result[k] = MergePatch(result[k], v)
+		}
+	}
+	return result
+}
+
+// DecodePatch decodes a RFC 6902 patch document.
+func DecodePatch(b []byte) (Patch, error) {
+	p := make(Patch, 0)
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Apply returns the result of the application of the patch operations
+// to doc. The operations are applied in order and the application stops
+// on the first error, including failed "test" operations. doc is not
+// modified.
+func (t Patch) Apply(doc interface{}) (interface{}, error) {
+	doc = deepCopy(doc)
+	for i, op := range t {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("operation %d: %s %s: %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func (t Operation) value() (interface{}, error) {
+	var v interface{}
+	if len(t.Value) == 0 {
+		return nil, fmt.Errorf("missing value")
+	}
+	if err := json.Unmarshal(t.Value, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (t Operation) apply(doc interface{}) (interface{}, error) {
+	switch t.Op {
+	case "add":
+		v, err := t.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, t.Path, v)
+	case "remove":
+		doc, _, err := remove(doc, t.Path)
+		return doc, err
+	case "replace":
+		v, err := t.value()
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = remove(doc, t.Path); err != nil {
+			return nil, err
+		}
+		return add(doc, t.Path, v)
+	case "move":
+		if strings.HasPrefix(t.Path, t.From+"/") {
+			return nil, fmt.Errorf("can not move a value into one of its children")
+		}
+		doc, v, err := remove(doc, t.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, t.Path, v)
+	case "copy":
+		v, err := get(doc, t.From)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, t.Path, deepCopy(v))
+	case "test":
+		v, err := t.value()
+		if err != nil {
+			return nil, err
+		}
+		current, err := get(doc, t.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(v, current) {
+			return nil, ErrTestFailed
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unsupported operation")
+	}
+}
+
+// ErrTestFailed is returned when the value of a "test" operation differs
+// from the document value.
+var ErrTestFailed = fmt.Errorf("test failed")
+
+// parsePointer splits a RFC 6901 json pointer into its unescaped tokens.
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(s, "/") {
+		return nil, fmt.Errorf("invalid json pointer %s", s)
+	}
+	l := strings.Split(s[1:], "/")
+	for i, token := range l {
+		token = strings.ReplaceAll(token, "~1", "/")
+		token = strings.ReplaceAll(token, "~0", "~")
+		l[i] = token
+	}
+	return l, nil
+}
+
+func arrayIndex(token string, n int, appendable bool) (int, error) {
+	if appendable && token == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("invalid array index %s", token)
+	}
+	max := n - 1
+	if appendable {
+		max = n
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+func get(doc interface{}, path string) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if doc, ok = v[token]; !ok {
+				return nil, fmt.Errorf("path not found")
+			}
+		case []interface{}:
+			i, err := arrayIndex(token, len(v), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = v[i]
+		default:
+			return nil, fmt.Errorf("path not found")
+		}
+	}
+	return doc, nil
+}
+
+// add sets the value at path, and returns the modified document.
+func add(doc interface{}, path string, value interface{}) (interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	parentPath := path[:strings.LastIndex(path, "/")]
+	parent, err := get(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		v[token] = value
+		return doc, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(v), true)
+		if err != nil {
+			return nil, err
+		}
+		l := append(v[:i:i], value)
+		l = append(l, v[i:]...)
+		return set(doc, parentPath, l)
+	default:
+		return nil, fmt.Errorf("parent is not a container")
+	}
+}
+
+// set replaces the existing value at path, and returns the modified
+// document.
+func set(doc interface{}, path string, value interface{}) (interface{}, error) {
+	if path == "" {
+		return value, nil
+	}
+	parentPath := path[:strings.LastIndex(path, "/")]
+	parent, err := get(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		v[token] = value
+	case []interface{}:
+		i, err := arrayIndex(token, len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = value
+	default:
+		return nil, fmt.Errorf("path not found")
+	}
+	return doc, nil
+}
+
+// remove deletes the value at path, and returns the modified document and
+// the removed value.
+func remove(doc interface{}, path string) (interface{}, interface{}, error) {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	parentPath := path[:strings.LastIndex(path, "/")]
+	parent, err := get(doc, parentPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	token := tokens[len(tokens)-1]
+	switch v := parent.(type) {
+	case map[string]interface{}:
+		removed, ok := v[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("path not found")
+		}
+		delete(v, token)
+		return doc, removed, nil
+	case []interface{}:
+		i, err := arrayIndex(token, len(v), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		removed := v[i]
+		l := append(v[:i:i], v[i+1:]...)
+		doc, err = set(doc, parentPath, l)
+		return doc, removed, err
+	default:
+		return nil, nil, fmt.Errorf("path not found")
+	}
+}
+
+func deepCopy(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = deepCopy(e)
+		}
+		return l
+	default:
+		return v
+	}
+}
@@ -0,0 +1,128 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decode(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		doc    string
+		patch  string
+		output string
+	}{
+		"replace value": {
+			doc:    `{"a": "b"}`,
+			patch:  `{"a": "c"}`,
+			output: `{"a": "c"}`,
+		},
+		"unset value": {
+			doc:    `{"a": "b", "c": "d"}`,
+			patch:  `{"a": null}`,
+			output: `{"c": "d"}`,
+		},
+		"nested merge": {
+			doc:    `{"a": {"b": "c", "d": "e"}}`,
+			patch:  `{"a": {"d": null, "f": "g"}}`,
+			output: `{"a": {"b": "c", "f": "g"}}`,
+		},
+		"replace array": {
+			doc:    `{"a": [1, 2]}`,
+			patch:  `{"a": [3]}`,
+			output: `{"a": [3]}`,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		doc := decode(test.doc)
+		output := MergePatch(doc, decode(test.patch))
+		assert.Equal(t, decode(test.output), output)
+		assert.Equal(t, decode(test.doc), doc, "original document modified")
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	tests := map[string]struct {
+		doc    string
+		patch  string
+		output string
+		err    bool
+	}{
+		"add and replace": {
+			doc:    `{"a": "b"}`,
+			patch:  `[{"op": "add", "path": "/c", "value": 1}, {"op": "replace", "path": "/a", "value": null}]`,
+			output: `{"a": null, "c": 1}`,
+		},
+		"remove": {
+			doc:    `{"a": "b", "c": "d"}`,
+			patch:  `[{"op": "remove", "path": "/a"}]`,
+			output: `{"c": "d"}`,
+		},
+		"array insert and append": {
+			doc:    `{"a": [1, 3]}`,
+			patch:  `[{"op": "add", "path": "/a/1", "value": 2}, {"op": "add", "path": "/a/-", "value": 4}]`,
+			output: `{"a": [1, 2, 3, 4]}`,
+		},
+		"nested array remove": {
+			doc:    `[[1, 2], [3]]`,
+			patch:  `[{"op": "remove", "path": "/0/0"}]`,
+			output: `[[2], [3]]`,
+		},
+		"move and copy": {
+			doc:    `{"a": {"b": 1}, "c": {}}`,
+			patch:  `[{"op": "move", "path": "/c/b", "from": "/a/b"}, {"op": "copy", "path": "/d", "from": "/c"}]`,
+			output: `{"a": {}, "c": {"b": 1}, "d": {"b": 1}}`,
+		},
+		"escaped pointer": {
+			doc:    `{"a/b": {"c~d": 1}}`,
+			patch:  `[{"op": "replace", "path": "/a~1b/c~0d", "value": 2}]`,
+			output: `{"a/b": {"c~d": 2}}`,
+		},
+		"test success": {
+			doc:    `{"a": "b"}`,
+			patch:  `[{"op": "test", "path": "/a", "value": "b"}, {"op": "replace", "path": "/a", "value": "c"}]`,
+			output: `{"a": "c"}`,
+		},
+		"test failure": {
+			doc:   `{"a": "b"}`,
+			patch: `[{"op": "test", "path": "/a", "value": "c"}]`,
+			err:   true,
+		},
+		"replace missing path": {
+			doc:   `{"a": "b"}`,
+			patch: `[{"op": "replace", "path": "/c", "value": "d"}]`,
+			err:   true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		patch, err := DecodePatch([]byte(test.patch))
+		assert.NoError(t, err)
+		doc := decode(test.doc)
+		output, err := patch.Apply(doc)
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, decode(test.output), output)
+		assert.Equal(t, decode(test.doc), doc, "original document modified")
+	}
+}
+
+func TestErrTestFailed(t *testing.T) {
+	patch, _ := DecodePatch([]byte(`[{"op": "test", "path": "", "value": {}}]`))
+	_, err := patch.Apply(decode(`{"a": 1}`))
+	assert.True(t, errors.Is(err, ErrTestFailed))
+}
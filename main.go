@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/dashboard"
 	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
 	_ "github.com/opensvc/collector-api/docs"
+	"github.com/opensvc/collector-api/jobs"
+	"github.com/opensvc/collector-api/notify"
 	"github.com/opensvc/collector-api/routes"
+	"github.com/opensvc/collector-api/webhook"
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	"github.com/go-chi/chi/middleware"
@@ -49,6 +54,10 @@ func main() {
 	if err := db.Init(); err != nil {
 		fatal(err)
 	}
+	jobs.Start(context.Background(), viper.GetInt("jobs.workers"))
+	dashboard.Start(context.Background())
+	notify.Start(context.Background())
+	webhook.Start(context.Background())
 	addr := viper.GetString("Listen")
 	log.Printf("Starting server on %v\n", addr)
 	if err := http.ListenAndServe(addr, router()); err != nil {
@@ -64,103 +73,303 @@ func router() http.Handler {
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
-	r.Use(middleware.Timeout(60 * time.Second))
 	r.Mount("/api/swagger", httpSwagger.WrapHandler)
 
 	// Protected routes
 	r.Group(func(r chi.Router) {
 		r.Route("/api", func(r chi.Router) {
 			r.Use(auth.Middleware)
-			r.Route("/auth/node/token", func(r chi.Router) {
-				r.Get("/", routes.GetNodeToken)
-			})
-			r.Route("/auth/user/token", func(r chi.Router) {
-				r.Get("/", routes.GetUserToken)
-			})
-			r.Route("/nodes", func(r chi.Router) {
-				r.Route("/{id}", func(r chi.Router) {
-					r.Use(tables.NodeCtx)
-					r.Get("/candidate_tags", routes.GetNodeCandidateTags)
-					r.Route("/tags", func(r chi.Router) {
+			// event streams outlive the requests timeout
+			r.Get("/events", routes.GetEvents)
+			r.Group(func(r chi.Router) {
+				// the table responses streamed as ndjson or csv
+				// outlive the requests timeout
+				r.Use(routes.StreamTimeout(60 * time.Second))
+				r.Route("/actions", func(r chi.Router) {
+					r.Post("/begin", routes.PostActionsBegin)
+					r.Post("/end", routes.PostActionsEnd)
+					r.Get("/failed", routes.GetActionsFailed)
+					r.Get("/", routes.GetActions)
+				})
+				r.Route("/apps", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.AppCtx)
+						r.Get("/", routes.GetApp)
+					})
+					r.Get("/", routes.GetApps)
+				})
+				r.Route("/auth/node/token", func(r chi.Router) {
+					r.Get("/", routes.GetNodeToken)
+				})
+				r.Route("/auth/user/token", func(r chi.Router) {
+					r.Get("/", routes.GetUserToken)
+				})
+				r.Route("/checks", func(r chi.Router) {
+					r.Route("/thresholds", func(r chi.Router) {
+						r.Delete("/{id}", routes.DelChecksThreshold)
+						r.Get("/", routes.GetChecksThresholds)
+						r.Post("/", routes.PostChecksThresholds)
+					})
+					r.Get("/", routes.GetChecks)
+					r.Post("/", routes.PostChecks)
+				})
+				r.Route("/clusters", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.ClusterCtx)
+						r.Get("/nodes", routes.GetClusterNodes)
+						r.Get("/services", routes.GetClusterServices)
+						r.Get("/", routes.GetCluster)
+						r.Delete("/", routes.DelCluster)
+						r.Patch("/", routes.PatchCluster)
+					})
+					r.Get("/", routes.GetClusters)
+					r.Post("/", routes.PostClusters)
+				})
+				r.Route("/compliance", func(r chi.Router) {
+					r.Route("/modulesets", func(r chi.Router) {
 						r.Route("/{id}", func(r chi.Router) {
-							r.Use(tables.TagCtx)
-							r.Use(tables.NodeTagCtx)
-							r.Get("/", routes.GetNodeTag)
+							r.Use(tables.CompModulesetCtx)
+							r.Route("/modules", func(r chi.Router) {
+								r.Delete("/{module_id}", routes.DelComplianceModulesetModule)
+								r.Get("/", routes.GetComplianceModulesetModules)
+								r.Post("/", routes.PostComplianceModulesetModules)
+							})
+							r.Post("/{kind:nodes|services|tags}/{target_id}", routes.PostComplianceModulesetAttachment)
+							r.Delete("/{kind:nodes|services|tags}/{target_id}", routes.DelComplianceModulesetAttachment)
+							r.Get("/", routes.GetComplianceModuleset)
+							r.Delete("/", routes.DelComplianceModuleset)
+							r.Patch("/", routes.PatchComplianceModuleset)
+						})
+						r.Get("/", routes.GetComplianceModulesets)
+						r.Post("/", routes.PostComplianceModulesets)
+					})
+					r.Get("/rules", routes.GetComplianceRules)
+					r.Route("/rulesets", func(r chi.Router) {
+						r.Route("/{id}", func(r chi.Router) {
+							r.Use(tables.CompRulesetCtx)
+							r.Route("/variables", func(r chi.Router) {
+								r.Delete("/{variable_id}", routes.DelComplianceRulesetVariable)
+								r.Get("/", routes.GetComplianceRulesetVariables)
+								r.Post("/", routes.PostComplianceRulesetVariables)
+							})
+							r.Post("/{kind:nodes|services|tags}/{target_id}", routes.PostComplianceRulesetAttachment)
+							r.Delete("/{kind:nodes|services|tags}/{target_id}", routes.DelComplianceRulesetAttachment)
+							r.Get("/", routes.GetComplianceRuleset)
+							r.Delete("/", routes.DelComplianceRuleset)
+							r.Patch("/", routes.PatchComplianceRuleset)
 						})
-						r.Get("/", routes.GetNodeTags)
+						r.Get("/", routes.GetComplianceRulesets)
+						r.Post("/", routes.PostComplianceRulesets)
 					})
-					r.Get("/", routes.GetNode)
-					r.Delete("/", routes.DelNode)
-					r.Post("/", routes.PostNode)
+					r.Get("/status", routes.GetComplianceStatus)
+					r.Post("/status", routes.PostComplianceStatus)
 				})
-				r.Route("/tags", func(r chi.Router) {
+				r.Route("/dashboard", func(r chi.Router) {
 					r.Route("/{id}", func(r chi.Router) {
-						r.Use(tables.NodeTagCtx)
-						r.Get("/", routes.GetNodeTag)
+						r.Use(tables.DashboardCtx)
+						r.Post("/ack", routes.PostDashboardAck)
+						r.Delete("/ack", routes.DelDashboardAck)
 					})
-					r.Get("/", routes.GetNodesTags)
+					r.Get("/acks", routes.GetDashboardAcks)
+					r.Post("/refresh", routes.PostDashboardRefresh)
+					r.Get("/", routes.GetDashboard)
 				})
-				r.Get("/", routes.GetNodes)
-				r.Post("/", routes.PostNodes)
-			})
-			r.Route("/services", func(r chi.Router) {
-				r.Route("/{id}", func(r chi.Router) {
-					r.Use(tables.ServiceCtx)
-					r.Get("/candidate_tags", routes.GetServiceCandidateTags)
-					r.Get("/tags", routes.GetServiceTags)
-					r.Get("/", routes.GetService)
+				r.Route("/disks", func(r chi.Router) {
+					r.Get("/info", routes.GetDisksInfo)
+					r.Post("/info", routes.PostDisksInfo)
+					r.Delete("/info/{id}", routes.DelDiskInfo)
+					r.Get("/", routes.GetDisks)
+					r.Post("/", routes.PostDisks)
 				})
-				r.Route("/tags", func(r chi.Router) {
+				r.Route("/filtersets", func(r chi.Router) {
 					r.Route("/{id}", func(r chi.Router) {
-						r.Use(tables.ServiceTagCtx)
-						r.Get("/", routes.GetServiceTag)
+						r.Use(tables.FiltersetCtx)
+						r.Route("/groups", func(r chi.Router) {
+							r.Post("/{group_id}", routes.PostFiltersetGroup)
+							r.Delete("/{group_id}", routes.DelFiltersetGroup)
+							r.Get("/", routes.GetFiltersetGroups)
+						})
+						r.Get("/", routes.GetFilterset)
+						r.Delete("/", routes.DelFilterset)
+						r.Patch("/", routes.PatchFilterset)
 					})
-					r.Get("/", routes.GetServicesTags)
+					r.Get("/", routes.GetFiltersets)
+					r.Post("/", routes.PostFiltersets)
 				})
-				r.Get("/", routes.GetServices)
-			})
-			r.Route("/tags", func(r chi.Router) {
-				r.Route("/{id}", func(r chi.Router) {
-					r.Use(tables.TagCtx)
-					r.Route("/nodes", func(r chi.Router) {
-						r.Get("/", routes.GetTagNodes)
+				r.Route("/jobs", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.JobCtx)
+						r.Post("/retry", routes.PostJobRetry)
+						r.Get("/", routes.GetJob)
+						r.Delete("/", routes.DelJob)
 					})
-					r.Route("/services", func(r chi.Router) {
-						r.Get("/", routes.GetTagServices)
+					r.Get("/", routes.GetJobs)
+				})
+				r.Route("/nodes", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.NodeCtx)
+						r.Get("/actions", routes.GetNodeActions)
+						r.Get("/candidate_tags", routes.GetNodeCandidateTags)
+						r.Get("/checks", routes.GetNodeChecks)
+						r.Get("/disks", routes.GetNodeDisks)
+						r.Get("/hbas", routes.GetNodeHBAs)
+						r.Get("/ips", routes.GetNodeIPs)
+						r.Get("/packages", routes.GetNodePackages)
+						r.Get("/patches", routes.GetNodePatches)
+						r.Get("/targets", routes.GetNodeTargets)
+						r.Post("/snooze", routes.PostNodeSnooze)
+						r.Delete("/snooze", routes.DelNodeSnooze)
+						r.Route("/tags", func(r chi.Router) {
+							r.Route("/{id}", func(r chi.Router) {
+								r.Use(tables.TagCtx)
+								r.Use(tables.NodeTagCtx)
+								r.Get("/", routes.GetNodeTag)
+								r.Patch("/", routes.PatchNodeTag)
+							})
+							r.Get("/", routes.GetNodeTags)
+						})
+						r.Get("/", routes.GetNode)
+						r.Delete("/", routes.DelNode)
+						r.Post("/", routes.PostNode)
+						r.Patch("/", routes.PatchNode)
 					})
-					r.Get("/", routes.GetTag)
-					r.Delete("/", routes.DelTag)
+					r.Route("/tags", func(r chi.Router) {
+						r.Route("/{id}", func(r chi.Router) {
+							r.Use(tables.NodeTagCtx)
+							r.Get("/", routes.GetNodeTag)
+							r.Patch("/", routes.PatchNodeTag)
+						})
+						r.Get("/", routes.GetNodesTags)
+					})
+					r.Get("/hbas", routes.GetNodesHBAs)
+					r.Post("/hbas", routes.PostNodesHBAs)
+					r.Get("/ips", routes.GetNodesIPs)
+					r.Post("/ips", routes.PostNodesIPs)
+					r.Get("/targets", routes.GetNodesTargets)
+					r.Post("/targets", routes.PostNodesTargets)
+					r.Get("/", routes.GetNodes)
+					r.Post("/", routes.PostNodes)
 				})
-				r.Get("/", routes.GetTags)
-				r.Post("/", routes.PostTags)
-				r.Delete("/", routes.DelTags)
-			})
-			r.Route("/users", func(r chi.Router) {
-				r.Route("/{id}", func(r chi.Router) {
-					r.Use(tables.UserCtx)
-					r.Route("/apps", func(r chi.Router) {
-						r.Route("/responsible", func(r chi.Router) {
-							r.Get("/", routes.GetUserAppsResponsible)
+				r.Route("/packages", func(r chi.Router) {
+					r.Get("/nodes", routes.GetPackagesNodes)
+					r.Get("/", routes.GetPackages)
+					r.Post("/", routes.PostPackages)
+				})
+				r.Route("/patches", func(r chi.Router) {
+					r.Get("/", routes.GetPatches)
+					r.Post("/", routes.PostPatches)
+				})
+				r.Post("/query", routes.PostQuery)
+				r.Route("/schema", func(r chi.Router) {
+					r.Get("/joins", routes.GetSchemaJoins)
+					r.Get("/", routes.GetSchema)
+				})
+				r.Get("/search", routes.GetSearch)
+				r.Route("/services", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.ServiceCtx)
+						r.Get("/actions", routes.GetServiceActions)
+						r.Get("/candidate_tags", routes.GetServiceCandidateTags)
+						r.Route("/config", func(r chi.Router) {
+							r.Get("/diff", routes.GetServiceConfigDiff)
+							r.Get("/history", routes.GetServiceConfigHistory)
+							r.Get("/keywords", routes.GetServiceConfigKeywords)
+							r.Get("/", routes.GetServiceConfig)
+							r.Post("/", routes.PostServiceConfig)
 						})
-						r.Route("/publication", func(r chi.Router) {
-							r.Get("/", routes.GetUserAppsPublication)
+						r.Get("/disks", routes.GetServiceDisks)
+						r.Post("/snooze", routes.PostServiceSnooze)
+						r.Delete("/snooze", routes.DelServiceSnooze)
+						r.Route("/tags", func(r chi.Router) {
+							r.Route("/{id}", func(r chi.Router) {
+								r.Use(tables.TagCtx)
+								r.Use(tables.ServiceTagCtx)
+								r.Get("/", routes.GetServiceTag)
+								r.Patch("/", routes.PatchServiceTag)
+							})
+							r.Get("/", routes.GetServiceTags)
 						})
+						r.Get("/", routes.GetService)
+						r.Patch("/", routes.PatchService)
+					})
+					r.Route("/config", func(r chi.Router) {
+						r.Get("/keywords", routes.GetServicesConfigKeywords)
 					})
-					r.Route("/groups", func(r chi.Router) {
-						r.Get("/", routes.GetUserGroups)
+					r.Route("/tags", func(r chi.Router) {
+						r.Route("/{id}", func(r chi.Router) {
+							r.Use(tables.ServiceTagCtx)
+							r.Get("/", routes.GetServiceTag)
+							r.Patch("/", routes.PatchServiceTag)
+						})
+						r.Get("/", routes.GetServicesTags)
+					})
+					r.Get("/", routes.GetServices)
+				})
+				r.Route("/tags", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.TagCtx)
+						r.Route("/nodes", func(r chi.Router) {
+							r.Get("/", routes.GetTagNodes)
+						})
+						r.Route("/services", func(r chi.Router) {
+							r.Get("/", routes.GetTagServices)
+						})
+						r.Get("/", routes.GetTag)
+						r.Delete("/", routes.DelTag)
+						r.Patch("/", routes.PatchTag)
+					})
+					r.Get("/", routes.GetTags)
+					r.Post("/", routes.PostTags)
+					r.Delete("/", routes.DelTags)
+				})
+				r.Route("/users", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.UserCtx)
+						r.Route("/apps", func(r chi.Router) {
+							r.Route("/responsible", func(r chi.Router) {
+								r.Get("/", routes.GetUserAppsResponsible)
+							})
+							r.Route("/publication", func(r chi.Router) {
+								r.Get("/", routes.GetUserAppsPublication)
+							})
+						})
+						r.Route("/filterset", func(r chi.Router) {
+							r.Get("/", routes.GetUserFilterset)
+							r.Put("/", routes.PutUserFilterset)
+							r.Delete("/", routes.DelUserFilterset)
+						})
+						r.Route("/groups", func(r chi.Router) {
+							r.Get("/", routes.GetUserGroups)
+						})
+						//r.Get("/dump", routes.GetUserDump)
+						r.Get("/", routes.GetUser)
+						r.Delete("/", routes.DelUser)
+						r.Patch("/", routes.PatchUser)
+					})
+					r.Get("/", routes.GetUsers)
+					r.Post("/", routes.PostUsers)
+				})
+				r.Route("/webhooks", func(r chi.Router) {
+					r.Route("/{id}", func(r chi.Router) {
+						r.Use(tables.WebhookCtx)
+						r.Route("/deliveries", func(r chi.Router) {
+							r.Post("/{delivery_id}/retry", routes.PostWebhookDeliveryRetry)
+							r.Get("/", routes.GetWebhookDeliveries)
+						})
+						r.Get("/", routes.GetWebhook)
+						r.Delete("/", routes.DelWebhook)
+						r.Patch("/", routes.PatchWebhook)
 					})
-					//r.Get("/dump", routes.GetUserDump)
-					r.Get("/", routes.GetUser)
-					r.Delete("/", routes.DelUser)
+					r.Get("/", routes.GetWebhooks)
+					r.Post("/", routes.PostWebhooks)
 				})
-				r.Get("/", routes.GetUsers)
-				r.Post("/", routes.PostUsers)
 			})
 		})
 	})
 
 	// Public routes
 	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte("welcome anonymous"))
 		})
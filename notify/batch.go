@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	batchKey struct {
+		UserID  uint
+		Channel string
+	}
+
+	batch struct {
+		to   Recipient
+		msgs []Message
+	}
+
+	// batcher groups the messages sent to a user through a channel over
+	// a delay, so the user receives a single notification for the
+	// messages of the delay. The batches are kept in memory.
+	batcher struct {
+		sync.Mutex
+		pending map[batchKey]*batch
+		send    func(channel string, to Recipient, msgs []Message)
+	}
+)
+
+func newBatcher(send func(channel string, to Recipient, msgs []Message)) *batcher {
+	return &batcher{
+		pending: make(map[batchKey]*batch),
+		send:    send,
+	}
+}
+
+// Add sends the message after the delay, with the other messages added
+// to the user channel batch in the meantime. The message is sent
+// immediately if the delay is not positive.
+func (t *batcher) Add(channel string, to Recipient, m Message, delay time.Duration) {
+	if delay <= 0 {
+		go t.send(channel, to, []Message{m})
+		return
+	}
+	key := batchKey{UserID: to.UserID, Channel: channel}
+	t.Lock()
+	defer t.Unlock()
+	if b, ok := t.pending[key]; ok {
+		b.msgs = append(b.msgs, m)
+		return
+	}
+	t.pending[key] = &batch{to: to, msgs: []Message{m}}
+	time.AfterFunc(delay, func() { t.flush(key) })
+}
+
+func (t *batcher) flush(key batchKey) {
+	t.Lock()
+	b, ok := t.pending[key]
+	delete(t.pending, key)
+	t.Unlock()
+	if ok {
+		t.send(key.Channel, b.to, b.msgs)
+	}
+}
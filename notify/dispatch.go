@@ -0,0 +1,198 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const (
+	sendTimeout = 30 * time.Second
+)
+
+// Start registers the configured channels, and sends the dashboard alerts
+// raised until the context is done.
+func Start(ctx context.Context) {
+	configure()
+	b := newBatcher(send)
+	go loop(ctx, b)
+}
+
+// configure registers the channels configured in the notify section.
+func configure() {
+	client := &http.Client{Timeout: sendTimeout}
+	if addr := viper.GetString("notify.smtp.addr"); addr != "" {
+		RegisterChannel("email", SMTP{
+			Addr:     addr,
+			From:     viper.GetString("notify.smtp.from"),
+			Username: viper.GetString("notify.smtp.username"),
+			Password: viper.GetString("notify.smtp.password"),
+		})
+	}
+	if url := viper.GetString("notify.slack.url"); url != "" {
+		RegisterChannel("slack", Slack{URL: url, Client: client})
+	}
+	if url := viper.GetString("notify.webhook.url"); url != "" {
+		RegisterChannel("webhook", Webhook{URL: url, Client: client})
+	}
+}
+
+func loop(ctx context.Context, b *batcher) {
+	var (
+		lastID uint64
+		resume bool
+	)
+	for {
+		sub, backlog := events.Subscribe(lastID, resume)
+		for _, e := range backlog {
+			handle(b, e)
+			lastID = e.ID
+		}
+	events:
+		for {
+			select {
+			case <-ctx.Done():
+				events.Unsubscribe(sub)
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					// too slow, resume from the last event
+					break events
+				}
+				handle(b, e)
+				lastID = e.ID
+			}
+		}
+		resume = true
+	}
+}
+
+// handle sends the alert of a dashboard entry creation event to the users
+// responsible for its app. The alert is sent again when its severity
+// increases, or when it is no longer acked.
+func handle(b *batcher, e events.Event) {
+	if !notified(e) {
+		return
+	}
+	m, ok, err := alertMessage(e.EntryID)
+	if err != nil {
+		log.Printf("notify: alert %d: %s", e.EntryID, err)
+		return
+	} else if !ok {
+		return
+	}
+	users, err := responsibleUsers(m.App)
+	if err != nil {
+		log.Printf("notify: alert %d: %s", e.EntryID, err)
+		return
+	}
+	for _, u := range users {
+		for _, r := range routes(u, m) {
+			if _, ok := getChannel(r.Channel); !ok {
+				continue
+			}
+			b.Add(r.Channel, recipient(u), m, r.Delay)
+		}
+	}
+}
+
+// notified returns true if the event is a dashboard entry creation, or a
+// dashboard entry update of its severity or acked flag.
+func notified(e events.Event) bool {
+	if e.Table != "dashboard" {
+		return false
+	}
+	switch e.Kind {
+	case events.Create:
+		return true
+	case events.Update:
+		for _, prop := range e.Props {
+			if prop == "dash_severity" || prop == "acked" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// alertMessage returns the message of a dashboard entry, and false if the
+// entry is not to be notified.
+func alertMessage(id uint) (Message, bool, error) {
+	m := Message{}
+	e := tables.Dashboard{}
+	if err := db.DB().Where("id = ?", id).Take(&e).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return m, false, nil
+	} else if err != nil {
+		return m, false, err
+	}
+	if e.Acked {
+		return m, false, nil
+	}
+	var target string
+	if e.NodeID != "" {
+		node := tables.Node{}
+		if err := db.DB().Where("node_id = ?", e.NodeID).Take(&node).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return m, false, nil
+		} else if err != nil {
+			return m, false, err
+		}
+		if !node.Notifications {
+			return m, false, nil
+		}
+		target = node.Nodename
+	} else {
+		svc := tables.Service{}
+		if err := db.DB().Where("svc_id = ?", e.SvcID).Take(&svc).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return m, false, nil
+		} else if err != nil {
+			return m, false, err
+		}
+		target = svc.Svcname
+	}
+	m = Message{
+		Level:   LevelOfSeverity(e.Severity),
+		Subject: fmt.Sprintf("%s: %s", target, e.Type),
+		Text:    dashboard.Message(e),
+		App:     e.App,
+		NodeID:  e.NodeID,
+		SvcID:   e.SvcID,
+		Time:    e.CreatedAt,
+	}
+	return m, true, nil
+}
+
+// responsibleUsers returns the users member of a group responsible for
+// the app.
+func responsibleUsers(app string) ([]tables.User, error) {
+	users := make([]tables.User, 0)
+	err := db.DB().Table("auth_user").
+		Joins("JOIN auth_membership ON auth_membership.user_id = auth_user.id").
+		Joins("JOIN apps_responsibles ON apps_responsibles.group_id = auth_membership.group_id").
+		Joins("JOIN apps ON apps.id = apps_responsibles.app_id").
+		Where("apps.app = ? AND auth_user.deleted_at IS NULL", app).
+		Select("DISTINCT auth_user.*").
+		Find(&users).Error
+	return users, err
+}
+
+func send(channel string, to Recipient, msgs []Message) {
+	c, ok := getChannel(channel)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+	if err := c.Send(ctx, to, msgs); err != nil {
+		log.Printf("notify: %s: user %s: %s", channel, to.Username, err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package notify sends the dashboard alerts to the users responsible for
+// the app of the alerted node or service, via the apps_responsibles
+// groups.
+//
+// The messages are sent through the channels selected by the user
+// preferences:
+//
+//	email_notifications  "T" to receive the messages of level
+//	                     email_log_level and above by email
+//	im_notifications     "T" to receive the messages of level im_log_level
+//	                     and above through the im_type channel ("slack" or
+//	                     "webhook"), batched over im_notifications_delay
+//	                     minutes
+//
+// The acknowledged alerts and the alerts on nodes with notifications
+// disabled are not sent.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelCritical
+)
+
+type (
+	// Level is the level of a message, compared to the user log level
+	// thresholds.
+	Level int
+
+	// Message is a notification sent to a user.
+	Message struct {
+		Level   Level     `json:"level"`
+		Subject string    `json:"subject"`
+		Text    string    `json:"text"`
+		App     string    `json:"app,omitempty"`
+		NodeID  string    `json:"node_id,omitempty"`
+		SvcID   string    `json:"svc_id,omitempty"`
+		Time    time.Time `json:"time"`
+	}
+
+	// Recipient is the user a message is sent to.
+	Recipient struct {
+		UserID     uint   `json:"user_id"`
+		Username   string `json:"username"`
+		Email      string `json:"email,omitempty"`
+		IMUsername string `json:"im_username,omitempty"`
+	}
+
+	// Channel sends messages to a recipient.
+	Channel interface {
+		Send(ctx context.Context, to Recipient, msgs []Message) error
+	}
+
+	// route is a channel a message is sent to a user through, after the
+	// batching delay.
+	route struct {
+		Channel string
+		Delay   time.Duration
+	}
+)
+
+var (
+	levelNames = []string{"debug", "info", "warning", "error", "critical"}
+
+	channelsMu sync.RWMutex
+	channels   = make(map[string]Channel)
+)
+
+func (t Level) String() string {
+	if t < 0 || int(t) >= len(levelNames) {
+		return fmt.Sprint(int(t))
+	}
+	return levelNames[t]
+}
+
+// MarshalText implements encoding.TextMarshaler, so the json encoded
+// messages carry the level name.
+func (t Level) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// ParseLevel returns the level of a log level name, as stored in the user
+// preferences. The empty name is the "warning" default.
+func ParseLevel(s string) (Level, bool) {
+	if s == "" {
+		return LevelWarning, true
+	}
+	for i, name := range levelNames {
+		if name == s {
+			return Level(i), true
+		}
+	}
+	return LevelWarning, false
+}
+
+// LevelOfSeverity returns the level of a dashboard alert severity.
+func LevelOfSeverity(severity int) Level {
+	switch {
+	case severity <= 1:
+		return LevelInfo
+	case severity == 2:
+		return LevelWarning
+	case severity == 3:
+		return LevelError
+	default:
+		return LevelCritical
+	}
+}
+
+// RegisterChannel makes a channel available to the users selecting it.
+// The email channel is selected by the email_notifications preference,
+// the other channels by the im_type preference.
+func RegisterChannel(name string, c Channel) {
+	channelsMu.Lock()
+	defer channelsMu.Unlock()
+	channels[name] = c
+}
+
+func getChannel(name string) (Channel, bool) {
+	channelsMu.RLock()
+	defer channelsMu.RUnlock()
+	c, ok := channels[name]
+	return c, ok
+}
+
+func isTrue(s string) bool {
+	return s == "T"
+}
+
+// routes returns the channels to send the message to the user through,
+// according to the user preferences.
+func routes(u tables.User, m Message) []route {
+	l := make([]route, 0)
+	if isTrue(u.EmailNotifications) && u.Email != "" {
+		if threshold, _ := ParseLevel(u.EmailLogLevel); m.Level >= threshold {
+			l = append(l, route{Channel: "email"})
+		}
+	}
+	if isTrue(u.IMNotifications) && u.IMType != "" && u.IMType != "email" {
+		if threshold, _ := ParseLevel(u.IMLogLevel); m.Level >= threshold {
+			l = append(l, route{
+				Channel: u.IMType,
+				Delay:   time.Duration(u.IMNotificationsDelay) * time.Minute,
+			})
+		}
+	}
+	return l
+}
+
+// recipient returns the recipient of the messages sent to the user.
+func recipient(u tables.User) Recipient {
+	return Recipient{
+		UserID:     u.ID,
+		Username:   u.Username,
+		Email:      u.Email,
+		IMUsername: u.IMUsername,
+	}
+}
+
+// Subject returns the subject of a batch of messages.
+func Subject(msgs []Message) string {
+	if len(msgs) == 1 {
+		return msgs[0].Subject
+	}
+	max := LevelDebug
+	for _, m := range msgs {
+		if m.Level > max {
+			max = m.Level
+		}
+	}
+	return fmt.Sprintf("%d alerts, up to %s", len(msgs), max)
+}
+
+// Text returns the text of a batch of messages, one line per message,
+// most severe first.
+func Text(msgs []Message) string {
+	l := make([]Message, len(msgs))
+	copy(l, msgs)
+	sort.SliceStable(l, func(i, j int) bool { return l[i].Level > l[j].Level })
+	var b strings.Builder
+	for _, m := range l {
+		fmt.Fprintf(&b, "[%s] %s: %s\n", m.Level, m.Subject, m.Text)
+	}
+	return b.String()
+}
@@ -0,0 +1,309 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]struct {
+		level Level
+		ok    bool
+	}{
+		"":         {level: LevelWarning, ok: true},
+		"debug":    {level: LevelDebug, ok: true},
+		"error":    {level: LevelError, ok: true},
+		"critical": {level: LevelCritical, ok: true},
+		"bogus":    {level: LevelWarning, ok: false},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		level, ok := ParseLevel(testName)
+		assert.Equal(t, test.level, level)
+		assert.Equal(t, test.ok, ok)
+	}
+}
+
+func TestLevelOfSeverity(t *testing.T) {
+	assert.Equal(t, LevelInfo, LevelOfSeverity(0))
+	assert.Equal(t, LevelInfo, LevelOfSeverity(1))
+	assert.Equal(t, LevelWarning, LevelOfSeverity(2))
+	assert.Equal(t, LevelError, LevelOfSeverity(3))
+	assert.Equal(t, LevelCritical, LevelOfSeverity(4))
+}
+
+func TestRoutes(t *testing.T) {
+	tests := map[string]struct {
+		user     tables.User
+		level    Level
+		expected []route
+	}{
+		"no notification": {
+			user:     tables.User{Email: "a@b.c", EmailLogLevel: "debug", IMType: "slack", IMLogLevel: "debug"},
+			level:    LevelCritical,
+			expected: []route{},
+		},
+		"email above threshold": {
+			user:     tables.User{Email: "a@b.c", EmailNotifications: "T", EmailLogLevel: "warning"},
+			level:    LevelError,
+			expected: []route{{Channel: "email"}},
+		},
+		"email below threshold": {
+			user:     tables.User{Email: "a@b.c", EmailNotifications: "T", EmailLogLevel: "error"},
+			level:    LevelWarning,
+			expected: []route{},
+		},
+		"email without address": {
+			user:     tables.User{EmailNotifications: "T", EmailLogLevel: "debug"},
+			level:    LevelError,
+			expected: []route{},
+		},
+		"email and delayed im": {
+			user: tables.User{
+				Email:                "a@b.c",
+				EmailNotifications:   "T",
+				EmailLogLevel:        "info",
+				IMNotifications:      "T",
+				IMType:               "slack",
+				IMLogLevel:           "info",
+				IMNotificationsDelay: 5,
+			},
+			level:    LevelInfo,
+			expected: []route{{Channel: "email"}, {Channel: "slack", Delay: 5 * time.Minute}},
+		},
+		"im default threshold": {
+			user:     tables.User{IMNotifications: "T", IMType: "webhook"},
+			level:    LevelInfo,
+			expected: []route{},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, routes(test.user, Message{Level: test.level}))
+	}
+}
+
+func TestNotified(t *testing.T) {
+	tests := map[string]struct {
+		event    events.Event
+		expected bool
+	}{
+		"dashboard create": {
+			event:    events.Event{Kind: events.Create, Table: "dashboard"},
+			expected: true,
+		},
+		"dashboard severity update": {
+			event:    events.Event{Kind: events.Update, Table: "dashboard", Props: []string{"dash_severity"}},
+			expected: true,
+		},
+		"dashboard acked update": {
+			event:    events.Event{Kind: events.Update, Table: "dashboard", Props: []string{"acked"}},
+			expected: true,
+		},
+		"dashboard other update": {
+			event:    events.Event{Kind: events.Update, Table: "dashboard", Props: []string{"dash_dict"}},
+			expected: false,
+		},
+		"dashboard delete": {
+			event:    events.Event{Kind: events.Delete, Table: "dashboard"},
+			expected: false,
+		},
+		"other table create": {
+			event:    events.Event{Kind: events.Create, Table: "nodes"},
+			expected: false,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, notified(test.event))
+	}
+}
+
+func TestSubjectAndText(t *testing.T) {
+	msgs := []Message{
+		{Level: LevelWarning, Subject: "node1: node stale", Text: "node last communicated on 2022-06-13"},
+		{Level: LevelError, Subject: "svc1: service status", Text: "service status is down"},
+	}
+	assert.Equal(t, "node1: node stale", Subject(msgs[:1]))
+	assert.Equal(t, "2 alerts, up to error", Subject(msgs))
+	assert.Equal(t, "[error] svc1: service status: service status is down\n[warning] node1: node stale: node last communicated on 2022-06-13\n", Text(msgs))
+
+	b, err := json.Marshal(msgs[0])
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"level":"warning"`)
+}
+
+func TestBatcher(t *testing.T) {
+	type sent struct {
+		channel string
+		msgs    []Message
+	}
+	c := make(chan sent, 10)
+	b := newBatcher(func(channel string, to Recipient, msgs []Message) {
+		c <- sent{channel: channel, msgs: msgs}
+	})
+	to := Recipient{UserID: 1}
+	b.Add("email", to, Message{Subject: "a"}, 0)
+	select {
+	case s := <-c:
+		assert.Equal(t, "email", s.channel)
+		assert.Len(t, s.msgs, 1)
+	case <-time.After(time.Second):
+		t.Fatal("immediate message not sent")
+	}
+
+	b.Add("slack", to, Message{Subject: "b"}, 50*time.Millisecond)
+	b.Add("slack", to, Message{Subject: "c"}, 50*time.Millisecond)
+	b.Add("slack", Recipient{UserID: 2}, Message{Subject: "d"}, 50*time.Millisecond)
+	got := make(map[int]int)
+	for i := 0; i < 2; i++ {
+		select {
+		case s := <-c:
+			assert.Equal(t, "slack", s.channel)
+			got[len(s.msgs)]++
+		case <-time.After(time.Second):
+			t.Fatal("batch not sent")
+		}
+	}
+	assert.Equal(t, map[int]int{1: 1, 2: 1}, got)
+}
+
+// fakeSMTP accepts a single SMTP session and sends the received mail data
+// to the returned channel.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := make(chan string, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+					c <- data.String()
+				} else {
+					data.WriteString(line)
+				}
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), c
+}
+
+func TestSMTP(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	c := SMTP{Addr: addr, From: "collector@example.com"}
+	msgs := []Message{{Level: LevelError, Subject: "svc1: service status", Text: "service status is down"}}
+	err := c.Send(context.Background(), Recipient{Username: "u1", Email: "u1@example.com"}, msgs)
+	assert.Nil(t, err)
+	select {
+	case data := <-received:
+		assert.Contains(t, data, "From: collector@example.com\r\n")
+		assert.Contains(t, data, "To: u1@example.com\r\n")
+		assert.Contains(t, data, "Subject: svc1: service status\r\n")
+		assert.Contains(t, data, "\r\n\r\n[error] svc1: service status: service status is down\r\n")
+	case <-time.After(time.Second):
+		t.Fatal("mail not received")
+	}
+
+	err = c.Send(context.Background(), Recipient{Username: "u2"}, msgs)
+	assert.NotNil(t, err)
+}
+
+func TestSMTPTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		// accept, but never greet
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		time.Sleep(2 * time.Second)
+	}()
+	c := SMTP{Addr: ln.Addr().String(), From: "collector@example.com"}
+	msgs := []Message{{Level: LevelError, Subject: "svc1: service status"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	err = c.Send(ctx, Recipient{Username: "u1", Email: "u1@example.com"}, msgs)
+	assert.NotNil(t, err)
+	assert.Less(t, int64(time.Since(begin)), int64(time.Second))
+}
+
+func TestWebhookAndSlack(t *testing.T) {
+	var body []byte
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	to := Recipient{UserID: 1, Username: "u1", IMUsername: "u1"}
+	msgs := []Message{{Level: LevelWarning, Subject: "node1: node frozen", Text: "node is frozen"}}
+
+	err := Slack{URL: srv.URL, Client: srv.Client()}.Send(context.Background(), to, msgs)
+	assert.Nil(t, err)
+	slack := slackPayload{}
+	assert.Nil(t, json.Unmarshal(body, &slack))
+	assert.Equal(t, "@u1", slack.Channel)
+	assert.Equal(t, "*node1: node frozen*\n[warning] node1: node frozen: node is frozen", slack.Text)
+
+	err = Webhook{URL: srv.URL, Client: srv.Client()}.Send(context.Background(), to, msgs)
+	assert.Nil(t, err)
+	payload := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "node1: node frozen", payload["subject"])
+	assert.Len(t, payload["messages"], 1)
+
+	status = http.StatusInternalServerError
+	err = Webhook{URL: srv.URL, Client: srv.Client()}.Send(context.Background(), to, msgs)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+type (
+	// Webhook is the generic webhook channel. The messages are posted
+	// to the url as a json document, with the recipient.
+	Webhook struct {
+		URL    string
+		Client *http.Client
+	}
+
+	// Slack is the Slack-compatible incoming webhook channel. The
+	// messages are posted to the url as a single text, to the recipient
+	// im_username if set.
+	Slack struct {
+		URL    string
+		Client *http.Client
+	}
+
+	webhookPayload struct {
+		Recipient Recipient `json:"recipient"`
+		Subject   string    `json:"subject"`
+		Messages  []Message `json:"messages"`
+	}
+
+	slackPayload struct {
+		Channel string `json:"channel,omitempty"`
+		Text    string `json:"text"`
+	}
+)
+
+// Send posts the messages to the webhook url.
+func (t Webhook) Send(ctx context.Context, to Recipient, msgs []Message) error {
+	return postJSON(ctx, t.Client, t.URL, webhookPayload{
+		Recipient: to,
+		Subject:   Subject(msgs),
+		Messages:  msgs,
+	})
+}
+
+// Send posts the messages to the slack webhook url.
+func (t Slack) Send(ctx context.Context, to Recipient, msgs []Message) error {
+	p := slackPayload{
+		Text: fmt.Sprintf("*%s*\n%s", Subject(msgs), strings.TrimSuffix(Text(msgs), "\n")),
+	}
+	if to.IMUsername != "" {
+		p.Channel = "@" + strings.TrimPrefix(to.IMUsername, "@")
+	}
+	return postJSON(ctx, t.Client, t.URL, p)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "opensvc-collector-api")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
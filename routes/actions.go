@@ -0,0 +1,414 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"gorm.io/gorm"
+)
+
+// actionsFailedGroups are the selections and groupings of the failed
+// actions counts, indexed by the by query parameter value.
+var actionsFailedGroups = map[string]struct {
+	table  string
+	props  []string
+	groups []string
+}{
+	"app": {
+		table:  "services",
+		props:  []string{"services.svc_app AS app"},
+		groups: []string{"services.svc_app"},
+	},
+	"service": {
+		table:  "services",
+		props:  []string{"svcactions.svc_id AS svc_id", "services.svcname AS svcname"},
+		groups: []string{"svcactions.svc_id", "services.svcname"},
+	},
+	"node": {
+		table:  "nodes",
+		props:  []string{"svcactions.node_id AS node_id", "nodes.nodename AS nodename"},
+		groups: []string{"svcactions.node_id", "nodes.nodename"},
+	},
+}
+
+//
+// GetActions     godoc
+// @Summary      List service actions
+// @Description  List the actions run by the nodes on the services of the apps published to the user.
+// @Description  The status is empty while the action is running, then ok, warn or err.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example status=err"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /actions  [get]
+//
+func GetActions(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("svcactions").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeActions     godoc
+// @Summary      List the service actions run by a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example status=err"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/actions  [get]
+//
+func GetNodeActions(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("svcactions").Request()
+	rq.Where("svcactions.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetServiceActions     godoc
+// @Summary      List the actions run on a service
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example status=err"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /services/{id}/actions  [get]
+//
+func GetServiceActions(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	rq := db.Tab("svcactions").Request()
+	rq.Where("svcactions.svc_id = ?", svc.SvcID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetActionsFailed     godoc
+// @Summary      Count the failed service actions
+// @Description  Count the actions ended with the err status since a duration ago, on the services of the apps published to the user, per app, service or node.
+// @Description  The entries have the failed count and the last failed action begin date, and the app, the svc_id and svcname, or the node_id and nodename, depending on the by parameter.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Accept       json
+// @Produce      json
+// @Param        since    query     string    false  "the duration to count the failed actions over (default 24h)"
+// @Param        by       query     string    false  "the grouping of the counts (default app)"  Enums(app, service, node)
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example action=start"
+// @Success      200      {array}   object
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Router       /actions/failed  [get]
+//
+func GetActionsFailed(w http.ResponseWriter, r *http.Request) {
+	since := 24 * time.Hour
+	if s := r.URL.Query().Get("since"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			http.Error(w, fmt.Sprintf("%s: invalid since duration %s", http.StatusText(400), s), 400)
+			return
+		}
+		since = d
+	}
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		by = "app"
+	}
+	g, ok := actionsFailedGroups[by]
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s: invalid by %s", http.StatusText(400), by), 400)
+		return
+	}
+	rq := db.Tab("svcactions").Request(db.TableRequestWithPaging(false))
+	rq.AutoJoin(g.table)
+	rq.Where("svcactions.status = ? AND svcactions.begin >= ?", "err", time.Now().Add(-since))
+	// the ACL and filters joins can duplicate the actions, so count the
+	// distinct ones
+	props := append([]string{}, g.props...)
+	props = append(props, "COUNT(DISTINCT svcactions.id) AS failed", "MAX(svcactions.begin) AS last")
+	tx := rq.TX(r).Select(props)
+	for _, s := range g.groups {
+		tx = tx.Group(s)
+	}
+	data := make([]map[string]interface{}, 0)
+	if err := tx.Order("failed DESC").Find(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
+
+//
+// PostActionsBegin     godoc
+// @Summary      Begin service actions
+// @Description  Requires node authentication, from a node running the services.
+// @Description  The actions are logged with an empty status and the current begin date. The node must end them with their index in database.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Accept       json
+// @Produce      json
+// @Param        actions  body      []tables.SvcAction  true  "list of actions to begin"
+// @Success      200      {array}   tables.SvcAction
+// @Failure      403      {string}  string  "Forbidden"
+// @Failure      422      {object}  validationErrorResponse
+// @Failure      500      {string}  string  "Internal Server Error"
+// @Router       /actions/begin  [post]
+//
+func PostActionsBegin(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data, entries, ok := decodeActions(w, r)
+	if !ok {
+		return
+	}
+	errs := make(db.FieldErrors, 0)
+	now := time.Now()
+	for i, entry := range entries {
+		l := db.Tab("svcactions").Validate(entry, db.ValidateWithCreate(true), db.ValidateWithReadOnly("status"))
+		errs = append(errs, l.WithIndex(i)...)
+		if data[i].SvcID != "" {
+			svcID, ok, err := nodeServiceID(nodeID, data[i].SvcID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+				return
+			}
+			if !ok {
+				errs = append(errs, db.FieldError{Index: i, Field: "svc_id", Error: "not a service of the node"})
+			}
+			data[i].SvcID = svcID
+		}
+		data[i].NodeID = nodeID
+		data[i].Begin = now
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if len(data) == 0 {
+		jsonEncode(w, data)
+		return
+	}
+	if err := db.DB().Create(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	ids := make([]uint, len(data))
+	for i, a := range data {
+		ids[i] = a.ID
+	}
+	publishUpserted("svcactions", nil, ids)
+	jsonEncode(w, data)
+}
+
+//
+// PostActionsEnd     godoc
+// @Summary      End service actions
+// @Description  Requires node authentication, from the node that began the actions.
+// @Description  The entries are the index in database of the actions, their ok, warn or err status, and their optional status_log. The end date and the duration of the actions are set.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         actions
+// @Accept       json
+// @Produce      json
+// @Param        actions  body      []tables.SvcAction  true  "list of actions to end"
+// @Success      200      {array}   tables.SvcAction
+// @Failure      403      {string}  string  "Forbidden"
+// @Failure      422      {object}  validationErrorResponse
+// @Failure      500      {string}  string  "Internal Server Error"
+// @Router       /actions/end  [post]
+//
+func PostActionsEnd(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data, entries, ok := decodeActions(w, r)
+	if !ok {
+		return
+	}
+	errs := make(db.FieldErrors, 0)
+	ids := make([]uint, 0)
+	for i, entry := range entries {
+		l := db.Tab("svcactions").Validate(entry,
+			db.ValidateWithKeys("id"),
+			db.ValidateWithReadOnly("svc_id", "action", "rid", "subset", "pid", "sid", "cron", "version"),
+		)
+		errs = append(errs, l.WithIndex(i)...)
+		for _, k := range []string{"id", "status"} {
+			if _, ok := entry[k]; !ok {
+				errs = append(errs, db.FieldError{Index: i, Field: k, Error: "required property"})
+			}
+		}
+		ids = append(ids, data[i].ID)
+	}
+	running := make(map[uint]tables.SvcAction)
+	if len(errs) == 0 {
+		l := make([]tables.SvcAction, 0)
+		if err := db.DB().Where("id IN ? AND node_id = ?", ids, nodeID).Find(&l).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select: %s", err), 500)
+			return
+		}
+		for _, a := range l {
+			running[a.ID] = a
+		}
+		for i, a := range data {
+			if current, ok := running[a.ID]; !ok {
+				errs = append(errs, db.FieldError{Index: i, Field: "id", Error: "not an action of the node"})
+			} else if current.End != nil {
+				errs = append(errs, db.FieldError{Index: i, Field: "id", Error: "action already ended"})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	now := time.Now()
+	ended := make([]tables.SvcAction, len(data))
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		for i, a := range data {
+			current := running[a.ID]
+			current.Status = a.Status
+			current.StatusLog = a.StatusLog
+			current.End = &now
+			current.Time = int(now.Sub(current.Begin).Seconds())
+			err := tx.Model(&tables.SvcAction{}).Where("id = ?", current.ID).Updates(map[string]interface{}{
+				"status":     current.Status,
+				"status_log": current.StatusLog,
+				"end":        current.End,
+				"time":       current.Time,
+			}).Error
+			if err != nil {
+				return fmt.Errorf("update action %d: %w", current.ID, err)
+			}
+			ended[i] = current
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	for _, a := range ended {
+		events.Publish(events.Update, "svcactions", a.ID, "status", "status_log", "end", "time")
+	}
+	jsonEncode(w, ended)
+}
+
+// decodeActions decodes the request body, a single action or a list of
+// actions, as actions and as entries to validate. It responds the error
+// and returns false on failure.
+func decodeActions(w http.ResponseWriter, r *http.Request) ([]tables.SvcAction, []map[string]interface{}, bool) {
+	data := make([]tables.SvcAction, 0)
+	action := tables.SvcAction{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return nil, nil, false
+	}
+	if err := json.Unmarshal(body, &action); err == nil {
+		// single entry
+		data = append(data, action)
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return nil, nil, false
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return nil, nil, false
+	}
+	return data, entries, true
+}
@@ -6,8 +6,10 @@ import (
 	"io/ioutil"
 	"net/http"
 
+	"github.com/opensvc/collector-api/authuser"
 	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
+	"github.com/shaj13/go-guardian/v2/auth"
 	"gorm.io/gorm/clause"
 )
 
@@ -20,35 +22,40 @@ import (
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
 // @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
 // @Failure      500    {string}  string  "Internal Server Error"
 // @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param        limit    query     int       false  "number of objets to include in response"
 // @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router       /users  [get]
 //
 func GetUsers(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("auth_user").Request()
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
 // PostUsers	godoc
 // @Summary      Create or update users
-// @Description  The user must be in the UserManager privilege group to modify tiers users properties.
-// @Description  The user must be in the SelfManager privilege group to modify its user properties.
+// @Description  The user must be in the UserManager privilege group to create users, to modify tiers users properties, and to modify the credentials, quotas and lock_filter properties.
+// @Description  Other users can only update their own entry, identified by id, with their names, phone and notification preferences.
 // @Security     BasicAuth
 // @Security     BearerAuth
 // @Tags         users
@@ -56,6 +63,8 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        users  body      []tables.User  true  "list of users to create or update"
 // @Success      200    {array}   tables.User
+// @Failure      401    {string}  string  "missing UserManager privilege"
+// @Failure      422    {object}  validationErrorResponse
 // @Failure      500      {string}  string    "Internal Server Error"
 // @Router       /users  [post]
 //
@@ -75,11 +84,62 @@ func PostUsers(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprint(err), 500)
 		return
 	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if !authuser.HasPrivilege(auth.User(r), "UserManager") {
+		postSelfUser(w, r, users, entries)
+		return
+	}
+	if errs := validateEntries(db.Tab("auth_user"), entries, "id"); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
 	for _, user := range users {
+		before := user.ID
 		tx := db.DB().Clauses(clause.OnConflict{UpdateAll: true})
 		if err := tx.Create(&user).Error; err != nil {
 			http.Error(w, fmt.Sprint(err), 500)
 			return
 		}
+		publishUpserted("auth_user", []uint{before}, []uint{user.ID})
+	}
+}
+
+// postSelfUser updates the entry of a user without the UserManager
+// privilege. The entries must identify the user entry by id, and only
+// modify the self-writable properties, because the upsert would
+// otherwise reset the credentials, quotas and lock_filter.
+func postSelfUser(w http.ResponseWriter, r *http.Request, users []tables.User, entries []map[string]interface{}) {
+	userID := auth.User(r).GetID()
+	errs := make(db.FieldErrors, 0)
+	for i, entry := range entries {
+		l := db.Tab("auth_user").Validate(entry, db.ValidateWithKeys("id"), db.ValidateWithWritable(userSelfWritable...))
+		errs = append(errs, l.WithIndex(i)...)
+		if fmt.Sprint(users[i].ID) != userID {
+			errs = append(errs, db.FieldError{Index: i, Field: "id", Error: "not the user id"})
+		}
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	for i, entry := range entries {
+		delete(entry, "id")
+		if len(entry) == 0 {
+			continue
+		}
+		if err := db.Tab("auth_user").Update(users[i].ID, entry); err != nil {
+			http.Error(w, fmt.Sprintf("update: %s", err), 500)
+			return
+		}
+	}
+	current := make([]tables.User, 0)
+	if err := db.DB().Where("id = ?", userID).Find(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
 	}
+	jsonEncode(w, current)
 }
@@ -0,0 +1,150 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"gorm.io/gorm/clause"
+)
+
+type userFiltersetRequest struct {
+	FiltersetID uint `json:"filterset_id"`
+}
+
+//
+// GetUserFilterset     godoc
+// @Summary      Show the filterset assigned to a user
+// @Description  If the user lock_filter is "T", all the user table requests are limited to the entries matching the filters of this filterset.
+// @Description  Users can see their own filterset. The UserManager privilege is required to see other users' filterset.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Filterset
+// @Failure      401  {string}  string  "missing UserManager privilege"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or email, or login name"
+// @Router       /users/{id}/filterset  [get]
+//
+func GetUserFilterset(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	users := tables.UserFromCtx(r)
+	if len(users) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	u := users[0]
+	if user.GetID() != fmt.Sprint(u.ID) && !authuser.HasPrivilege(user, "UserManager") {
+		authuser.PrivError(w, "UserManager")
+		return
+	}
+	data, err := tables.GetUserFilterset(u.ID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select filterset: %s", err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
+
+//
+// PutUserFilterset     godoc
+// @Summary      Assign a filterset to a user
+// @Description  Requires the UserManager privilege.
+// @Description  The filterset is enforced on the user table requests if the user lock_filter is "T".
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string                true  "the index of the entry in database, or email, or login name"
+// @Param        filterset  body      userFiltersetRequest  true  "the filterset to assign"
+// @Success      200        {object}  tables.FiltersetUser
+// @Failure      401        {string}  string  "missing UserManager privilege"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      422        {object}  validationErrorResponse
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Router       /users/{id}/filterset  [put]
+//
+func PutUserFilterset(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.HasPrivilege(user, "UserManager") {
+		authuser.PrivError(w, "UserManager")
+		return
+	}
+	users := tables.UserFromCtx(r)
+	if len(users) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	req := userFiltersetRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	var i int64
+	if err := db.DB().Table("filtersets").Where("filtersets.id = ?", req.FiltersetID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select filterset: %s", err), 500)
+		return
+	}
+	if i == 0 {
+		validationError(w, db.FieldErrors{{Field: "filterset_id", Error: "unknown filterset"}})
+		return
+	}
+	entry := tables.FiltersetUser{
+		UserID:      users[0].ID,
+		FiltersetID: req.FiltersetID,
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"filterset_id", "updated_at"}),
+	})
+	if err := tx.Create(&entry).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	events.Publish(events.Update, "auth_user", users[0].ID, "filterset")
+	jsonEncode(w, entry)
+}
+
+//
+// DelUserFilterset     godoc
+// @Summary      Unassign the filterset of a user
+// @Description  Requires the UserManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing UserManager privilege"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or email, or login name"
+// @Router       /users/{id}/filterset  [delete]
+//
+func DelUserFilterset(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.HasPrivilege(user, "UserManager") {
+		authuser.PrivError(w, "UserManager")
+		return
+	}
+	users := tables.UserFromCtx(r)
+	if len(users) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	if err := db.DB().Where("user_id = ?", users[0].ID).Delete(&[]tables.FiltersetUser{}).Error; err != nil {
+		http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+		return
+	}
+	events.Publish(events.Update, "auth_user", users[0].ID, "filterset")
+	w.WriteHeader(204)
+}
@@ -7,9 +7,26 @@ import (
 	"github.com/opensvc/collector-api/authuser"
 	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/funcopt"
 	"github.com/shaj13/go-guardian/v2/auth"
 )
 
+// userSelfWritable are the properties users without the UserManager
+// privilege can modify in their own entry.
+var userSelfWritable = []string{
+	"first_name",
+	"last_name",
+	"phone_work",
+	"email_notifications",
+	"email_notifications_delay",
+	"email_log_level",
+	"im_notifications",
+	"im_notifications_delay",
+	"im_type",
+	"im_username",
+	"im_log_level",
+}
+
 //
 // DelUser     godoc
 // @Summary      Delete a user
@@ -22,8 +39,10 @@ import (
 // @Success      200  {array}   tables.User
 // @Success      204  {string}  string  "No Content"
 // @Failure      403  {string}  string  "Forbidden"
+// @Failure      412  {string}  string  "Precondition Failed"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or email, or login name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
 // @Router       /users/{id}  [delete]
 //
 func DelUser(w http.ResponseWriter, r *http.Request) {
@@ -37,8 +56,15 @@ func DelUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(204), 204)
 		return
 	}
+	if !checkIfMatch(w, r, users) {
+		return
+	}
 	usr := users[0]
-	db.DB().Table("auth_user").Where("auth_user.id = ?", usr.ID).Delete(&tables.User{})
+	if err := deleteIfMatch(r, db.DB().Table("auth_user"), "auth_user", usr.ID, usr.UpdatedAt, &tables.User{}); err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("auth_user", usr.ID)
 	jsonEncode(w, []tables.User{usr})
 }
 
@@ -52,9 +78,11 @@ func DelUser(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Success      200  {array}   tables.User
+// @Success      304  {string}  string  "Not Modified"
 // @Failure      404  {string}  string  "Not Found"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
 // @Router       /users/{id}  [get]
 //
 func GetUser(w http.ResponseWriter, r *http.Request) {
@@ -63,7 +91,7 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	jsonEncode(w, users)
+	jsonEncodeWithETag(w, r, users)
 }
 
 //
@@ -76,15 +104,28 @@ func GetUser(w http.ResponseWriter, r *http.Request) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
 // @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
 // @Failure      500    {string}  string  "Internal Server Error"
 // @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param        limit    query     int       false  "number of objets to include in response"
 // @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router       /users/{id}/groups  [get]
 //
 func GetUserGroups(w http.ResponseWriter, r *http.Request) {
@@ -99,15 +140,7 @@ func GetUserGroups(w http.ResponseWriter, r *http.Request) {
 	)
 	rq.AutoJoin("auth_membership")
 	rq.Where("auth_membership.user_id = ?", u.ID)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -120,15 +153,28 @@ func GetUserGroups(w http.ResponseWriter, r *http.Request) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
 // @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
 // @Failure      500    {string}  string  "Internal Server Error"
 // @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param        limit    query     int       false  "number of objets to include in response"
 // @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router       /users/{id}/apps/publication  [get]
 //
 func GetUserAppsPublication(w http.ResponseWriter, r *http.Request) {
@@ -146,15 +192,7 @@ func GetUserAppsPublication(w http.ResponseWriter, r *http.Request) {
 		rq.TX(r).Joins("JOIN auth_membership ON apps_publications.group_id = auth_membership.group_id")
 		rq.Where("auth_membership.user_id = ?", u.ID)
 	}
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -167,15 +205,28 @@ func GetUserAppsPublication(w http.ResponseWriter, r *http.Request) {
 // @Tags         users
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
 // @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
 // @Failure      500    {string}  string  "Internal Server Error"
 // @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param        limit    query     int       false  "number of objets to include in response"
 // @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router       /users/{id}/apps/responsible  [get]
 //
 func GetUserAppsResponsible(w http.ResponseWriter, r *http.Request) {
@@ -194,13 +245,64 @@ func GetUserAppsResponsible(w http.ResponseWriter, r *http.Request) {
 		rq.TX(r).Joins("JOIN auth_membership ON apps_responsibles.group_id = auth_membership.group_id")
 		rq.Where("auth_membership.user_id = ?", u.ID)
 	}
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PatchUser     godoc
+// @Summary      Patch a user
+// @Description  The user must be in the UserManager privilege group to modify tiers users properties.
+// @Description  The user must be in the UserManager privilege group to modify the credentials, quotas and lock_filter properties. Other users can only modify their names, phone and notification preferences.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         users
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or email, or login name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.User
+// @Failure      401       {string}  string  "missing UserManager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /users/{id}  [patch]
+//
+func PatchUser(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	users := tables.UserFromCtx(r)
+	if len(users) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := users[0]
+	if user.GetID() != fmt.Sprint(current.ID) && !authuser.HasPrivilege(user, "UserManager") {
+		authuser.PrivError(w, "UserManager")
+		return
+	}
+	if !checkIfMatch(w, r, users) {
+		return
+	}
+	opts := make([]funcopt.O, 0)
+	if !authuser.HasPrivilege(user, "UserManager") {
+		// users can not change their credentials nor unlock their own
+		// filterset
+		opts = append(opts, db.ValidateWithWritable(userSelfWritable...))
+	}
+	if !patchEntry(w, r, "auth_user", current, current.ID, opts...) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
 		return
 	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	if err := jsonEncodeWithETag(w, r, []tables.User{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
 	}
 }
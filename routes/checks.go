@@ -0,0 +1,266 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/opensvc/collector-api/checks"
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetChecks     godoc
+// @Summary      List checks
+// @Description  List the last check values pushed by the nodes of the apps published to the user, and their evaluation.
+// @Description  The chk_err is 1 if the chk_value is out of the [chk_low, chk_high] thresholds, selected by chk_threshold_provider.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example chk_err=1"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /checks  [get]
+//
+func GetChecks(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("checks_live").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeChecks     godoc
+// @Summary      List the checks of a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example chk_err=1"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/checks  [get]
+//
+func GetNodeChecks(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("checks_live").Request()
+	rq.Where("checks_live.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostChecks     godoc
+// @Summary      Push check values
+// @Description  Requires node authentication.
+// @Description  The values replace the last values of the node for the same service, type and instance, and are evaluated against the thresholds.
+// @Description  The checks of the node with one of the pushed types, but not pushed, are removed.
+// @Description  The svc_id is the svc_id or svcname of a service running on the node, or empty for the node itself.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Accept       json
+// @Produce      json
+// @Param        checks  body      []tables.Check  true  "list of check values"
+// @Success      200     {array}   tables.Check
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      422     {object}  validationErrorResponse
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /checks  [post]
+//
+func PostChecks(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.Check, 0)
+	check := tables.Check{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &check); err == nil {
+		// single entry
+		data = append(data, check)
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("checks_live"), entries)
+	for i := range data {
+		if data[i].SvcID != "" {
+			svcID, ok, err := nodeServiceID(nodeID, data[i].SvcID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+				return
+			}
+			if !ok {
+				errs = append(errs, db.FieldError{Index: i, Field: "svc_id", Error: "not a service of the node"})
+			}
+			data[i].SvcID = svcID
+		}
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if len(data) == 0 {
+		jsonEncode(w, data)
+		return
+	}
+	if err := checks.Evaluate(data); err != nil {
+		http.Error(w, fmt.Sprintf("evaluate: %s", err), 500)
+		return
+	}
+	existing := make([]tables.Check, 0)
+	if err := db.DB().Where("node_id = ? AND chk_type IN ?", nodeID, checks.Types(data)).Find(&existing).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return
+	}
+	existingIDs := make(map[string]uint)
+	for _, c := range existing {
+		existingIDs[checkKey(c)] = c.ID
+	}
+	before := make([]uint, len(data))
+	pushed := make(map[string]bool)
+	for i, c := range data {
+		before[i] = existingIDs[checkKey(c)]
+		pushed[checkKey(c)] = true
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"chk_value", "chk_low", "chk_high", "chk_threshold_provider", "chk_err", "updated_at"}),
+	})
+	if err := tx.Create(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
+		return
+	}
+	// the ids of the updated entries are not returned by the upsert
+	after := make([]uint, len(data))
+	for i := range data {
+		entry := tables.Check{}
+		err := db.DB().
+			Where("node_id = ? AND svc_id = ? AND chk_type = ? AND chk_instance = ?", data[i].NodeID, data[i].SvcID, data[i].Type, data[i].Instance).
+			Take(&entry).Error
+		if err != nil {
+			http.Error(w, fmt.Sprintf("select after upsert: %s", err), 500)
+			return
+		}
+		data[i] = entry
+		after[i] = entry.ID
+	}
+	publishUpserted("checks_live", before, after)
+	removed := make([]uint, 0)
+	changed := append([]tables.Check{}, data...)
+	for _, c := range existing {
+		if !pushed[checkKey(c)] {
+			removed = append(removed, c.ID)
+			changed = append(changed, c)
+		}
+	}
+	if len(removed) > 0 {
+		if err := db.DB().Where("id IN ?", removed).Delete(&tables.Check{}).Error; err != nil {
+			http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+			return
+		}
+		publishDeleted("checks_live", removed...)
+	}
+	jsonEncode(w, data)
+	refreshChecksAlerts(r, changed)
+}
+
+// checkKey returns the identity of the check value among the values
+// pushed by its node.
+func checkKey(c tables.Check) string {
+	return c.SvcID + "/" + c.Type + "/" + c.Instance
+}
+
+// refreshChecksAlerts enqueues the refresh of the dashboard alerts of the
+// nodes and services of the changed checks.
+func refreshChecksAlerts(r *http.Request, l []tables.Check) {
+	nodeIDs := make([]string, 0)
+	svcIDs := make([]string, 0)
+	for _, c := range l {
+		if c.SvcID == "" {
+			nodeIDs = append(nodeIDs, c.NodeID)
+		} else {
+			svcIDs = append(svcIDs, c.SvcID)
+		}
+	}
+	if len(nodeIDs) > 0 {
+		nodes := make([]tables.Node, 0)
+		if err := db.DB().Where("node_id IN ?", nodeIDs).Find(&nodes).Error; err != nil {
+			log.Printf("select nodes to refresh the alerts of: %s", err)
+		}
+		for _, n := range nodes {
+			refreshAlerts(r, dashboard.RefreshArgs{NodeID: n.ID})
+		}
+	}
+	if len(svcIDs) > 0 {
+		services := make([]tables.Service, 0)
+		if err := db.DB().Where("svc_id IN ?", svcIDs).Find(&services).Error; err != nil {
+			log.Printf("select services to refresh the alerts of: %s", err)
+		}
+		for _, svc := range services {
+			refreshAlerts(r, dashboard.RefreshArgs{ServiceID: svc.ID})
+		}
+	}
+}
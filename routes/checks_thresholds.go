@@ -0,0 +1,204 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/checks"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+// canWriteThreshold verifies the user can write a threshold of its scope,
+// and replaces the node, service and tag names by their ids.
+//
+// The node and service thresholds require the user to be responsible for
+// the app of the node and service. The tag and default thresholds require
+// the CheckManager privilege.
+func canWriteThreshold(w http.ResponseWriter, r *http.Request, t *tables.CheckThreshold) bool {
+	if t.NodeID == "" && t.SvcID == "" {
+		if !authuser.HasPrivilege(auth.User(r), "CheckManager") {
+			authuser.PrivError(w, "CheckManager")
+			return false
+		}
+	}
+	if t.NodeID != "" {
+		data := make([]tables.Node, 0)
+		if err := db.DB().Where("node_id = ? OR nodename = ?", t.NodeID, t.NodeID).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select node: %s", err), 500)
+			return false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: node %s", http.StatusText(404), t.NodeID), 404)
+			return false
+		}
+		if !canWriteEntry(w, r, "nodes", data[0].ID, data[0].App) {
+			return false
+		}
+		t.NodeID = data[0].NodeID
+	}
+	if t.SvcID != "" {
+		data := make([]tables.Service, 0)
+		if err := db.DB().Where("svc_id = ? OR svcname = ?", t.SvcID, t.SvcID).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+			return false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: service %s", http.StatusText(404), t.SvcID), 404)
+			return false
+		}
+		if !canWriteEntry(w, r, "services", data[0].ID, data[0].SvcApp) {
+			return false
+		}
+		t.SvcID = data[0].SvcID
+	}
+	if t.TagID != "" {
+		data := make([]tables.Tag, 0)
+		if err := db.DB().Where("tag_id = ? OR tag_name = ?", t.TagID, t.TagID).Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select tag: %s", err), 500)
+			return false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: tag %s", http.StatusText(404), t.TagID), 404)
+			return false
+		}
+		t.TagID = data[0].TagID
+	}
+	return true
+}
+
+// reevaluateChecks evaluates the checks of the types against their
+// thresholds, and refreshes the dashboard alerts of the nodes and services
+// of the checks whose evaluation changed. The errors are logged, as the
+// threshold change succeeded anyway.
+func reevaluateChecks(r *http.Request, types ...string) {
+	changed, err := checks.Reevaluate(types...)
+	if err != nil {
+		log.Printf("reevaluate %s checks: %s", types, err)
+	}
+	refreshChecksAlerts(r, changed)
+}
+
+//
+// GetChecksThresholds     godoc
+// @Summary      List checks thresholds
+// @Description  The chk_instance is a LIKE pattern of the check instances the threshold applies to.
+// @Description  The thresholds without node_id, svc_id and tag_id are the defaults.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /checks/thresholds  [get]
+//
+func GetChecksThresholds(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("checks_thresholds").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostChecksThresholds     godoc
+// @Summary      Create checks thresholds
+// @Description  The node_id, svc_id and tag_id are the uuid or name of the node, service and tag the threshold applies to.
+// @Description  A threshold of a node or service requires the user to be responsible for its app. A tag or default threshold requires the CheckManager privilege.
+// @Description  The checks of the thresholds types are reevaluated.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Accept       json
+// @Produce      json
+// @Param        thresholds  body      []tables.CheckThreshold  true  "list of thresholds to create"
+// @Success      200         {array}   tables.CheckThreshold
+// @Failure      401         {string}  string  "missing CheckManager privilege"
+// @Failure      403         {string}  string  "Forbidden"
+// @Failure      404         {string}  string  "Not Found"
+// @Failure      422         {object}  validationErrorResponse
+// @Failure      500         {string}  string  "Internal Server Error"
+// @Router       /checks/thresholds  [post]
+//
+func PostChecksThresholds(w http.ResponseWriter, r *http.Request) {
+	thresholds := make([]tables.CheckThreshold, 0)
+	threshold := tables.CheckThreshold{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &threshold); err == nil {
+		// single entry
+		thresholds = append(thresholds, threshold)
+	} else if err := json.Unmarshal(body, &thresholds); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("checks_thresholds"), entries)
+	for i := range thresholds {
+		if thresholds[i].Low > thresholds[i].High {
+			errs = append(errs, db.FieldError{Index: i, Field: "chk_low", Error: "must not be greater than chk_high"})
+		}
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	userID, _ := strconv.Atoi(auth.User(r).GetID())
+	for i := range thresholds {
+		if !canWriteThreshold(w, r, &thresholds[i]) {
+			return
+		}
+		if thresholds[i].Instance == "" {
+			thresholds[i].Instance = "%"
+		}
+		thresholds[i].UserID = uint(userID)
+	}
+	if err := db.DB().Create(&thresholds).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	types := make([]string, len(thresholds))
+	ids := make([]uint, len(thresholds))
+	for i, t := range thresholds {
+		types[i] = t.Type
+		ids[i] = t.ID
+	}
+	publishUpserted("checks_thresholds", nil, ids)
+	if err := jsonEncode(w, thresholds); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+	reevaluateChecks(r, types...)
+}
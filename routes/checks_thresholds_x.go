@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+//
+// DelChecksThreshold     godoc
+// @Summary      Delete a checks threshold
+// @Description  A threshold of a node or service requires the user to be responsible for its app. A tag or default threshold requires the CheckManager privilege.
+// @Description  The checks of the threshold type are reevaluated.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         checks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int     true  "the index of the threshold in database"
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing CheckManager privilege"
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /checks/thresholds/{id}  [delete]
+//
+func DelChecksThreshold(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIndex(chi.URLParam(r, "id"))
+	if !ok {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	data := make([]tables.CheckThreshold, 0)
+	if err := db.DB().Where("id = ?", id).Find(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return
+	}
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	threshold := data[0]
+	if !canWriteThreshold(w, r, &threshold) {
+		return
+	}
+	if err := db.DB().Delete(&tables.CheckThreshold{}, id).Error; err != nil {
+		http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+		return
+	}
+	publishDeleted("checks_thresholds", id)
+	w.WriteHeader(204)
+	reevaluateChecks(r, threshold.Type)
+}
@@ -0,0 +1,142 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetClusters     godoc
+// @Summary      List clusters
+// @Description  List the clusters with a member node of an app published to the user.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example cluster_name=prd%"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /clusters  [get]
+//
+func GetClusters(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("clusters").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostClusters     godoc
+// @Summary      Register the cluster of the node
+// @Description  Requires node authentication.
+// @Description  The cluster is created, or updated if a cluster with the same cluster_id exists, and the node becomes a member of the cluster.
+// @Description  An existing cluster can only be updated by one of its member nodes.
+// @Description  The cluster secret is never sent, only the version, date and author node_id of its last rotation.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Accept       json
+// @Produce      json
+// @Param        cluster  body      tables.Cluster  true  "the cluster of the node"
+// @Success      200      {array}   tables.Cluster
+// @Failure      403      {string}  string  "Forbidden"
+// @Failure      422      {object}  validationErrorResponse
+// @Failure      500      {string}  string  "Internal Server Error"
+// @Router       /clusters  [post]
+//
+func PostClusters(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	cluster := tables.Cluster{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &cluster); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("clusters"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	nodes := make([]tables.Node, 0)
+	if err := db.DB().Where("cluster_id = ?", cluster.ClusterID).Find(&nodes).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select member nodes: %s", err), 500)
+		return
+	}
+	isMember := len(nodes) == 0
+	for _, n := range nodes {
+		if n.NodeID == nodeID {
+			isMember = true
+		}
+	}
+	if !isMember {
+		http.Error(w, fmt.Sprintf("%s: node is not a member of cluster %s", http.StatusText(403), cluster.ClusterID), 403)
+		return
+	}
+	existing := make([]tables.Cluster, 0)
+	if err := db.DB().Where("cluster_id = ?", cluster.ClusterID).Find(&existing).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return
+	}
+	before := make([]uint, 1)
+	if len(existing) > 0 {
+		before[0] = existing[0].ID
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"cluster_name", "cluster_secret_version", "cluster_secret_rotated_at", "cluster_secret_rotated_by", "cluster_heartbeats", "updated_at"}),
+	})
+	if err := tx.Create(&cluster).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
+		return
+	}
+	// the id of the updated entry is not returned by the upsert
+	if err := db.DB().Where("cluster_id = ?", cluster.ClusterID).Take(&cluster).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after upsert: %s", err), 500)
+		return
+	}
+	publishUpserted("clusters", before, []uint{cluster.ID})
+	node := tables.Node{}
+	if err := db.DB().Where("node_id = ?", nodeID).Take(&node).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select node: %s", err), 500)
+		return
+	}
+	if node.ClusterID != cluster.ClusterID {
+		if err := db.Tab("nodes").Update(node.ID, map[string]interface{}{"cluster_id": cluster.ClusterID}); err != nil {
+			http.Error(w, fmt.Sprintf("update node cluster_id: %s", err), 500)
+			return
+		}
+	}
+	jsonEncode(w, []tables.Cluster{cluster})
+}
@@ -0,0 +1,256 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+// canWriteCluster verifies the user is responsible for the app of one of
+// the cluster member nodes. It responds 403 if not.
+func canWriteCluster(w http.ResponseWriter, r *http.Request, cluster tables.Cluster) bool {
+	var i int64
+	rq := db.Tab("clusters").Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where("clusters.id = ?", cluster.ID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return false
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not responsible for a node of cluster %s", http.StatusText(403), cluster.Name), 403)
+		return false
+	}
+	return true
+}
+
+//
+// GetCluster     godoc
+// @Summary      Show a cluster
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Cluster
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or cluster_id, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /clusters/{id}  [get]
+//
+func GetCluster(w http.ResponseWriter, r *http.Request) {
+	data := tables.ClusterFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// PatchCluster     godoc
+// @Summary      Patch a cluster
+// @Description  The user must be responsible for the app of one of the cluster member nodes.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or cluster_id, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Cluster
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /clusters/{id}  [patch]
+//
+func PatchCluster(w http.ResponseWriter, r *http.Request) {
+	clusters := tables.ClusterFromCtx(r)
+	if len(clusters) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := clusters[0]
+	if !checkIfMatch(w, r, clusters) {
+		return
+	}
+	if !canWriteCluster(w, r, current) {
+		return
+	}
+	if !patchEntry(w, r, "clusters", current, current.ID, db.ValidateWithReadOnly("cluster_id")) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Cluster{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+//
+// DelCluster     godoc
+// @Summary      Delete a cluster
+// @Description  The user must be responsible for the app of one of the cluster member nodes.
+// @Description  The cluster_id of the member nodes and services is unset.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Cluster
+// @Success      204  {string}  string  "No Content"
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      412  {string}  string  "Precondition Failed"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or cluster_id, or name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Router       /clusters/{id}  [delete]
+//
+func DelCluster(w http.ResponseWriter, r *http.Request) {
+	clusters := tables.ClusterFromCtx(r)
+	if len(clusters) == 0 {
+		http.Error(w, http.StatusText(204), 204)
+		return
+	}
+	current := clusters[0]
+	if !checkIfMatch(w, r, clusters) {
+		return
+	}
+	if !canWriteCluster(w, r, current) {
+		return
+	}
+	// delete first, so a failed If-Match precondition leaves the members
+	// unchanged
+	if err := deleteIfMatch(r, db.DB(), "clusters", current.ID, current.UpdatedAt, &tables.Cluster{}); err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	members := []struct {
+		table string
+		model interface{}
+	}{
+		{"nodes", &tables.Node{}},
+		{"services", &tables.Service{}},
+	}
+	for _, m := range members {
+		ids := make([]uint, 0)
+		if err := db.DB().Model(m.model).Where("cluster_id = ?", current.ClusterID).Pluck("id", &ids).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select %s: %s", m.table, err), 500)
+			return
+		}
+		for _, id := range ids {
+			if err := db.Tab(m.table).Update(id, map[string]interface{}{"cluster_id": ""}); err != nil {
+				http.Error(w, fmt.Sprintf("update %s cluster_id: %s", m.table, err), 500)
+				return
+			}
+		}
+	}
+	publishDeleted("clusters", current.ID)
+	jsonEncode(w, clusters)
+}
+
+//
+// GetClusterNodes     godoc
+// @Summary      List the nodes of a cluster
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or cluster_id, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /clusters/{id}/nodes  [get]
+//
+func GetClusterNodes(w http.ResponseWriter, r *http.Request) {
+	data := tables.ClusterFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	c := data[0]
+	rq := db.Tab("nodes").Request()
+	rq.Where("nodes.cluster_id = ?", c.ClusterID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetClusterServices     godoc
+// @Summary      List the services of a cluster
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         clusters
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or cluster_id, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /clusters/{id}/services  [get]
+//
+func GetClusterServices(w http.ResponseWriter, r *http.Request) {
+	data := tables.ClusterFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	c := data[0]
+	rq := db.Tab("services").Request()
+	rq.Where("services.cluster_id = ?", c.ClusterID)
+	serveTableResponse(w, r, rq)
+}
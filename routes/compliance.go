@@ -0,0 +1,424 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/compliance"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// callerNodeID returns the node_id of the node authenticated by the
+// request. It responds 403 if the request is not authenticated as a node.
+func callerNodeID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user := auth.User(r)
+	if !authuser.IsNode(user) {
+		http.Error(w, fmt.Sprintf("%s: requires node authentication", http.StatusText(403)), 403)
+		return "", false
+	}
+	return user.GetID(), true
+}
+
+// nodeServiceID returns the svc_id of the service with the svc_id or
+// svcname s, running on the node.
+func nodeServiceID(nodeID, s string) (string, bool, error) {
+	ids := make([]string, 0)
+	tx := db.DB().Table("services").
+		Joins("JOIN svcmon ON svcmon.svc_id = services.svc_id").
+		Where("svcmon.node_id = ? AND (services.svc_id = ? OR services.svcname = ?)", nodeID, s, s).
+		Distinct().
+		Pluck("services.svc_id", &ids)
+	if err := tx.Error; err != nil {
+		return "", false, err
+	}
+	if len(ids) != 1 {
+		return "", false, nil
+	}
+	return ids[0], true, nil
+}
+
+// parseIndex returns the index in database designated by a path
+// parameter, and false if the parameter is not an index.
+func parseIndex(s string) (uint, bool) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// compAttachTarget returns the node_id, svc_id or tag_id of the target of
+// a ruleset or moduleset attachment, designated by the kind and target_id
+// path parameters.
+//
+// The nodes and services are designated by index in database, uuid or
+// name, and the user must be responsible for their app. The tags are
+// designated by index in database, tag_id or name, and the user must have
+// the CompManager privilege.
+func compAttachTarget(w http.ResponseWriter, r *http.Request) (string, bool) {
+	kind := chi.URLParam(r, "kind")
+	s := chi.URLParam(r, "target_id")
+	_, isIndex := parseIndex(s)
+	switch kind {
+	case "nodes":
+		data := make([]tables.Node, 0)
+		tx := db.DB()
+		if isIndex {
+			tx = tx.Where("nodes.id = ?", s)
+		} else {
+			tx = tx.Where("nodes.node_id = ? OR nodes.nodename = ?", s, s)
+		}
+		if err := tx.Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select node: %s", err), 500)
+			return "", false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: node %s", http.StatusText(404), s), 404)
+			return "", false
+		}
+		if !canWriteEntry(w, r, "nodes", data[0].ID, data[0].App) {
+			return "", false
+		}
+		return data[0].NodeID, true
+	case "services":
+		data := make([]tables.Service, 0)
+		tx := db.DB()
+		if isIndex {
+			tx = tx.Where("services.id = ?", s)
+		} else {
+			tx = tx.Where("services.svc_id = ? OR services.svcname = ?", s, s)
+		}
+		if err := tx.Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+			return "", false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: service %s", http.StatusText(404), s), 404)
+			return "", false
+		}
+		if !canWriteEntry(w, r, "services", data[0].ID, data[0].SvcApp) {
+			return "", false
+		}
+		return data[0].SvcID, true
+	case "tags":
+		if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+			authuser.PrivError(w, "CompManager")
+			return "", false
+		}
+		data := make([]tables.Tag, 0)
+		tx := db.DB()
+		if isIndex {
+			tx = tx.Where("tags.id = ?", s)
+		} else {
+			tx = tx.Where("tags.tag_id = ? OR tags.tag_name = ?", s, s)
+		}
+		if err := tx.Find(&data).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select tag: %s", err), 500)
+			return "", false
+		}
+		if len(data) != 1 {
+			http.Error(w, fmt.Sprintf("%s: tag %s", http.StatusText(404), s), 404)
+			return "", false
+		}
+		return data[0].TagID, true
+	}
+	http.Error(w, fmt.Sprintf("%s: unknown attachment kind %s", http.StatusText(404), kind), 404)
+	return "", false
+}
+
+// postCompAttachment creates the attachment entry, unless it exists, and
+// responds the entry.
+func postCompAttachment(w http.ResponseWriter, table string, entry interface{}) {
+	tx := db.DB().Where(entry).FirstOrCreate(entry)
+	if err := tx.Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	if tx.RowsAffected > 0 {
+		publishUpserted(table, nil, []uint{entryID(entry)})
+	}
+	jsonEncode(w, entry)
+}
+
+// delCompEntries deletes the entries matching the entry non-zero fields,
+// and responds 204.
+func delCompEntries(w http.ResponseWriter, table string, entry interface{}) {
+	ids := make([]uint, 0)
+	if err := db.DB().Model(entry).Where(entry).Pluck("id", &ids).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return
+	}
+	if len(ids) > 0 {
+		if err := db.DB().Where("id IN (?)", ids).Delete(entry).Error; err != nil {
+			http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+			return
+		}
+	}
+	publishDeleted(table, ids...)
+	w.WriteHeader(204)
+}
+
+// compReadable returns true if the compliance ruleset or moduleset is
+// readable by the user. It responds 404 otherwise.
+func compReadable(w http.ResponseWriter, r *http.Request, table string, id uint) bool {
+	ok, err := db.Tab(table).Readable(auth.User(r), id, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select: %s", err), 500)
+		return false
+	}
+	if !ok {
+		http.Error(w, http.StatusText(404), 404)
+		return false
+	}
+	return true
+}
+
+// compCascade is a table referencing the compliance rulesets or
+// modulesets, whose entries are deleted with the ruleset or moduleset.
+type compCascade struct {
+	table string
+	entry interface{}
+}
+
+// delCompCascade deletes the entries of the cascade tables referencing the
+// ruleset or moduleset id by the key column, and returns the deleted
+// entries ids by table.
+func delCompCascade(tx *gorm.DB, key string, id uint, cascade []compCascade) (map[string][]uint, error) {
+	deleted := make(map[string][]uint)
+	for _, c := range cascade {
+		ids := make([]uint, 0)
+		if err := tx.Model(c.entry).Where(key+" = ?", id).Pluck("id", &ids).Error; err != nil {
+			return nil, fmt.Errorf("select %s: %w", c.table, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := tx.Where("id IN (?)", ids).Delete(c.entry).Error; err != nil {
+			return nil, fmt.Errorf("delete %s: %w", c.table, err)
+		}
+		deleted[c.table] = ids
+	}
+	return deleted, nil
+}
+
+// entryID returns the ID field of a pointer to a table entry.
+func entryID(entry interface{}) uint {
+	switch t := entry.(type) {
+	case *tables.CompRulesetNode:
+		return t.ID
+	case *tables.CompRulesetService:
+		return t.ID
+	case *tables.CompRulesetTag:
+		return t.ID
+	case *tables.CompModulesetNode:
+		return t.ID
+	case *tables.CompModulesetService:
+		return t.ID
+	case *tables.CompModulesetTag:
+		return t.ID
+	}
+	return 0
+}
+
+//
+// GetComplianceRules     godoc
+// @Summary      Show the compliance rules of the node
+// @Description  Requires node authentication.
+// @Description  The rules are the variables of the rulesets and the modules of the modulesets attached to the node, directly or via one of the node tags.
+// @Description  With the svc parameter, the rules also include the rulesets and modulesets attached to the service, directly or via one of the service tags.
+// @Description  A variable defined by several rulesets takes the value of the ruleset attached with the highest precedence: node tag, node, service tag, then service.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Param        svc  query     string  false  "the svc_id or svcname of a service running on the node"
+// @Success      200  {object}  compliance.Rules
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /compliance/rules  [get]
+//
+func GetComplianceRules(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	var svcID string
+	if s := r.URL.Query().Get("svc"); s != "" {
+		id, ok, err := nodeServiceID(nodeID, s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+			return
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("%s: service %s on this node", http.StatusText(404), s), 404)
+			return
+		}
+		svcID = id
+	}
+	rules, err := compliance.NodeRules(nodeID, svcID)
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	jsonEncode(w, rules)
+}
+
+//
+// GetComplianceStatus     godoc
+// @Summary      List the compliance modules status
+// @Description  List the last results reported by the nodes of the apps published to the user, per node, service and module.
+// @Description  The run_status is 0 for compliant, 1 for non compliant and 2 for not applicable.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example run_status=1"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /compliance/status  [get]
+//
+func GetComplianceStatus(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("comp_status").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostComplianceStatus     godoc
+// @Summary      Report compliance modules results
+// @Description  Requires node authentication.
+// @Description  The results replace the last results of the node for the same service and module.
+// @Description  The svc_id is the svc_id or svcname of a service running on the node, or empty for the node itself.
+// @Description  The run_action is check or fix, the run_status 0 for compliant, 1 for non compliant and 2 for not applicable. The run_date defaults to now.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Param        status  body      []tables.CompStatus  true  "list of module results"
+// @Success      200     {array}   tables.CompStatus
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      422     {object}  validationErrorResponse
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /compliance/status  [post]
+//
+func PostComplianceStatus(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.CompStatus, 0)
+	status := tables.CompStatus{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &status); err == nil {
+		// single entry
+		data = append(data, status)
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("comp_status"), entries)
+	now := time.Now()
+	for i := range data {
+		if !compliance.ValidStatus(data[i].Status) {
+			errs = append(errs, db.FieldError{Index: i, Field: "run_status", Error: "must be 0, 1 or 2"})
+		}
+		if data[i].SvcID != "" {
+			svcID, ok, err := nodeServiceID(nodeID, data[i].SvcID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+				return
+			}
+			if !ok {
+				errs = append(errs, db.FieldError{Index: i, Field: "svc_id", Error: "not a service of the node"})
+			}
+			data[i].SvcID = svcID
+		}
+		data[i].NodeID = nodeID
+		if data[i].Date.IsZero() {
+			data[i].Date = now
+		}
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	before := make([]uint, len(data))
+	for i := range data {
+		var ids []uint
+		if err := compStatusTX(db.DB(), data[i]).Pluck("id", &ids).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select: %s", err), 500)
+			return
+		}
+		if len(ids) > 0 {
+			before[i] = ids[0]
+		}
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"run_action", "run_status", "run_log", "run_date", "updated_at"}),
+	})
+	if err := tx.Create(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
+		return
+	}
+	// the ids of the updated entries are not returned by the upsert
+	after := make([]uint, len(data))
+	for i := range data {
+		entry := tables.CompStatus{}
+		if err := compStatusTX(db.DB(), data[i]).Take(&entry).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select after upsert: %s", err), 500)
+			return
+		}
+		data[i] = entry
+		after[i] = entry.ID
+	}
+	publishUpserted("comp_status", before, after)
+	jsonEncode(w, data)
+}
+
+// compStatusTX selects the status entry with the same node, service and
+// module as the status.
+func compStatusTX(tx *gorm.DB, status tables.CompStatus) *gorm.DB {
+	return tx.Model(&tables.CompStatus{}).Where("node_id = ? AND svc_id = ? AND run_module = ?", status.NodeID, status.SvcID, status.Module)
+}
@@ -0,0 +1,403 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetComplianceModuleset     godoc
+// @Summary      Show a compliance moduleset
+// @Description  The users without the CompManager privilege, and the nodes, can read the modulesets attached to a node or service they can read, directly or via one of its tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.CompModuleset
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /compliance/modulesets/{id}  [get]
+//
+func GetComplianceModuleset(w http.ResponseWriter, r *http.Request) {
+	data := tables.CompModulesetFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	if !compReadable(w, r, "comp_moduleset", data[0].ID) {
+		return
+	}
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// PatchComplianceModuleset     godoc
+// @Summary      Patch a compliance moduleset
+// @Description  Requires the CompManager privilege.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.CompModuleset
+// @Failure      401       {string}  string  "missing CompManager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /compliance/modulesets/{id}  [patch]
+//
+func PatchComplianceModuleset(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := modulesets[0]
+	if !checkIfMatch(w, r, modulesets) {
+		return
+	}
+	if !patchEntry(w, r, "comp_moduleset", current, current.ID) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.CompModuleset{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+//
+// DelComplianceModuleset     godoc
+// @Summary      Delete a compliance moduleset
+// @Description  Requires the CompManager privilege.
+// @Description  Cascade deletes the moduleset modules, and its attachments to nodes, services and tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.CompModuleset
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing CompManager privilege"
+// @Failure      412  {string}  string  "Precondition Failed"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Router       /compliance/modulesets/{id}  [delete]
+//
+func DelComplianceModuleset(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(204), 204)
+		return
+	}
+	current := modulesets[0]
+	if !checkIfMatch(w, r, modulesets) {
+		return
+	}
+	cascade := []compCascade{
+		{"comp_moduleset_modules", &tables.CompModulesetModule{}},
+		{"comp_modulesets_nodes", &tables.CompModulesetNode{}},
+		{"comp_modulesets_services", &tables.CompModulesetService{}},
+		{"comp_modulesets_tags", &tables.CompModulesetTag{}},
+	}
+	var deleted map[string][]uint
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := deleteIfMatch(r, tx, "comp_moduleset", current.ID, current.UpdatedAt, &[]tables.CompModuleset{}); err != nil {
+			return err
+		}
+		var err error
+		deleted, err = delCompCascade(tx, "modset_id", current.ID, cascade)
+		return err
+	})
+	if err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("comp_moduleset", current.ID)
+	for _, c := range cascade {
+		publishDeleted(c.table, deleted[c.table]...)
+	}
+	jsonEncode(w, modulesets)
+}
+
+//
+// GetComplianceModulesetModules     godoc
+// @Summary      List the modules of a compliance moduleset
+// @Description  The users without the CompManager privilege, and the nodes, can read the modulesets attached to a node or service they can read, directly or via one of its tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the moduleset in database, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /compliance/modulesets/{id}/modules  [get]
+//
+func GetComplianceModulesetModules(w http.ResponseWriter, r *http.Request) {
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	if !compReadable(w, r, "comp_moduleset", modulesets[0].ID) {
+		return
+	}
+	rq := db.Tab("comp_moduleset_modules").Request()
+	rq.Where("comp_moduleset_modules.modset_id = ?", modulesets[0].ID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostComplianceModulesetModules     godoc
+// @Summary      Create or update the modules of a compliance moduleset
+// @Description  Requires the CompManager privilege.
+// @Description  The modules are identified by their modset_mod_name in the moduleset.
+// @Description  The nodes fix the autofix modules their checks found non compliant.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "the index of the moduleset in database, or name"
+// @Param        modules    body      []tables.CompModulesetModule  true  "list of modules to create or update"
+// @Success      200        {array}   tables.CompModulesetModule
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      422        {object}  validationErrorResponse
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Router       /compliance/modulesets/{id}/modules  [post]
+//
+func PostComplianceModulesetModules(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := modulesets[0]
+	modules := make([]tables.CompModulesetModule, 0)
+	module := tables.CompModulesetModule{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &module); err == nil {
+		// single entry
+		modules = append(modules, module)
+	} else if err := json.Unmarshal(body, &modules); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("comp_moduleset_modules"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	before := make([]uint, len(modules))
+	for i := range modules {
+		var ids []uint
+		modules[i].ModsetID = current.ID
+		modules[i].ID = 0
+		if err := db.DB().Model(&tables.CompModulesetModule{}).Where("modset_id = ? AND modset_mod_name = ?", current.ID, modules[i].Name).Pluck("id", &ids).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select: %s", err), 500)
+			return
+		}
+		if len(ids) > 0 {
+			before[i] = ids[0]
+		}
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"autofix", "updated_at"}),
+	})
+	if err := tx.Create(&modules).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
+		return
+	}
+	// the ids of the updated entries are not returned by the upsert
+	after := make([]uint, len(modules))
+	for i := range modules {
+		entry := tables.CompModulesetModule{}
+		if err := db.DB().Where("modset_id = ? AND modset_mod_name = ?", current.ID, modules[i].Name).Take(&entry).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select after upsert: %s", err), 500)
+			return
+		}
+		modules[i] = entry
+		after[i] = entry.ID
+	}
+	publishUpserted("comp_moduleset_modules", before, after)
+	jsonEncode(w, modules)
+}
+
+//
+// DelComplianceModulesetModule     godoc
+// @Summary      Delete a module of a compliance moduleset
+// @Description  Requires the CompManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      204          {string}  string  "No Content"
+// @Failure      401          {string}  string  "missing CompManager privilege"
+// @Failure      404          {string}  string  "Not Found"
+// @Failure      500          {string}  string  "Internal Server Error"
+// @Param        id           path      string  true  "the index of the moduleset in database, or name"
+// @Param        module_id    path      string  true  "the index of the module in database, or name"
+// @Router       /compliance/modulesets/{id}/modules/{module_id}  [delete]
+//
+func DelComplianceModulesetModule(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	s := chi.URLParam(r, "module_id")
+	entry := tables.CompModulesetModule{ModsetID: modulesets[0].ID}
+	if id, ok := parseIndex(s); ok {
+		entry.ID = id
+	} else {
+		entry.Name = s
+	}
+	delCompEntries(w, "comp_moduleset_modules", &entry)
+}
+
+//
+// PostComplianceModulesetAttachment     godoc
+// @Summary      Attach a compliance moduleset to a node, service or tag
+// @Description  Attaching to a node or service requires the user to be responsible for its app.
+// @Description  Attaching to a tag requires the CompManager privilege. The moduleset then applies to the nodes and services with the tag.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200        {object}  tables.CompModulesetNode
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      403        {string}  string  "Forbidden"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Param        id         path      string  true  "the index of the moduleset in database, or name"
+// @Param        kind       path      string  true  "the kind of attachment target"  Enums(nodes, services, tags)
+// @Param        target_id  path      string  true  "the index of the node, service or tag in database, or uuid, or name"
+// @Router       /compliance/modulesets/{id}/{kind}/{target_id}  [post]
+//
+func PostComplianceModulesetAttachment(w http.ResponseWriter, r *http.Request) {
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	key, ok := compAttachTarget(w, r)
+	if !ok {
+		return
+	}
+	table, entry := modulesetAttachment(chi.URLParam(r, "kind"), modulesets[0].ID, key)
+	postCompAttachment(w, table, entry)
+}
+
+//
+// DelComplianceModulesetAttachment     godoc
+// @Summary      Detach a compliance moduleset from a node, service or tag
+// @Description  Detaching from a node or service requires the user to be responsible for its app.
+// @Description  Detaching from a tag requires the CompManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      204        {string}  string  "No Content"
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      403        {string}  string  "Forbidden"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Param        id         path      string  true  "the index of the moduleset in database, or name"
+// @Param        kind       path      string  true  "the kind of attachment target"  Enums(nodes, services, tags)
+// @Param        target_id  path      string  true  "the index of the node, service or tag in database, or uuid, or name"
+// @Router       /compliance/modulesets/{id}/{kind}/{target_id}  [delete]
+//
+func DelComplianceModulesetAttachment(w http.ResponseWriter, r *http.Request) {
+	modulesets := tables.CompModulesetFromCtx(r)
+	if len(modulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	key, ok := compAttachTarget(w, r)
+	if !ok {
+		return
+	}
+	table, entry := modulesetAttachment(chi.URLParam(r, "kind"), modulesets[0].ID, key)
+	delCompEntries(w, table, entry)
+}
+
+// modulesetAttachment returns the table and the entry attaching the moduleset
+// to the node, service or tag.
+func modulesetAttachment(kind string, id uint, key string) (string, interface{}) {
+	switch kind {
+	case "services":
+		return "comp_modulesets_services", &tables.CompModulesetService{ModsetID: id, SvcID: key}
+	case "tags":
+		return "comp_modulesets_tags", &tables.CompModulesetTag{ModsetID: id, TagID: key}
+	default:
+		return "comp_modulesets_nodes", &tables.CompModulesetNode{ModsetID: id, NodeID: key}
+	}
+}
@@ -0,0 +1,108 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+)
+
+//
+// GetComplianceRulesets     godoc
+// @Summary      List compliance rulesets
+// @Description  The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /compliance/rulesets  [get]
+//
+func GetComplianceRulesets(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("comp_rulesets").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostComplianceRulesets     godoc
+// @Summary      Create compliance rulesets
+// @Description  Requires the CompManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Param        rulesets  body      []tables.CompRuleset  true  "list of rulesets to create"
+// @Success      200       {array}   tables.CompRuleset
+// @Failure      401       {string}  string  "missing CompManager privilege"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Router       /compliance/rulesets  [post]
+//
+func PostComplianceRulesets(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	rulesets := make([]tables.CompRuleset, 0)
+	ruleset := tables.CompRuleset{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &ruleset); err == nil {
+		// single entry
+		rulesets = append(rulesets, ruleset)
+	} else if err := json.Unmarshal(body, &rulesets); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("comp_rulesets"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if err := db.DB().Create(&rulesets).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	for _, ruleset := range rulesets {
+		events.Publish(events.Create, "comp_rulesets", ruleset.ID)
+	}
+	if err := jsonEncode(w, rulesets); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
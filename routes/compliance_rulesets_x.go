@@ -0,0 +1,402 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetComplianceRuleset     godoc
+// @Summary      Show a compliance ruleset
+// @Description  The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.CompRuleset
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /compliance/rulesets/{id}  [get]
+//
+func GetComplianceRuleset(w http.ResponseWriter, r *http.Request) {
+	data := tables.CompRulesetFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	if !compReadable(w, r, "comp_rulesets", data[0].ID) {
+		return
+	}
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// PatchComplianceRuleset     godoc
+// @Summary      Patch a compliance ruleset
+// @Description  Requires the CompManager privilege.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.CompRuleset
+// @Failure      401       {string}  string  "missing CompManager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /compliance/rulesets/{id}  [patch]
+//
+func PatchComplianceRuleset(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := rulesets[0]
+	if !checkIfMatch(w, r, rulesets) {
+		return
+	}
+	if !patchEntry(w, r, "comp_rulesets", current, current.ID) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.CompRuleset{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+//
+// DelComplianceRuleset     godoc
+// @Summary      Delete a compliance ruleset
+// @Description  Requires the CompManager privilege.
+// @Description  Cascade deletes the ruleset variables, and its attachments to nodes, services and tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.CompRuleset
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing CompManager privilege"
+// @Failure      412  {string}  string  "Precondition Failed"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      string  true  "the index of the entry in database, or name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Router       /compliance/rulesets/{id}  [delete]
+//
+func DelComplianceRuleset(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(204), 204)
+		return
+	}
+	current := rulesets[0]
+	if !checkIfMatch(w, r, rulesets) {
+		return
+	}
+	cascade := []compCascade{
+		{"comp_rulesets_variables", &tables.CompRulesetVariable{}},
+		{"comp_rulesets_nodes", &tables.CompRulesetNode{}},
+		{"comp_rulesets_services", &tables.CompRulesetService{}},
+		{"comp_rulesets_tags", &tables.CompRulesetTag{}},
+	}
+	var deleted map[string][]uint
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := deleteIfMatch(r, tx, "comp_rulesets", current.ID, current.UpdatedAt, &[]tables.CompRuleset{}); err != nil {
+			return err
+		}
+		var err error
+		deleted, err = delCompCascade(tx, "ruleset_id", current.ID, cascade)
+		return err
+	})
+	if err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("comp_rulesets", current.ID)
+	for _, c := range cascade {
+		publishDeleted(c.table, deleted[c.table]...)
+	}
+	jsonEncode(w, rulesets)
+}
+
+//
+// GetComplianceRulesetVariables     godoc
+// @Summary      List the variables of a compliance ruleset
+// @Description  The users without the CompManager privilege, and the nodes, can read the rulesets attached to a node or service they can read, directly or via one of its tags.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the ruleset in database, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /compliance/rulesets/{id}/variables  [get]
+//
+func GetComplianceRulesetVariables(w http.ResponseWriter, r *http.Request) {
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	if !compReadable(w, r, "comp_rulesets", rulesets[0].ID) {
+		return
+	}
+	rq := db.Tab("comp_rulesets_variables").Request()
+	rq.Where("comp_rulesets_variables.ruleset_id = ?", rulesets[0].ID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostComplianceRulesetVariables     godoc
+// @Summary      Create or update the variables of a compliance ruleset
+// @Description  Requires the CompManager privilege.
+// @Description  The variables are identified by their var_name in the ruleset.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "the index of the ruleset in database, or name"
+// @Param        variables  body      []tables.CompRulesetVariable  true  "list of variables to create or update"
+// @Success      200        {array}   tables.CompRulesetVariable
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      422        {object}  validationErrorResponse
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Router       /compliance/rulesets/{id}/variables  [post]
+//
+func PostComplianceRulesetVariables(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := rulesets[0]
+	variables := make([]tables.CompRulesetVariable, 0)
+	variable := tables.CompRulesetVariable{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &variable); err == nil {
+		// single entry
+		variables = append(variables, variable)
+	} else if err := json.Unmarshal(body, &variables); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("comp_rulesets_variables"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	before := make([]uint, len(variables))
+	for i := range variables {
+		var ids []uint
+		variables[i].RulesetID = current.ID
+		variables[i].ID = 0
+		if err := db.DB().Model(&tables.CompRulesetVariable{}).Where("ruleset_id = ? AND var_name = ?", current.ID, variables[i].Name).Pluck("id", &ids).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select: %s", err), 500)
+			return
+		}
+		if len(ids) > 0 {
+			before[i] = ids[0]
+		}
+	}
+	tx := db.DB().Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"var_class", "var_value", "updated_at"}),
+	})
+	if err := tx.Create(&variables).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
+		return
+	}
+	// the ids of the updated entries are not returned by the upsert
+	after := make([]uint, len(variables))
+	for i := range variables {
+		entry := tables.CompRulesetVariable{}
+		if err := db.DB().Where("ruleset_id = ? AND var_name = ?", current.ID, variables[i].Name).Take(&entry).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select after upsert: %s", err), 500)
+			return
+		}
+		variables[i] = entry
+		after[i] = entry.ID
+	}
+	publishUpserted("comp_rulesets_variables", before, after)
+	jsonEncode(w, variables)
+}
+
+//
+// DelComplianceRulesetVariable     godoc
+// @Summary      Delete a variable of a compliance ruleset
+// @Description  Requires the CompManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      204          {string}  string  "No Content"
+// @Failure      401          {string}  string  "missing CompManager privilege"
+// @Failure      404          {string}  string  "Not Found"
+// @Failure      500          {string}  string  "Internal Server Error"
+// @Param        id           path      string  true  "the index of the ruleset in database, or name"
+// @Param        variable_id  path      string  true  "the index of the variable in database, or name"
+// @Router       /compliance/rulesets/{id}/variables/{variable_id}  [delete]
+//
+func DelComplianceRulesetVariable(w http.ResponseWriter, r *http.Request) {
+	if !authuser.HasPrivilege(auth.User(r), "CompManager") {
+		authuser.PrivError(w, "CompManager")
+		return
+	}
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	s := chi.URLParam(r, "variable_id")
+	entry := tables.CompRulesetVariable{RulesetID: rulesets[0].ID}
+	if id, ok := parseIndex(s); ok {
+		entry.ID = id
+	} else {
+		entry.Name = s
+	}
+	delCompEntries(w, "comp_rulesets_variables", &entry)
+}
+
+//
+// PostComplianceRulesetAttachment     godoc
+// @Summary      Attach a compliance ruleset to a node, service or tag
+// @Description  Attaching to a node or service requires the user to be responsible for its app.
+// @Description  Attaching to a tag requires the CompManager privilege. The ruleset then applies to the nodes and services with the tag.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      200        {object}  tables.CompRulesetNode
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      403        {string}  string  "Forbidden"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Param        id         path      string  true  "the index of the ruleset in database, or name"
+// @Param        kind       path      string  true  "the kind of attachment target"  Enums(nodes, services, tags)
+// @Param        target_id  path      string  true  "the index of the node, service or tag in database, or uuid, or name"
+// @Router       /compliance/rulesets/{id}/{kind}/{target_id}  [post]
+//
+func PostComplianceRulesetAttachment(w http.ResponseWriter, r *http.Request) {
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	key, ok := compAttachTarget(w, r)
+	if !ok {
+		return
+	}
+	table, entry := rulesetAttachment(chi.URLParam(r, "kind"), rulesets[0].ID, key)
+	postCompAttachment(w, table, entry)
+}
+
+//
+// DelComplianceRulesetAttachment     godoc
+// @Summary      Detach a compliance ruleset from a node, service or tag
+// @Description  Detaching from a node or service requires the user to be responsible for its app.
+// @Description  Detaching from a tag requires the CompManager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         compliance
+// @Accept       json
+// @Produce      json
+// @Success      204        {string}  string  "No Content"
+// @Failure      401        {string}  string  "missing CompManager privilege"
+// @Failure      403        {string}  string  "Forbidden"
+// @Failure      404        {string}  string  "Not Found"
+// @Failure      500        {string}  string  "Internal Server Error"
+// @Param        id         path      string  true  "the index of the ruleset in database, or name"
+// @Param        kind       path      string  true  "the kind of attachment target"  Enums(nodes, services, tags)
+// @Param        target_id  path      string  true  "the index of the node, service or tag in database, or uuid, or name"
+// @Router       /compliance/rulesets/{id}/{kind}/{target_id}  [delete]
+//
+func DelComplianceRulesetAttachment(w http.ResponseWriter, r *http.Request) {
+	rulesets := tables.CompRulesetFromCtx(r)
+	if len(rulesets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	key, ok := compAttachTarget(w, r)
+	if !ok {
+		return
+	}
+	table, entry := rulesetAttachment(chi.URLParam(r, "kind"), rulesets[0].ID, key)
+	delCompEntries(w, table, entry)
+}
+
+// rulesetAttachment returns the table and the entry attaching the ruleset
+// to the node, service or tag.
+func rulesetAttachment(kind string, id uint, key string) (string, interface{}) {
+	switch kind {
+	case "services":
+		return "comp_rulesets_services", &tables.CompRulesetService{RulesetID: id, SvcID: key}
+	case "tags":
+		return "comp_rulesets_tags", &tables.CompRulesetTag{RulesetID: id, TagID: key}
+	default:
+		return "comp_rulesets_nodes", &tables.CompRulesetNode{RulesetID: id, NodeID: key}
+	}
+}
@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+)
+
+// refreshAlerts enqueues the refresh of the dashboard alerts of a node or
+// service, on behalf of the request user. The errors are logged, as the
+// request succeeded anyway.
+func refreshAlerts(r *http.Request, args dashboard.RefreshArgs) {
+	userID, _ := strconv.Atoi(auth.User(r).GetID())
+	if _, err := dashboard.EnqueueRefresh(uint(userID), args); err != nil {
+		log.Printf("enqueue dashboard alerts refresh %+v: %s", args, err)
+	}
+}
+
+//
+// GetDashboard     godoc
+// @Summary      List dashboard alerts
+// @Description  List the alerts raised by the dashboard rules on the nodes and services of the apps published to the user.
+// @Description  The dash_severity is 0 for info, 1 for notice, 2 for warning, 3 for error and 4 for critical.
+// @Description  The alert message is the dash_fmt format, whose %(key)s placeholders are the dash_dict values.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example dash_severity>=3"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /dashboard  [get]
+//
+func GetDashboard(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("dashboard").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostDashboardRefresh     godoc
+// @Summary      Refresh the dashboard alerts
+// @Description  Requires the Manager privilege.
+// @Description  Enqueue the refresh of the alerts of all nodes and services, and respond with the background job.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Success      202  {object}  tables.Job
+// @Failure      401  {string}  string  "missing Manager privilege"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /dashboard/refresh  [post]
+//
+func PostDashboardRefresh(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.IsManager(user) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	userID, _ := strconv.Atoi(user.GetID())
+	job, err := dashboard.EnqueueRefresh(uint(userID), dashboard.RefreshArgs{})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("enqueue: %s", err), 500)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	jsonEncode(w, job)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+//
+// GetDashboardAcks     godoc
+// @Summary      List active dashboard alert acknowledgements
+// @Description  List the unexpired acknowledgements of the alerts on the nodes and services of the apps published to the user.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /dashboard/acks  [get]
+//
+func GetDashboardAcks(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("dashboard_acks").Request()
+	rq.Where("dashboard_acks.expires > ?", time.Now())
+	serveTableResponse(w, r, rq)
+}
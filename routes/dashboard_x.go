@@ -0,0 +1,144 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+)
+
+type (
+	dashboardAckRequest struct {
+		Comment string     `json:"comment"`
+		Expires *time.Time `json:"expires"`
+	}
+)
+
+// canWriteEntry verifies the user is responsible for the app of the table
+// entry. It responds 403 if not.
+func canWriteEntry(w http.ResponseWriter, r *http.Request, table string, id uint, app string) bool {
+	var i int64
+	rq := db.Tab(table).Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where(fmt.Sprintf("%s.id = ?", table), id).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return false
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not responsible for app %s", http.StatusText(403), app), 403)
+		return false
+	}
+	return true
+}
+
+//
+// PostDashboardAck     godoc
+// @Summary      Acknowledge a dashboard alert
+// @Description  The user must be responsible for the app of the alert, via app responsibles.
+// @Description  The alerts of the same type on the same node or service are acknowledged until the expiry date, 24 hours from now by default.
+// @Description  The acknowledged alerts are still listed, flagged as acked, but not notified.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int     true  "the index of the alert in database"
+// @Param        ack  body      dashboardAckRequest  true  "the acknowledgement comment and expiry date"
+// @Success      200  {object}  tables.DashboardAck
+// @Failure      400  {string}  string  "Bad Request"
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      422  {object}  validationErrorResponse
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /dashboard/{id}/ack  [post]
+//
+func PostDashboardAck(w http.ResponseWriter, r *http.Request) {
+	entries := tables.DashboardFromCtx(r)
+	if len(entries) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := entries[0]
+	if !canWriteEntry(w, r, "dashboard", current.ID, current.App) {
+		return
+	}
+	req := dashboardAckRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 400)
+		return
+	}
+	now := time.Now()
+	expires := now.Add(dashboard.DefaultAckDuration)
+	if req.Expires != nil {
+		expires = *req.Expires
+	}
+	errs := make(db.FieldErrors, 0)
+	if req.Comment == "" {
+		errs = append(errs, db.FieldError{Field: "comment", Error: "required"})
+	}
+	if !expires.After(now) {
+		errs = append(errs, db.FieldError{Field: "expires", Error: "must be in the future"})
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	userID, _ := strconv.Atoi(auth.User(r).GetID())
+	ack, err := dashboard.Ack(current, uint(userID), req.Comment, expires)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("ack: %s", err), 500)
+		return
+	}
+	if ack.CreatedAt.Equal(ack.UpdatedAt) {
+		events.Publish(events.Create, "dashboard_acks", ack.ID)
+	} else {
+		events.Publish(events.Update, "dashboard_acks", ack.ID)
+	}
+	events.Publish(events.Update, "dashboard", current.ID, "acked")
+	jsonEncode(w, ack)
+}
+
+//
+// DelDashboardAck     godoc
+// @Summary      Remove the acknowledgement of a dashboard alert
+// @Description  The user must be responsible for the app of the alert, via app responsibles.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         dashboard
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int     true  "the index of the alert in database"
+// @Success      200  {array}   tables.Dashboard
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /dashboard/{id}/ack  [delete]
+//
+func DelDashboardAck(w http.ResponseWriter, r *http.Request) {
+	entries := tables.DashboardFromCtx(r)
+	if len(entries) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := entries[0]
+	if !canWriteEntry(w, r, "dashboard", current.ID, current.App) {
+		return
+	}
+	acks, err := dashboard.Unack(current)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unack: %s", err), 500)
+		return
+	}
+	for _, ack := range acks {
+		publishDeleted("dashboard_acks", ack.ID)
+	}
+	events.Publish(events.Update, "dashboard", current.ID, "acked")
+	current.Acked = false
+	jsonEncode(w, []tables.Dashboard{current})
+}
@@ -0,0 +1,424 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetDisks     godoc
+// @Summary      List the disks of the nodes and services
+// @Description  The disk storage array information is available as the diskinfo properties, for example props=svcdisks.disk_id,services.svcname,diskinfo.disk_arrayid.
+// @Description  The disks with an empty svc_id are used by the node itself.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example disk_local=false"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /disks  [get]
+//
+func GetDisks(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("svcdisks").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeDisks     godoc
+// @Summary      List the disks of a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/disks  [get]
+//
+func GetNodeDisks(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("svcdisks").Request()
+	rq.Where("svcdisks.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetServiceDisks     godoc
+// @Summary      List the disks of a service
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /services/{id}/disks  [get]
+//
+func GetServiceDisks(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	rq := db.Tab("svcdisks").Request()
+	rq.Where("svcdisks.svc_id = ?", svc.SvcID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostDisks     godoc
+// @Summary      Replace the disks inventory of the node
+// @Description  Requires node authentication.
+// @Description  The disks replace the disks inventory of the node atomically.
+// @Description  A disk is identified by its disk_id wwid and the svc_id of the service using it, empty if used by the node itself.
+// @Description  The svc_id is the svc_id or svcname of a service running on the node.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Accept       json
+// @Produce      json
+// @Param        disks  body      []tables.ServiceDisk  true  "list of the node disks"
+// @Success      200    {array}   tables.ServiceDisk
+// @Failure      403    {string}  string  "Forbidden"
+// @Failure      422    {object}  validationErrorResponse
+// @Failure      500    {string}  string  "Internal Server Error"
+// @Router       /disks  [post]
+//
+func PostDisks(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.ServiceDisk, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("svcdisks"), entries)
+	svcIDs := make(map[string]string)
+	keys := make(map[string]bool)
+	for i := range data {
+		if s := data[i].SvcID; s != "" {
+			svcID, ok := svcIDs[s]
+			if !ok {
+				id, found, err := nodeServiceID(nodeID, s)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+					return
+				}
+				if found {
+					svcID = id
+				}
+				svcIDs[s] = svcID
+			}
+			if svcID == "" {
+				errs = append(errs, db.FieldError{Index: i, Field: "svc_id", Error: "not a service of the node"})
+			}
+			data[i].SvcID = svcID
+		}
+		k := data[i].SvcID + "/" + data[i].DiskID
+		if keys[k] {
+			errs = append(errs, db.FieldError{Index: i, Error: "duplicate entry"})
+		}
+		keys[k] = true
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if err := replaceNodeEntries("svcdisks", nodeID, &tables.ServiceDisk{}, &data, len(data)); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
+
+//
+// GetDisksInfo     godoc
+// @Summary      List the disks storage array information
+// @Description  The disk_id is the disk wwid, the key of the disks of the nodes and services.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example disk_arrayid=svc01"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /disks/info  [get]
+//
+func GetDisksInfo(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("diskinfo").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostDisksInfo     godoc
+// @Summary      Report disks storage array information
+// @Description  Requires node authentication, from a node with access to the array management interface.
+// @Description  The information replaces the information of the disks with the same disk_id, if reported by the same node.
+// @Description  The node_id is set to the reporting node. The information of a disk reported by another node can not be replaced, until that node is deleted or a manager deletes the information.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Accept       json
+// @Produce      json
+// @Param        disks  body      []tables.DiskInfo  true  "list of disks information"
+// @Success      200    {array}   tables.DiskInfo
+// @Failure      403    {string}  string  "Forbidden"
+// @Failure      422    {object}  validationErrorResponse
+// @Failure      500    {string}  string  "Internal Server Error"
+// @Router       /disks/info  [post]
+//
+func PostDisksInfo(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.DiskInfo, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("diskinfo"), entries)
+	keys := make(map[string]bool)
+	diskIDs := make([]string, len(data))
+	for i, e := range data {
+		if keys[e.DiskID] {
+			errs = append(errs, db.FieldError{Index: i, Error: "duplicate entry"})
+		}
+		keys[e.DiskID] = true
+		diskIDs[i] = e.DiskID
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if len(data) == 0 {
+		jsonEncode(w, data)
+		return
+	}
+	var before, after []uint
+	err = db.DB().Transaction(func(tx *gorm.DB) error {
+		// lock the existing entries, so the concurrent reports of the
+		// same disks by other nodes wait for the ownership verdict
+		existing := make([]tables.DiskInfo, 0)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("disk_id IN ?", diskIDs).Find(&existing).Error; err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		existingIDs := make(map[string]uint)
+		reporters := make(map[string]string)
+		for _, e := range existing {
+			existingIDs[e.DiskID] = e.ID
+			reporters[e.DiskID] = e.NodeID
+		}
+		for i, e := range data {
+			// the entries without reporting node are claimed by the first
+			// node reporting them
+			if reporter := reporters[e.DiskID]; reporter != "" && reporter != nodeID {
+				errs = append(errs, db.FieldError{Index: i, Error: "disk information reported by another node"})
+			}
+		}
+		if len(errs) > 0 {
+			return nil
+		}
+		before = make([]uint, len(data))
+		for i, e := range data {
+			before[i] = existingIDs[e.DiskID]
+		}
+		upsert := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"node_id", "disk_devid", "disk_arrayid", "disk_name", "disk_size", "disk_alloc", "disk_raid", "disk_group", "disk_level", "updated_at"}),
+		})
+		if err := upsert.Create(&data).Error; err != nil {
+			return fmt.Errorf("insert or update: %w", err)
+		}
+		// the ids of the updated entries are not returned by the upsert
+		data = make([]tables.DiskInfo, 0)
+		if err := tx.Where("disk_id IN ?", diskIDs).Find(&data).Error; err != nil {
+			return fmt.Errorf("select after upsert: %w", err)
+		}
+		after = make([]uint, len(diskIDs))
+		for i, diskID := range diskIDs {
+			for _, e := range data {
+				if e.DiskID == diskID {
+					after[i] = e.ID
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	publishUpserted("diskinfo", before, after)
+	jsonEncode(w, data)
+}
+
+//
+// DelDiskInfo     godoc
+// @Summary      Delete a disk storage array information
+// @Description  Requires the Manager privilege.
+// @Description  The next node reporting the disk information becomes its reporting node.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         disks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing Manager privilege"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /disks/info/{id}  [delete]
+//
+func DelDiskInfo(w http.ResponseWriter, r *http.Request) {
+	if !authuser.IsManager(auth.User(r)) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	id, ok := parseIndex(chi.URLParam(r, "id"))
+	if !ok {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	tx := db.DB().Delete(&tables.DiskInfo{}, id)
+	if err := tx.Error; err != nil {
+		http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+		return
+	}
+	if tx.RowsAffected == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	publishDeleted("diskinfo", id)
+	w.WriteHeader(204)
+}
@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/funcopt"
+	"gorm.io/gorm"
+)
+
+// etagOf returns a strong entity tag computed from the json representation
+// of data. The representation of the entries includes their updated_at
+// timestamp, so the tag changes on every update.
+func etagOf(data interface{}) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatch returns true if the If-Match or If-None-Match header value
+// contains the entity tag, or is "*". The weak comparison, used for
+// If-None-Match, ignores the W/ prefix of the header tags. The strong
+// comparison, used for If-Match, never matches a weak tag (RFC 7232 2.3.2).
+func etagMatch(header, etag string, weak bool) bool {
+	for _, s := range strings.Split(header, ",") {
+		s = strings.TrimSpace(s)
+		if weak {
+			s = strings.TrimPrefix(s, "W/")
+		}
+		if s == "*" || s == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEncodeWithETag sets the ETag header of the response and, for GET
+// requests, responds 304 Not Modified instead of the data if the client
+// If-None-Match header matches.
+func jsonEncodeWithETag(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	etag, err := etagOf(data)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("ETag", etag)
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		if s := r.Header.Get("If-None-Match"); s != "" && etagMatch(s, etag, true) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+	return jsonEncode(w, data)
+}
+
+// checkIfMatch verifies the If-Match precondition of an update or delete
+// request against the current representation of the entry. It responds
+// 412 Precondition Failed and returns false if the entry was modified
+// since the client read it.
+//
+// The entry can still be modified between this verification and the
+// write, so the write must also be conditional, with ifMatchUpdateOpts or
+// deleteIfMatch.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, current interface{}) bool {
+	s := r.Header.Get("If-Match")
+	if s == "" {
+		return true
+	}
+	etag, err := etagOf(current)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("etag: %s", err), 500)
+		return false
+	}
+	if !etagMatch(s, etag, false) {
+		http.Error(w, http.StatusText(412), 412)
+		return false
+	}
+	return true
+}
+
+// hasIfMatch returns true if the request has an If-Match precondition
+// on a specific representation of the entry.
+func hasIfMatch(r *http.Request) bool {
+	s := strings.TrimSpace(r.Header.Get("If-Match"))
+	return s != "" && s != "*"
+}
+
+// ifMatchUpdateOpts returns the db.Table.Update options making the update
+// conditional to the entry updated_at value verified by checkIfMatch.
+func ifMatchUpdateOpts(r *http.Request, updatedAt time.Time) []funcopt.O {
+	if !hasIfMatch(r) {
+		return nil
+	}
+	return []funcopt.O{db.UpdateWithVersion(updatedAt)}
+}
+
+// deleteIfMatch deletes the entry with the id from the table, on the
+// condition its updated_at value is still the one verified by
+// checkIfMatch. It returns db.ErrPreconditionFailed if the entry was
+// modified or deleted meanwhile.
+func deleteIfMatch(r *http.Request, tx *gorm.DB, table string, id uint, updatedAt time.Time, entry interface{}) error {
+	tx = tx.Where(table+".id = ?", id)
+	if hasIfMatch(r) {
+		tx = tx.Where(table+".updated_at = ?", updatedAt)
+	}
+	result := tx.Delete(entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if hasIfMatch(r) && result.RowsAffected == 0 {
+		return db.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// writeError responds 412 Precondition Failed if the write failed its
+// If-Match condition, or 500 with the message prefix and error otherwise.
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	if errors.Is(err, db.ErrPreconditionFailed) {
+		http.Error(w, http.StatusText(412), 412)
+		return
+	}
+	http.Error(w, fmt.Sprintf("%s: %s", prefix, err), 500)
+}
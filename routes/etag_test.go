@@ -0,0 +1,133 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagMatch(t *testing.T) {
+	tests := map[string]struct {
+		header   string
+		etag     string
+		weak     bool
+		expected bool
+	}{
+		"strong equal": {
+			header:   `"abc"`,
+			etag:     `"abc"`,
+			expected: true,
+		},
+		"strong different": {
+			header:   `"abd"`,
+			etag:     `"abc"`,
+			expected: false,
+		},
+		"strong weak tag": {
+			header:   `W/"abc"`,
+			etag:     `"abc"`,
+			expected: false,
+		},
+		"weak weak tag": {
+			header:   `W/"abc"`,
+			etag:     `"abc"`,
+			weak:     true,
+			expected: true,
+		},
+		"weak different": {
+			header:   `W/"abd"`,
+			etag:     `"abc"`,
+			weak:     true,
+			expected: false,
+		},
+		"strong star": {
+			header:   `*`,
+			etag:     `"abc"`,
+			expected: true,
+		},
+		"weak star": {
+			header:   `*`,
+			etag:     `"abc"`,
+			weak:     true,
+			expected: true,
+		},
+		"strong list": {
+			header:   `"abd", "abc"`,
+			etag:     `"abc"`,
+			expected: true,
+		},
+		"strong list without match": {
+			header:   `"abd","abe"`,
+			etag:     `"abc"`,
+			expected: false,
+		},
+		"strong list with weak tag": {
+			header:   `"abd", W/"abc"`,
+			etag:     `"abc"`,
+			expected: false,
+		},
+		"weak list with weak tag": {
+			header:   `"abd", W/"abc"`,
+			etag:     `"abc"`,
+			weak:     true,
+			expected: true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, etagMatch(test.header, test.etag, test.weak))
+	}
+}
+
+func TestCheckIfMatch(t *testing.T) {
+	current := []map[string]string{{"name": "n1"}}
+	etag, err := etagOf(current)
+	assert.Nil(t, err)
+	tests := map[string]struct {
+		header   string
+		expected bool
+		status   int
+	}{
+		"no header": {
+			expected: true,
+			status:   200,
+		},
+		"match": {
+			header:   etag,
+			expected: true,
+			status:   200,
+		},
+		"star": {
+			header:   "*",
+			expected: true,
+			status:   200,
+		},
+		"list": {
+			header:   `"abc", ` + etag,
+			expected: true,
+			status:   200,
+		},
+		"weak": {
+			header:   "W/" + etag,
+			expected: false,
+			status:   412,
+		},
+		"modified": {
+			header:   `"abc"`,
+			expected: false,
+			status:   412,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		r := httptest.NewRequest(http.MethodPatch, "/nodes/1", nil)
+		if test.header != "" {
+			r.Header.Set("If-Match", test.header)
+		}
+		w := httptest.NewRecorder()
+		assert.Equal(t, test.expected, checkIfMatch(w, r, current))
+		assert.Equal(t, test.status, w.Code)
+	}
+}
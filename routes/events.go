@@ -0,0 +1,308 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/events"
+)
+
+const (
+	eventStreamContentType = "text/event-stream"
+	eventKeepaliveInterval = 15 * time.Second
+
+	// eventBatchSize is the maximum number of pending events checked
+	// for visibility together.
+	eventBatchSize = 256
+
+	// eventReadableTTL is how long a client stream remembers an entry
+	// was readable, to stream its delete event once it is gone.
+	eventReadableTTL = 10 * time.Minute
+)
+
+type (
+	// eventFilter decides which events are streamed to a client. It
+	// checks the readability of a batch of events entries with a single
+	// query per table, and remembers the entries found readable, whose
+	// delete events are streamed even if the entries are gone.
+	eventFilter struct {
+		r         *http.Request
+		tables    map[string]*db.Table
+		filters   []string
+		readable  map[eventEntry]time.Time
+		lastPurge time.Time
+	}
+
+	eventEntry struct {
+		table string
+		id    uint
+	}
+)
+
+// publishUpserted publishes the change events of upserted entries. The
+// entries without id before the upsert are created, the others updated.
+func publishUpserted(table string, before, after []uint) {
+	for i, id := range after {
+		if i < len(before) && before[i] != 0 {
+			events.Publish(events.Update, table, id)
+		} else {
+			events.Publish(events.Create, table, id)
+		}
+	}
+}
+
+// publishDeleted publishes the delete events of the entries.
+func publishDeleted(table string, ids ...uint) {
+	for _, id := range ids {
+		events.Publish(events.Delete, table, id)
+	}
+}
+
+//
+// GetEvents     godoc
+// @Summary      Stream the table entries change events
+// @Description  Stream the create, update and delete events of the table entries the user can read, as Server-Sent Events.
+// @Description  The event data is the json encoded event, without the entry itself: read it from the table routes.
+// @Description  A client reconnecting with the Last-Event-ID header receives the events it missed, or a reset event if they are no longer available.
+// @Description  The delete events of entries removed from the database are streamed if the entry was found readable in the last 10 minutes of the stream, and to managers without filters.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         events
+// @Produce      text/event-stream
+// @Success      200      {object}  events.Event
+// @Failure      400      {string}  string    "Bad Request"
+// @Param        tables   query     string    false  "tables to stream the events of (comma separated, default all)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), with a single table"
+// @Param        last_event_id  query  int    false  "resume after this event id, if the Last-Event-ID header is not set"
+// @Param        Last-Event-ID  header  int   false  "resume after this event id"
+// @Router       /events  [get]
+//
+func GetEvents(w http.ResponseWriter, r *http.Request) {
+	tables, err := queryEventTables(r)
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 400)
+		return
+	}
+	filters := r.URL.Query()["filters"]
+	if len(filters) > 0 && len(tables) != 1 {
+		http.Error(w, "filters require a single table", 400)
+		return
+	}
+	lastID, resume, err := queryLastEventID(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("last event id: %s", err), 400)
+		return
+	}
+	sub, backlog := events.Subscribe(lastID, resume)
+	defer events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+	flush(w)
+
+	filter := newEventFilter(r, tables, filters)
+	send := func(batch []events.Event) bool {
+		for _, e := range filter.visible(batch) {
+			if err := writeEvent(w, e); err != nil {
+				log.Printf("GetEvents: %s", err)
+				return false
+			}
+		}
+		flush(w)
+		return true
+	}
+	for i := 0; i < len(backlog); i += eventBatchSize {
+		end := i + eventBatchSize
+		if end > len(backlog) {
+			end = len(backlog)
+		}
+		if !send(backlog[i:end]) {
+			return
+		}
+	}
+	ticker := time.NewTicker(eventKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flush(w)
+		case e, ok := <-sub.C:
+			if !ok {
+				// too slow, the client resumes from its last event
+				return
+			}
+			batch, ok := pendingEvents(sub.C, e)
+			if !send(batch) || !ok {
+				return
+			}
+		}
+	}
+}
+
+// pendingEvents returns the received event followed by the events already
+// pending in the subscription channel, up to eventBatchSize, and false if
+// the channel is closed.
+func pendingEvents(c <-chan events.Event, e events.Event) ([]events.Event, bool) {
+	batch := []events.Event{e}
+	for len(batch) < eventBatchSize {
+		select {
+		case e, ok := <-c:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, e)
+		default:
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+// queryEventTables returns the tables to stream the events of, indexed by
+// name, from the "tables" query parameter. A nil map selects all tables.
+func queryEventTables(r *http.Request) (map[string]*db.Table, error) {
+	s := r.URL.Query().Get("tables")
+	if s == "" {
+		return nil, nil
+	}
+	m := make(map[string]*db.Table)
+	for _, name := range strings.Split(s, ",") {
+		t := db.Tab(name)
+		if t == nil {
+			return nil, fmt.Errorf("tables: unknown table %s", name)
+		}
+		m[name] = t
+	}
+	return m, nil
+}
+
+// queryLastEventID returns the id of the last event received by a
+// resuming client, from the Last-Event-ID header or the last_event_id
+// query parameter, and true if the client is resuming.
+func queryLastEventID(r *http.Request) (uint64, bool, error) {
+	s := r.Header.Get("Last-Event-ID")
+	if s == "" {
+		s = r.URL.Query().Get("last_event_id")
+	}
+	if s == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+func newEventFilter(r *http.Request, tables map[string]*db.Table, filters []string) *eventFilter {
+	return &eventFilter{
+		r:         r,
+		tables:    tables,
+		filters:   filters,
+		readable:  make(map[eventEntry]time.Time),
+		lastPurge: time.Now(),
+	}
+}
+
+// visible returns the events of the batch concerning one of the streamed
+// tables, whose entry is readable by the user and matches the filters.
+func (t *eventFilter) visible(batch []events.Event) []events.Event {
+	now := time.Now()
+	t.purge(now)
+	ids := make(map[string][]uint)
+	for _, e := range batch {
+		if t.streamed(e) {
+			ids[e.Table] = append(ids[e.Table], e.EntryID)
+		}
+	}
+	user := auth.User(t.r)
+	readable := make(map[eventEntry]bool)
+	for table, l := range ids {
+		m, err := db.Tab(table).ReadableIDs(user, l, t.filters)
+		if err != nil {
+			log.Printf("GetEvents: table %s: %s", table, err)
+			continue
+		}
+		for id := range m {
+			readable[eventEntry{table: table, id: id}] = true
+		}
+	}
+	l := make([]events.Event, 0, len(batch))
+	for _, e := range batch {
+		if e.Kind == events.Reset {
+			l = append(l, e)
+			continue
+		}
+		if !t.streamed(e) {
+			continue
+		}
+		k := eventEntry{table: e.Table, id: e.EntryID}
+		switch {
+		case readable[k]:
+			t.readable[k] = now.Add(eventReadableTTL)
+		case e.Kind != events.Delete:
+			continue
+		case now.Before(t.readable[k]):
+			// the entry is gone, but was readable
+		case len(t.filters) == 0 && authuser.IsManager(user):
+			// the entry is gone
+		default:
+			continue
+		}
+		if e.Kind == events.Delete {
+			delete(t.readable, k)
+		}
+		l = append(l, e)
+	}
+	return l
+}
+
+// streamed returns true if the event concerns an entry of one of the
+// streamed tables.
+func (t *eventFilter) streamed(e events.Event) bool {
+	if e.Kind == events.Reset || db.Tab(e.Table) == nil {
+		return false
+	}
+	if t.tables == nil {
+		return true
+	}
+	_, ok := t.tables[e.Table]
+	return ok
+}
+
+// purge forgets the expired readable entries, at most once per ttl.
+func (t *eventFilter) purge(now time.Time) {
+	if now.Sub(t.lastPurge) < eventReadableTTL {
+		return
+	}
+	t.lastPurge = now
+	for k, expires := range t.readable {
+		if !now.Before(expires) {
+			delete(t.readable, k)
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e events.Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Kind, b)
+	return err
+}
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+)
+
+//
+// GetFiltersets     godoc
+// @Summary      List filtersets
+// @Description  List the filtersets owned by the user or shared with one of the user groups.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /filtersets  [get]
+//
+func GetFiltersets(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("filtersets").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostFiltersets     godoc
+// @Summary      Create filtersets
+// @Description  The filtersets are owned by the user.
+// @Description  A filterset can be applied to any table request with the filterset query parameter.
+// @Description  The unqualified properties of its filters refer to the filterset table.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Param        filtersets  body      []tables.Filterset  true  "list of filtersets to create"
+// @Success      200         {array}   tables.Filterset
+// @Failure      403         {string}  string  "Forbidden"
+// @Failure      422         {object}  validationErrorResponse
+// @Failure      500         {string}  string  "Internal Server Error"
+// @Router       /filtersets  [post]
+//
+func PostFiltersets(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	userID, err := strconv.Atoi(user.GetID())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s: nodes can not create filtersets", http.StatusText(403)), 403)
+		return
+	}
+	filtersets := make([]tables.Filterset, 0)
+	filterset := tables.Filterset{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &filterset); err == nil {
+		// single entry
+		filtersets = append(filtersets, filterset)
+	} else if err := json.Unmarshal(body, &filtersets); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateFiltersetEntries(entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	for i := range filtersets {
+		filtersets[i].UserID = uint(userID)
+	}
+	if err := db.DB().Create(&filtersets).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	for _, fs := range filtersets {
+		events.Publish(events.Create, "filtersets", fs.ID)
+	}
+	if err := jsonEncode(w, filtersets); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+// validateFiltersetEntries verifies the filtersets to create against the
+// table rules, and verifies the filterset table is a registered table and
+// the filters are a list of strings.
+func validateFiltersetEntries(entries []map[string]interface{}) db.FieldErrors {
+	errs := validateEntries(db.Tab("filtersets"), entries)
+	for i, entry := range entries {
+		errs = append(errs, validateFiltersetEntry(entry).WithIndex(i)...)
+	}
+	return errs
+}
+
+func validateFiltersetEntry(entry map[string]interface{}) db.FieldErrors {
+	errs := make(db.FieldErrors, 0)
+	if s, ok := entry["table_name"].(string); ok && db.Tab(s) == nil {
+		errs = append(errs, db.FieldError{Field: "table_name", Error: "unknown table"})
+	}
+	if i, ok := entry["filters"]; ok && i != nil && !isStringList(i) {
+		errs = append(errs, db.FieldError{Field: "filters", Error: "must be a list of strings"})
+	}
+	return errs
+}
@@ -0,0 +1,333 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//
+// GetFilterset     godoc
+// @Summary      Show a filterset
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Filterset
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /filtersets/{id}  [get]
+//
+func GetFilterset(w http.ResponseWriter, r *http.Request) {
+	data := tables.FiltersetFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// PatchFilterset     godoc
+// @Summary      Patch a filterset
+// @Description  The user must own the filterset, or have the Manager privilege.
+// @Description  The UserManager privilege is required if the filterset is assigned to a user with a locked filter.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      int     true   "the index of the entry in database"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Filterset
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /filtersets/{id}  [patch]
+//
+func PatchFilterset(w http.ResponseWriter, r *http.Request) {
+	currents := tables.FiltersetFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	if !canWriteFilterset(w, r, current) {
+		return
+	}
+	if !checkIfMatch(w, r, currents) {
+		return
+	}
+	if !patchEntry(w, r, "filtersets", current, current.ID, db.ValidateWithFunc(validateFiltersetEntry)) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Filterset{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+//
+// DelFilterset     godoc
+// @Summary      Delete a filterset
+// @Description  The user must own the filterset, or have the Manager privilege.
+// @Description  The UserManager privilege is required if the filterset is assigned to a user with a locked filter.
+// @Description  The filterset can not be deleted while assigned to users. Cascade deletes the filterset shares with groups.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Filterset
+// @Success      204  {string}  string  "No Content"
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      409  {string}  string  "the filterset is assigned to users"
+// @Failure      412  {string}  string  "Precondition Failed"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Router       /filtersets/{id}  [delete]
+//
+func DelFilterset(w http.ResponseWriter, r *http.Request) {
+	filtersets := tables.FiltersetFromCtx(r)
+	if len(filtersets) == 0 {
+		http.Error(w, http.StatusText(204), 204)
+		return
+	}
+	current := filtersets[0]
+	if !canWriteFilterset(w, r, current) {
+		return
+	}
+	if !checkIfMatch(w, r, filtersets) {
+		return
+	}
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if n, err := tables.CountFiltersetUsers(tx, current.ID, false); err != nil {
+			return fmt.Errorf("filterset users: %w", err)
+		} else if n > 0 {
+			return fmt.Errorf("%w: %d users", errFiltersetAssigned, n)
+		}
+		if err := deleteIfMatch(r, tx, "filtersets", current.ID, current.UpdatedAt, &[]tables.Filterset{}); err != nil {
+			return err
+		}
+		if err := tx.Where("filterset_id = ?", current.ID).Delete(&[]tables.FiltersetGroup{}).Error; err != nil {
+			return fmt.Errorf("filterset groups: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, errFiltersetAssigned) {
+		http.Error(w, fmt.Sprintf("%s: %s", http.StatusText(409), err), 409)
+		return
+	} else if err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("filtersets", current.ID)
+	jsonEncode(w, filtersets)
+}
+
+//
+// GetFiltersetGroups     godoc
+// @Summary      List the groups a filterset is shared with
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Group
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Router       /filtersets/{id}/groups  [get]
+//
+func GetFiltersetGroups(w http.ResponseWriter, r *http.Request) {
+	filtersets := tables.FiltersetFromCtx(r)
+	if len(filtersets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	groups, err := tables.GetFiltersetGroups(filtersets[0].ID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select groups: %s", err), 500)
+		return
+	}
+	jsonEncode(w, groups)
+}
+
+//
+// PostFiltersetGroup     godoc
+// @Summary      Share a filterset with a group
+// @Description  The user must own the filterset, or have the Manager privilege.
+// @Description  Users without the Manager privilege can only share with their own groups.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Success      200       {object}  tables.FiltersetGroup
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "Not Found"
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Param        id        path      int     true  "the index of the entry in database"
+// @Param        group_id  path      int     true  "the index of the group in database"
+// @Router       /filtersets/{id}/groups/{group_id}  [post]
+//
+func PostFiltersetGroup(w http.ResponseWriter, r *http.Request) {
+	filtersets := tables.FiltersetFromCtx(r)
+	if len(filtersets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := filtersets[0]
+	if !canWriteFilterset(w, r, current) {
+		return
+	}
+	groupID, ok := filtersetGroupID(w, r)
+	if !ok {
+		return
+	}
+	entry := tables.FiltersetGroup{
+		FiltersetID: current.ID,
+		GroupID:     groupID,
+	}
+	tx := db.DB().Clauses(clause.OnConflict{DoNothing: true})
+	if err := tx.Create(&entry).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	events.Publish(events.Update, "filtersets", current.ID, "groups")
+	jsonEncode(w, entry)
+}
+
+//
+// DelFiltersetGroup     godoc
+// @Summary      Stop sharing a filterset with a group
+// @Description  The user must own the filterset, or have the Manager privilege.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         filtersets
+// @Accept       json
+// @Produce      json
+// @Success      204       {string}  string  "No Content"
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "Not Found"
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Param        id        path      int     true  "the index of the entry in database"
+// @Param        group_id  path      int     true  "the index of the group in database"
+// @Router       /filtersets/{id}/groups/{group_id}  [delete]
+//
+func DelFiltersetGroup(w http.ResponseWriter, r *http.Request) {
+	filtersets := tables.FiltersetFromCtx(r)
+	if len(filtersets) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := filtersets[0]
+	if !canWriteFilterset(w, r, current) {
+		return
+	}
+	groupID, ok := filtersetGroupID(w, r)
+	if !ok {
+		return
+	}
+	tx := db.DB().Where("filterset_id = ? AND group_id = ?", current.ID, groupID)
+	if err := tx.Delete(&[]tables.FiltersetGroup{}).Error; err != nil {
+		http.Error(w, fmt.Sprintf("delete: %s", err), 500)
+		return
+	}
+	events.Publish(events.Update, "filtersets", current.ID, "groups")
+	w.WriteHeader(204)
+}
+
+var (
+	errFiltersetAssigned = errors.New("filterset is assigned to users")
+
+	// countFiltersetUsers is tables.CountFiltersetUsers, replaced in tests.
+	countFiltersetUsers = tables.CountFiltersetUsers
+)
+
+// canWriteFilterset writes a 403 response and returns false if the user
+// neither owns the filterset nor has the Manager privilege, or if the
+// filterset is assigned to a user with a locked filter and the user does
+// not have the UserManager privilege, as the locked user could otherwise
+// lift the lock by emptying the filterset.
+func canWriteFilterset(w http.ResponseWriter, r *http.Request, t tables.Filterset) bool {
+	user := auth.User(r)
+	if !t.IsOwnedBy(user.GetID()) && !authuser.IsManager(user) {
+		http.Error(w, fmt.Sprintf("%s: user does not own filterset %s", http.StatusText(403), t.Name), 403)
+		return false
+	}
+	if authuser.HasPrivilege(user, "UserManager") {
+		return true
+	}
+	n, err := countFiltersetUsers(db.DB(), t.ID, true)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select filterset users: %s", err), 500)
+		return false
+	}
+	if n > 0 {
+		http.Error(w, fmt.Sprintf("%s: filterset %s is locked on %d users, requires UserManager", http.StatusText(403), t.Name, n), 403)
+		return false
+	}
+	return true
+}
+
+// filtersetGroupID returns the group id path parameter, verifying the
+// group exists and, for users without the Manager privilege, that the user
+// is a member of the group.
+func filtersetGroupID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	var i int64
+	user := auth.User(r)
+	id, err := strconv.ParseUint(chi.URLParam(r, "group_id"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s: group %s", http.StatusText(404), chi.URLParam(r, "group_id")), 404)
+		return 0, false
+	}
+	tx := db.DB().Table("auth_group").Where("auth_group.id = ?", id)
+	if err := tx.Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select group: %s", err), 500)
+		return 0, false
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: group %d", http.StatusText(404), id), 404)
+		return 0, false
+	}
+	if authuser.IsManager(user) {
+		return uint(id), true
+	}
+	tx = db.DB().Table("auth_membership").Where("auth_membership.group_id = ? AND auth_membership.user_id = ?", id, user.GetID())
+	if err := tx.Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select membership: %s", err), 500)
+		return 0, false
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not a member of group %d", http.StatusText(403), id), 403)
+		return 0, false
+	}
+	return uint(id), true
+}
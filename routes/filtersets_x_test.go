@@ -0,0 +1,73 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCanWriteFilterset(t *testing.T) {
+	defer func(f func(*gorm.DB, uint, bool) (int64, error)) { countFiltersetUsers = f }(countFiltersetUsers)
+	tests := map[string]struct {
+		userID   string
+		privs    []string
+		locked   int64
+		expected bool
+		status   int
+	}{
+		"owner": {
+			userID:   "1",
+			expected: true,
+			status:   200,
+		},
+		"locked owner": {
+			userID:   "1",
+			locked:   1,
+			expected: false,
+			status:   403,
+		},
+		"locked owner with UserManager": {
+			userID:   "1",
+			privs:    []string{"UserManager"},
+			locked:   1,
+			expected: true,
+			status:   200,
+		},
+		"other user": {
+			userID:   "2",
+			expected: false,
+			status:   403,
+		},
+		"other user with UserManager": {
+			userID:   "2",
+			privs:    []string{"UserManager"},
+			expected: false,
+			status:   403,
+		},
+		"manager": {
+			userID:   "2",
+			privs:    []string{"Manager"},
+			locked:   1,
+			expected: true,
+			status:   200,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		countFiltersetUsers = func(tx *gorm.DB, id uint, locked bool) (int64, error) {
+			assert.True(t, locked)
+			return test.locked, nil
+		}
+		user := auth.NewUserInfo("u"+test.userID, test.userID, nil, auth.Extensions{authuser.XPrivileges: test.privs})
+		r := auth.RequestWithUser(user, httptest.NewRequest(http.MethodPatch, "/filtersets/1", nil))
+		w := httptest.NewRecorder()
+		assert.Equal(t, test.expected, canWriteFilterset(w, r, tables.Filterset{ID: 1, UserID: 1, Name: "fs1"}))
+		assert.Equal(t, test.status, w.Code)
+	}
+}
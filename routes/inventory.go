@@ -0,0 +1,471 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+)
+
+// replaceNodeEntries replaces the entries of the node in the table by the
+// data, a pointer to a slice of n entries, in a transaction. The deletion
+// events of the replaced entries and the creation events of the new entries
+// are published.
+func replaceNodeEntries(table, nodeID string, model, data interface{}, n int) error {
+	var before, after []uint
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(model).Where("node_id = ?", nodeID).Pluck("id", &before).Error; err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		if len(before) > 0 {
+			if err := tx.Where("id IN ?", before).Delete(model).Error; err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		if n > 0 {
+			if err := tx.Create(data).Error; err != nil {
+				return fmt.Errorf("insert: %w", err)
+			}
+		}
+		if err := tx.Model(model).Where("node_id = ?", nodeID).Pluck("id", &after).Error; err != nil {
+			return fmt.Errorf("select after insert: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	publishDeleted(table, before...)
+	publishUpserted(table, nil, after)
+	return nil
+}
+
+//
+// GetNodesIPs     godoc
+// @Summary      List the nodes network addresses
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example addr=192.168.%"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/ips  [get]
+//
+func GetNodesIPs(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("node_ip").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeIPs     godoc
+// @Summary      List the network addresses of a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/ips  [get]
+//
+func GetNodeIPs(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("node_ip").Request()
+	rq.Where("node_ip.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostNodesIPs     godoc
+// @Summary      Replace the network addresses inventory of the node
+// @Description  Requires node authentication.
+// @Description  The network addresses replace the network addresses inventory of the node atomically.
+// @Description  A network address is identified by its intf and addr.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Param        ips  body      []tables.NodeIP  true  "list of the node network addresses"
+// @Success      200  {array}   tables.NodeIP
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      422  {object}  validationErrorResponse
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /nodes/ips  [post]
+//
+func PostNodesIPs(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.NodeIP, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("node_ip"), entries)
+	keys := make(map[string]bool)
+	for i, e := range data {
+		k := e.Intf + "/" + e.Addr
+		if keys[k] {
+			errs = append(errs, db.FieldError{Index: i, Error: "duplicate entry"})
+		}
+		keys[k] = true
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if err := replaceNodeEntries("node_ip", nodeID, &tables.NodeIP{}, &data, len(data)); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
+
+//
+// GetNodesHBAs     godoc
+// @Summary      List the nodes host bus adapters
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example hba_type=fc"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/hbas  [get]
+//
+func GetNodesHBAs(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("node_hba").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeHBAs     godoc
+// @Summary      List the host bus adapters of a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/hbas  [get]
+//
+func GetNodeHBAs(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("node_hba").Request()
+	rq.Where("node_hba.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostNodesHBAs     godoc
+// @Summary      Replace the host bus adapters inventory of the node
+// @Description  Requires node authentication.
+// @Description  The host bus adapters replace the host bus adapters inventory of the node atomically.
+// @Description  A host bus adapter port is identified by its hba_id, the fibre channel WWN or iSCSI initiator name.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Param        hbas  body      []tables.NodeHBA  true  "list of the node host bus adapters"
+// @Success      200  {array}   tables.NodeHBA
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      422  {object}  validationErrorResponse
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /nodes/hbas  [post]
+//
+func PostNodesHBAs(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.NodeHBA, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("node_hba"), entries)
+	keys := make(map[string]bool)
+	for i, e := range data {
+		k := e.HBAID
+		if keys[k] {
+			errs = append(errs, db.FieldError{Index: i, Error: "duplicate entry"})
+		}
+		keys[k] = true
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if err := replaceNodeEntries("node_hba", nodeID, &tables.NodeHBA{}, &data, len(data)); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
+
+//
+// GetNodesTargets     godoc
+// @Summary      List the nodes storage targets
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example tgt_id=5006%"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/targets  [get]
+//
+func GetNodesTargets(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("stor_zone").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodeTargets     godoc
+// @Summary      List the storage targets of a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/targets  [get]
+//
+func GetNodeTargets(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("stor_zone").Request()
+	rq.Where("stor_zone.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostNodesTargets     godoc
+// @Summary      Replace the storage targets inventory of the node
+// @Description  Requires node authentication.
+// @Description  The storage targets replace the storage targets inventory of the node atomically.
+// @Description  A storage target is identified by the hba_id of the node port it is reachable from, and its tgt_id port WWN.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Param        targets  body      []tables.StorZone  true  "list of the node storage targets"
+// @Success      200  {array}   tables.StorZone
+// @Failure      403  {string}  string  "Forbidden"
+// @Failure      422  {object}  validationErrorResponse
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Router       /nodes/targets  [post]
+//
+func PostNodesTargets(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.StorZone, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	errs := validateEntries(db.Tab("stor_zone"), entries)
+	keys := make(map[string]bool)
+	for i, e := range data {
+		k := e.HBAID + "/" + e.TgtID
+		if keys[k] {
+			errs = append(errs, db.FieldError{Index: i, Error: "duplicate entry"})
+		}
+		keys[k] = true
+		data[i].NodeID = nodeID
+	}
+	if len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	if err := replaceNodeEntries("stor_zone", nodeID, &tables.StorZone{}, &data, len(data)); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
@@ -0,0 +1,114 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/jobs"
+)
+
+//
+// GetJob     godoc
+// @Summary      Show a background job
+// @Description  Show the job status, progress and result.
+// @Description  The users see the jobs they enqueued, the managers see all jobs.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Job
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /jobs/{id}  [get]
+//
+func GetJob(w http.ResponseWriter, r *http.Request) {
+	data := tables.JobFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// DelJob     godoc
+// @Summary      Cancel a background job
+// @Description  A queued job is canceled immediately. A running job is canceled by its worker, which can take a few seconds.
+// @Description  The users can cancel the jobs they enqueued, the managers can cancel all jobs.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Job
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      409  {string}  string  "the job is already finished"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Router       /jobs/{id}  [delete]
+//
+func DelJob(w http.ResponseWriter, r *http.Request) {
+	data := tables.JobFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := data[0]
+	if err := jobs.Cancel(current.ID); errors.Is(err, jobs.ErrNotCancelable) {
+		http.Error(w, fmt.Sprintf("%s: %s", http.StatusText(409), err), 409)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("cancel: %s", err), 500)
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	jsonEncode(w, []tables.Job{current})
+}
+
+//
+// PostJobRetry     godoc
+// @Summary      Retry a background job
+// @Description  Queue again a failed or canceled job, with its attempts reset.
+// @Description  The users can retry the jobs they enqueued, the managers can retry all jobs.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Job
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      409  {string}  string  "the job is not failed nor canceled"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Router       /jobs/{id}/retry  [post]
+//
+func PostJobRetry(w http.ResponseWriter, r *http.Request) {
+	data := tables.JobFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := data[0]
+	if err := jobs.Retry(current.ID); errors.Is(err, jobs.ErrNotRetryable) {
+		http.Error(w, fmt.Sprintf("%s: %s", http.StatusText(409), err), 409)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("retry: %s", err), 500)
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	jsonEncode(w, []tables.Job{current})
+}
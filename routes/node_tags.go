@@ -40,7 +40,7 @@ func GetNodeTag(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprint(err), 500)
 		return
 	}
-	jsonEncode(w, data)
+	jsonEncodeWithETag(w, r, data)
 }
 
 //
@@ -51,29 +51,34 @@ func GetNodeTag(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /nodes/tags  [get]
 //
 func GetNodesTags(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("node_tags").Request()
 	rq.AutoJoin("nodes")
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -84,15 +89,28 @@ func GetNodesTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /nodes/{id}/tags  [get]
 //
 func GetNodeTags(w http.ResponseWriter, r *http.Request) {
@@ -105,15 +123,7 @@ func GetNodeTags(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("node_tags").Request()
 	rq.AutoJoin("nodes")
 	rq.Where("nodes.id = ?", n.ID)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -124,15 +134,28 @@ func GetNodeTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /nodes/{id}/candidate_tags  [get]
 //
 func GetNodeCandidateTags(w http.ResponseWriter, r *http.Request) {
@@ -146,15 +169,7 @@ func GetNodeCandidateTags(w http.ResponseWriter, r *http.Request) {
 
 	rq := db.Tab("tags").Request()
 	rq.Where("tags.tag_id NOT IN (?)", exclude)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -165,15 +180,28 @@ func GetNodeCandidateTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /tags/{id}/nodes  [get]
 //
 func GetTagNodes(w http.ResponseWriter, r *http.Request) {
@@ -186,13 +214,65 @@ func GetTagNodes(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("nodes").Request()
 	rq.AutoJoin("tags")
 	rq.Where("node_tags.tag_id = ?", tag.TagID)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PatchNodeTag     godoc
+// @Summary      Patch a tag attachment to a node
+// @Description  The user must be responsible for the node, via app responsibles.
+// @Description  The attachment node_id and tag_id can not be modified.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         tags
+// @Tags         nodes
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.NodeTag
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /nodes/{id}/tags/{id}  [patch]
+// @Router       /nodes/tags/{id}  [patch]
+//
+func PatchNodeTag(w http.ResponseWriter, r *http.Request) {
+	attachs := tables.NodeTagFromCtx(r)
+	if len(attachs) == 0 {
+		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	current := attachs[0]
+	var i int64
+	rq := db.Tab("node_tags").Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where("node_tags.id = ?", current.ID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not responsible for the node", http.StatusText(403)), 403)
+		return
+	}
+	if !checkIfMatch(w, r, attachs) {
+		return
+	}
+	if !patchEntry(w, r, "node_tags", current, current.ID, db.ValidateWithReadOnly("node_id", "tag_id")) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.NodeTag{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
 	}
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/opensvc/collector-api/apiuser"
 	"github.com/opensvc/collector-api/auth"
 	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/dashboard"
 	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
 	"gorm.io/gorm"
@@ -24,28 +25,33 @@ import (
 // @Tags         nodes
 // @Accept       json
 // @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
 // @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
 // @Failure      500    {string}  string  "Internal Server Error"
 // @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param        limit    query     int       false  "number of objets to include in response"
 // @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router       /nodes  [get]
 //
 func GetNodes(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("nodes").Request()
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -54,6 +60,7 @@ func GetNodes(w http.ResponseWriter, r *http.Request) {
 // @Description  The app code of the nodes is forced to one the user is responsible of.
 // @Description  The team responsible of the nodes defaults to the user's primary group.
 // @Description  The user must be in the NodeManager privilege group.
+// @Description  The dashboard alerts of the nodes are refreshed in the background.
 // @Security     BasicAuth
 // @Security     BearerAuth
 // @Tags         nodes
@@ -61,6 +68,7 @@ func GetNodes(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        nodes  body      []tables.Node  true  "list of nodes to create or update"
 // @Success      200    {array}   tables.Node
+// @Failure      422    {object}  validationErrorResponse
 // @Failure      500      {string}  string    "Internal Server Error"
 // @Router       /nodes  [post]
 //
@@ -85,6 +93,13 @@ func PostNodes(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
 		return
 	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("nodes"), entries, "id", "node_id"); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
 	userPrimaryGroup := apiuser.PrimaryGroup(user)
 	userDefaultApp := apiuser.DefaultApp(user)
 	var myNodes *gorm.DB
@@ -148,13 +163,25 @@ func PostNodes(w http.ResponseWriter, r *http.Request) {
 		nodes[i] = n
 	}
 
+	before := make([]uint, len(nodes))
+	for i, n := range nodes {
+		before[i] = n.ID
+	}
 	tx := db.DB().Clauses(clause.OnConflict{UpdateAll: true})
 	if err := tx.Create(&nodes).Error; err != nil {
 		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
 		return
 	}
+	after := make([]uint, len(nodes))
+	for i, n := range nodes {
+		after[i] = n.ID
+	}
+	publishUpserted("nodes", before, after)
 	if err := jsonEncode(w, nodes); err != nil {
 		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
 	}
+	for _, id := range after {
+		refreshAlerts(r, dashboard.RefreshArgs{NodeID: id})
+	}
 }
@@ -7,10 +7,12 @@ import (
 	"net/http"
 
 	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/dashboard"
 	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
-	"github.com/opensvc/collector-api/xmap"
+	"github.com/opensvc/collector-api/events"
 	"github.com/shaj13/go-guardian/v2/auth"
+	"gorm.io/gorm"
 )
 
 //
@@ -23,9 +25,11 @@ import (
 // @Accept       json
 // @Produce      json
 // @Success      200  {array}   tables.Node
+// @Success      304  {string}  string  "Not Modified"
 // @Failure      404  {string}  string  "Not Found"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
 // @Router       /nodes/{id}  [get]
 //
 func GetNode(w http.ResponseWriter, r *http.Request) {
@@ -34,7 +38,7 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	jsonEncode(w, nodes)
+	jsonEncodeWithETag(w, r, nodes)
 }
 
 //
@@ -43,7 +47,8 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 // @Description  Delete a node by index, id or name.
 // @Description  The user must have the NodeManager privilege.
 // @Description  The user must be responsible for the node, via app responsibles.
-// @Description  Cascade delete on services instances, dashboard entries, checks, packages and patches.
+// @Description  Cascade delete on services instances, dashboard entries, compliance attachments and status, checks, packages, patches, network addresses, host bus adapters, storage targets, disks and service actions.
+// @Description  The disks information reported by the node are kept, without reporting node.
 // @Security     BasicAuth
 // @Security     BearerAuth
 // @Tags         nodes
@@ -51,8 +56,10 @@ func GetNode(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Success      200  {array}   tables.Node
 // @Success      204  {array}   string  "No Content"
+// @Failure      412  {string}  string  "Precondition Failed"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
 // @Router       /nodes/{id}  [delete]
 //
 func DelNode(w http.ResponseWriter, r *http.Request) {
@@ -66,10 +73,68 @@ func DelNode(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(202), 202)
 		return
 	}
-	if err := db.DB().Delete(&nodes).Error; err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	if !checkIfMatch(w, r, nodes) {
 		return
 	}
+	cascade := []struct {
+		name  string
+		table string
+		entry interface{}
+	}{
+		{"dashboard entries", "dashboard", &tables.Dashboard{}},
+		{"compliance rulesets attachments", "comp_rulesets_nodes", &tables.CompRulesetNode{}},
+		{"compliance modulesets attachments", "comp_modulesets_nodes", &tables.CompModulesetNode{}},
+		{"compliance status", "comp_status", &tables.CompStatus{}},
+		{"checks", "checks_live", &tables.Check{}},
+		{"checks thresholds", "checks_thresholds", &tables.CheckThreshold{}},
+		{"packages", "node_pkg", &tables.NodePackage{}},
+		{"patches", "node_patches", &tables.NodePatch{}},
+		{"network addresses", "node_ip", &tables.NodeIP{}},
+		{"host bus adapters", "node_hba", &tables.NodeHBA{}},
+		{"storage targets", "stor_zone", &tables.StorZone{}},
+		{"disks", "svcdisks", &tables.ServiceDisk{}},
+		{"service actions", "svcactions", &tables.SvcAction{}},
+	}
+	deleted := make(map[string][]uint)
+	released := make([]uint, 0)
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := deleteIfMatch(r, tx, "nodes", nodes[0].ID, nodes[0].UpdatedAt, &tables.Node{}); err != nil {
+			return err
+		}
+		for _, c := range cascade {
+			ids := make([]uint, 0)
+			if err := tx.Model(c.entry).Where("node_id = ?", nodes[0].NodeID).Pluck("id", &ids).Error; err != nil {
+				return fmt.Errorf("select %s: %w", c.name, err)
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Where("id IN (?)", ids).Delete(c.entry).Error; err != nil {
+				return fmt.Errorf("delete %s: %w", c.name, err)
+			}
+			deleted[c.table] = ids
+		}
+		// release the disks information reported by the node, so
+		// another node can report them
+		if err := tx.Model(&tables.DiskInfo{}).Where("node_id = ?", nodes[0].NodeID).Pluck("id", &released).Error; err != nil {
+			return fmt.Errorf("disks information: %w", err)
+		}
+		if err := tx.Model(&tables.DiskInfo{}).Where("node_id = ?", nodes[0].NodeID).Update("node_id", "").Error; err != nil {
+			return fmt.Errorf("disks information: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("nodes", nodes[0].ID)
+	for _, c := range cascade {
+		publishDeleted(c.table, deleted[c.table]...)
+	}
+	for _, id := range released {
+		events.Publish(events.Update, "diskinfo", id, "node_id")
+	}
 	jsonEncode(w, nodes)
 }
 
@@ -77,6 +142,7 @@ func DelNode(w http.ResponseWriter, r *http.Request) {
 // PostNode	godoc
 // @Summary      Update a node
 // @Description  The user must be in the NodeManager privilege group.
+// @Description  The dashboard alerts of the node are refreshed in the background.
 // @Security     BasicAuth
 // @Security     BearerAuth
 // @Tags         nodes
@@ -87,7 +153,10 @@ func DelNode(w http.ResponseWriter, r *http.Request) {
 // @Success      200    {array}   tables.Node
 // @Failure      401    {string}  string  "missing NodeManager privilege"
 // @Failure      404    {string}  string  "the entry to update does not exist"
+// @Failure      412    {string}  string  "the entry was modified since the client read it"
+// @Failure      422    {object}  validationErrorResponse
 // @Failure      500    {string}  string
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
 // @Router       /nodes/{id}  [post]
 //
 func PostNode(w http.ResponseWriter, r *http.Request) {
@@ -106,8 +175,78 @@ func PostNode(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
 		return
 	}
-	if _, ok := data["id"]; ok {
-		delete(data, "id")
+	if errs := db.Tab("nodes").Validate(data, db.ValidateWithKeys("id")); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	// the clients may send the entry back as read, the node is
+	// identified by the path
+	delete(data, "id")
+	currents := tables.NodeFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	var i int64
+	rq := db.Tab("nodes").Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where("nodes.id = ?", current.ID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("user is not responsible for node %s in app %s", current.Nodename, current.App), 500)
+		return
+	}
+	if !checkIfMatch(w, r, currents) {
+		return
+	}
+	if err := db.Tab("nodes").Update(current.ID, data, ifMatchUpdateOpts(r, current.UpdatedAt)...); err != nil {
+		writeError(w, "update", err)
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Node{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+	refreshAlerts(r, dashboard.RefreshArgs{NodeID: current.ID})
+}
+
+//
+// PatchNode	godoc
+// @Summary      Patch a node
+// @Description  The user must be in the NodeManager privilege group.
+// @Description  The user must be responsible for the node, via app responsibles.
+// @Description  The dashboard alerts of the node are refreshed in the background.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Node
+// @Failure      401       {string}  string  "missing NodeManager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /nodes/{id}  [patch]
+//
+func PatchNode(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.HasPrivilege(user, "NodeManager") {
+		authuser.PrivError(w, "NodeManager")
+		return
 	}
 	currents := tables.NodeFromCtx(r)
 	if len(currents) == 0 {
@@ -125,18 +264,19 @@ func PostNode(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("user is not responsible for node %s in app %s", current.Nodename, current.App), 500)
 		return
 	}
-	props := xmap.Keys(data)
-	if err := db.DB().Table("nodes").Select(props).Where("id = ?", current.ID).Updates(data).Error; err != nil {
-		http.Error(w, fmt.Sprintf("update: %s", err), 500)
+	if !checkIfMatch(w, r, currents) {
+		return
+	}
+	if !patchEntry(w, r, "nodes", current, current.ID) {
 		return
 	}
 	if err := db.DB().Take(&current).Error; err != nil {
 		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
 		return
 	}
-	if err := jsonEncode(w, []tables.Node{current}); err != nil {
+	if err := jsonEncodeWithETag(w, r, []tables.Node{current}); err != nil {
 		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
 	}
-	// enqueue dashboard alerts refresh
+	refreshAlerts(r, dashboard.RefreshArgs{NodeID: current.ID})
 }
@@ -0,0 +1,299 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/version"
+	"gorm.io/gorm"
+)
+
+type (
+	// packageNode is a node with a version of a package.
+	packageNode struct {
+		NodeID   string `json:"node_id"`
+		Nodename string `json:"nodename"`
+		Name     string `json:"pkg_name"`
+		Version  string `json:"pkg_version"`
+		Arch     string `json:"pkg_arch"`
+	}
+)
+
+// packageKey returns the identity of the package among the packages of
+// its node.
+func packageKey(p tables.NodePackage) string {
+	return p.Name + "/" + p.Version + "/" + p.Arch
+}
+
+//
+// GetPackages     godoc
+// @Summary      List the packages installed on nodes
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /packages  [get]
+//
+func GetPackages(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("node_pkg").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodePackages     godoc
+// @Summary      List the packages installed on a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         packages
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/packages  [get]
+//
+func GetNodePackages(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("node_pkg").Request()
+	rq.Where("node_pkg.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetPackagesNodes     godoc
+// @Summary      List the nodes with a version of a package
+// @Description  List the nodes of the apps published to the user with the package installed in a version satisfying the comparison, for example below a version with op=lt.
+// @Description  The versions are compared the dpkg way: epoch, then upstream version and revision compared by numeric and non-numeric segments, where 1.10 is greater than 1.9 and 1.0~rc1 lower than 1.0.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Param        name     query     string  true   "the package name"
+// @Param        version  query     string  true   "the version to compare the installed versions to"
+// @Param        op       query     string  false  "the comparison operator (default lt)"  Enums(lt, le, eq, ne, ge, gt)
+// @Param        arch     query     string  false  "the package architecture"
+// @Success      200      {array}   packageNode
+// @Failure      400      {string}  string  "Bad Request"
+// @Failure      500      {string}  string  "Internal Server Error"
+// @Router       /packages/nodes  [get]
+//
+func GetPackagesNodes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name := q.Get("name")
+	ref := q.Get("version")
+	op := version.Op(q.Get("op"))
+	if op == "" {
+		op = version.OpLT
+	}
+	if name == "" || ref == "" {
+		http.Error(w, fmt.Sprintf("%s: name and version are required", http.StatusText(400)), 400)
+		return
+	}
+	if !version.ValidOp(op) {
+		http.Error(w, fmt.Sprintf("%s: unknown op %s", http.StatusText(400), op), 400)
+		return
+	}
+	pkgs := make([]tables.NodePackage, 0)
+	tx := db.Tab("node_pkg").Request(
+		db.TableRequestWithFilters(false),
+		db.TableRequestWithPaging(false),
+	).TX(r)
+	tx = tx.Where("node_pkg.pkg_name = ?", name)
+	if arch := q.Get("arch"); arch != "" {
+		tx = tx.Where("node_pkg.pkg_arch = ?", arch)
+	}
+	if err := tx.Find(&pkgs).Error; err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	l := make([]packageNode, 0)
+	nodeIDs := make([]string, 0)
+	for _, p := range pkgs {
+		if !version.Match(p.Version, op, ref) {
+			continue
+		}
+		l = append(l, packageNode{NodeID: p.NodeID, Name: p.Name, Version: p.Version, Arch: p.Arch})
+		nodeIDs = append(nodeIDs, p.NodeID)
+	}
+	if len(nodeIDs) > 0 {
+		nodes := make([]tables.Node, 0)
+		if err := db.DB().Where("node_id IN ?", nodeIDs).Find(&nodes).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select nodes: %s", err), 500)
+			return
+		}
+		nodenames := make(map[string]string)
+		for _, n := range nodes {
+			nodenames[n.NodeID] = n.Nodename
+		}
+		for i := range l {
+			l[i].Nodename = nodenames[l[i].NodeID]
+		}
+	}
+	jsonEncode(w, l)
+}
+
+//
+// PostPackages     godoc
+// @Summary      Replace the packages inventory of the node
+// @Description  Requires node authentication.
+// @Description  The packages replace the packages inventory of the node atomically: the packages not listed are removed.
+// @Description  A package is identified by its pkg_name, pkg_version and pkg_arch.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         packages
+// @Accept       json
+// @Produce      json
+// @Param        packages  body      []tables.NodePackage  true  "list of the installed packages"
+// @Success      200       {array}   tables.NodePackage
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Router       /packages  [post]
+//
+func PostPackages(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.NodePackage, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if errs := validateEntries(db.Tab("node_pkg"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	// the last of the duplicate packages wins
+	posted := make(map[string]tables.NodePackage)
+	keys := make([]string, 0, len(data))
+	for _, p := range data {
+		p.NodeID = nodeID
+		k := packageKey(p)
+		if _, ok := posted[k]; !ok {
+			keys = append(keys, k)
+		}
+		posted[k] = p
+	}
+	var created, updated, deleted []uint
+	err = db.DB().Transaction(func(tx *gorm.DB) error {
+		existing := make([]tables.NodePackage, 0)
+		if err := tx.Where("node_id = ?", nodeID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		current := make(map[string]tables.NodePackage)
+		for _, p := range existing {
+			k := packageKey(p)
+			if _, ok := posted[k]; !ok {
+				deleted = append(deleted, p.ID)
+				continue
+			}
+			current[k] = p
+		}
+		if len(deleted) > 0 {
+			if err := tx.Where("id IN ?", deleted).Delete(&tables.NodePackage{}).Error; err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		for _, k := range keys {
+			p := posted[k]
+			c, ok := current[k]
+			switch {
+			case !ok:
+				if err := tx.Create(&p).Error; err != nil {
+					return fmt.Errorf("insert %s: %w", k, err)
+				}
+				created = append(created, p.ID)
+			case c.Type != p.Type || c.Sig != p.Sig || !c.InstallDate.Equal(p.InstallDate):
+				err := tx.Model(&c).Updates(map[string]interface{}{
+					"pkg_type":         p.Type,
+					"pkg_sig":          p.Sig,
+					"pkg_install_date": p.InstallDate,
+				}).Error
+				if err != nil {
+					return fmt.Errorf("update %s: %w", k, err)
+				}
+				updated = append(updated, c.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	publishDeleted("node_pkg", deleted...)
+	publishUpserted("node_pkg", updated, updated)
+	publishUpserted("node_pkg", nil, created)
+	data = make([]tables.NodePackage, 0)
+	if err := db.DB().Where("node_id = ?", nodeID).Order("pkg_name, pkg_version, pkg_arch").Find(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after replace: %s", err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/funcopt"
+	"github.com/opensvc/collector-api/jsonpatch"
+)
+
+// patchEntry applies the request body patch to the json representation of
+// the current table entry, validates the modified properties and updates
+// them in the database.
+//
+// The request Content-Type selects the patch format:
+//
+//	application/merge-patch+json  RFC 7396 JSON Merge Patch
+//	application/json-patch+json   RFC 6902 JSON Patch
+//
+// The update is conditional to the entry updated_at being unchanged if the
+// request has an If-Match precondition.
+//
+// It responds the error and returns false if the entry was not updated.
+func patchEntry(w http.ResponseWriter, r *http.Request, table string, current interface{}, id uint, opts ...funcopt.O) bool {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return false
+	}
+	var doc interface{}
+	if b, err := json.Marshal(current); err != nil {
+		http.Error(w, fmt.Sprintf("marshal current: %s", err), 500)
+		return false
+	} else if err := json.Unmarshal(b, &doc); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal current: %s", err), 500)
+		return false
+	}
+	var patched interface{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case jsonpatch.MergePatchContentType:
+		var patch interface{}
+		if err := json.Unmarshal(body, &patch); err != nil {
+			http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 400)
+			return false
+		}
+		patched = jsonpatch.MergePatch(doc, patch)
+	case jsonpatch.PatchContentType:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 400)
+			return false
+		}
+		if patched, err = patch.Apply(doc); errors.Is(err, jsonpatch.ErrTestFailed) {
+			http.Error(w, fmt.Sprintf("apply patch: %s", err), 409)
+			return false
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("apply patch: %s", err), 422)
+			return false
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unsupported patch media type %s, use %s or %s", mediaType, jsonpatch.MergePatchContentType, jsonpatch.PatchContentType), 415)
+		return false
+	}
+	changes, err := diffEntry(doc, patched)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("apply patch: %s", err), 422)
+		return false
+	}
+	if len(changes) == 0 {
+		return true
+	}
+	if errs := db.Tab(table).Validate(changes, opts...); len(errs) > 0 {
+		validationError(w, errs)
+		return false
+	}
+	var updateOpts []funcopt.O
+	if hasIfMatch(r) {
+		s, _ := doc.(map[string]interface{})["updated_at"].(string)
+		updatedAt, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("entry version: %s", err), 500)
+			return false
+		}
+		updateOpts = ifMatchUpdateOpts(r, updatedAt)
+	}
+	if err := db.Tab(table).Update(id, changes, updateOpts...); err != nil {
+		writeError(w, "update", err)
+		return false
+	}
+	return true
+}
+
+// diffEntry returns the properties modified by a patch, with a nil value
+// for the removed properties.
+func diffEntry(before, after interface{}) (map[string]interface{}, error) {
+	b := before.(map[string]interface{})
+	a, ok := after.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the patched document is not an object")
+	}
+	changes := make(map[string]interface{})
+	for k, v := range a {
+		if !reflect.DeepEqual(b[k], v) {
+			changes[k] = v
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok && b[k] != nil {
+			changes[k] = nil
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,209 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"gorm.io/gorm"
+)
+
+// patchKey returns the identity of the patch among the patches of its
+// node.
+func patchKey(p tables.NodePatch) string {
+	return p.Num + "/" + p.Rev
+}
+
+//
+// GetPatches     godoc
+// @Summary      List the patches installed on nodes
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         patches
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /patches  [get]
+//
+func GetPatches(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("node_patches").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetNodePatches     godoc
+// @Summary      List the patches installed on a node
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         patches
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /nodes/{id}/patches  [get]
+//
+func GetNodePatches(w http.ResponseWriter, r *http.Request) {
+	data := tables.NodeFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	n := data[0]
+	rq := db.Tab("node_patches").Request()
+	rq.Where("node_patches.node_id = ?", n.NodeID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostPatches     godoc
+// @Summary      Replace the patches inventory of the node
+// @Description  Requires node authentication.
+// @Description  The patches replace the patches inventory of the node atomically: the patches not listed are removed.
+// @Description  A patch is identified by its patch_num and patch_rev.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         patches
+// @Accept       json
+// @Produce      json
+// @Param        patches  body      []tables.NodePatch  true  "list of the installed patches"
+// @Success      200       {array}   tables.NodePatch
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Router       /patches  [post]
+//
+func PostPatches(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := make([]tables.NodePatch, 0)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	entries, err := decodeEntries(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if errs := validateEntries(db.Tab("node_patches"), entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	// the last of the duplicate patches wins
+	posted := make(map[string]tables.NodePatch)
+	keys := make([]string, 0, len(data))
+	for _, p := range data {
+		p.NodeID = nodeID
+		k := patchKey(p)
+		if _, ok := posted[k]; !ok {
+			keys = append(keys, k)
+		}
+		posted[k] = p
+	}
+	var created, updated, deleted []uint
+	err = db.DB().Transaction(func(tx *gorm.DB) error {
+		existing := make([]tables.NodePatch, 0)
+		if err := tx.Where("node_id = ?", nodeID).Find(&existing).Error; err != nil {
+			return fmt.Errorf("select: %w", err)
+		}
+		current := make(map[string]tables.NodePatch)
+		for _, p := range existing {
+			k := patchKey(p)
+			if _, ok := posted[k]; !ok {
+				deleted = append(deleted, p.ID)
+				continue
+			}
+			current[k] = p
+		}
+		if len(deleted) > 0 {
+			if err := tx.Where("id IN ?", deleted).Delete(&tables.NodePatch{}).Error; err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+		for _, k := range keys {
+			p := posted[k]
+			c, ok := current[k]
+			switch {
+			case !ok:
+				if err := tx.Create(&p).Error; err != nil {
+					return fmt.Errorf("insert %s: %w", k, err)
+				}
+				created = append(created, p.ID)
+			case !c.InstallDate.Equal(p.InstallDate):
+				err := tx.Model(&c).Update("patch_install_date", p.InstallDate).Error
+				if err != nil {
+					return fmt.Errorf("update %s: %w", k, err)
+				}
+				updated = append(updated, c.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	publishDeleted("node_patches", deleted...)
+	publishUpserted("node_patches", updated, updated)
+	publishUpserted("node_patches", nil, created)
+	data = make([]tables.NodePatch, 0)
+	if err := db.DB().Where("node_id = ?", nodeID).Order("patch_num, patch_rev").Find(&data).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after replace: %s", err), 500)
+		return
+	}
+	jsonEncode(w, data)
+}
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/db"
+)
+
+//
+// PostQuery     godoc
+// @Summary      Query table entries
+// @Description  Select properties of the base table entries and of any table joinable to the base table,
+// @Description  filtered by a tree of property conditions combined with and, or and not.
+// @Description  The read ACL of the base table applies.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         query
+// @Accept       json
+// @Produce      json
+// @Param        query  body      db.Query  true  "query"
+// @Success      200    {object}  db.TableResponse
+// @Failure      400    {string}  string  "Bad Request"
+// @Failure      500    {string}  string  "Internal Server Error"
+// @Router       /query  [post]
+//
+func PostQuery(w http.ResponseWriter, r *http.Request) {
+	var q db.Query
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &q); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 400)
+		return
+	}
+	td, err := q.MakeTableResponse(auth.User(r))
+	if errors.Is(err, db.ErrBadRequest) {
+		http.Error(w, fmt.Sprint(err), 400)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if err := jsonEncode(w, td); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+}
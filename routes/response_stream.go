@@ -0,0 +1,207 @@
+package routes
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/opensvc/collector-api/db"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	csvContentType    = "text/csv"
+
+	// streamFlushRows and streamFlushInterval bound the rows and the time
+	// a row writer buffers before sending them to the client.
+	streamFlushRows     = 500
+	streamFlushInterval = time.Second
+)
+
+type (
+	// rowWriterState records if a row writer has started the response,
+	// after which errors can no longer be reported with a status code,
+	// and the rows written since the last flush.
+	rowWriterState struct {
+		started bool
+		pending int
+		flushed time.Time
+	}
+
+	streamRowWriter interface {
+		db.RowWriter
+		Started() bool
+
+		// Flush sends the buffered rows to the client.
+		Flush() error
+	}
+
+	// ndjsonRowWriter writes each table row as a json object on its own
+	// line.
+	ndjsonRowWriter struct {
+		rowWriterState
+		w       http.ResponseWriter
+		enc     *json.Encoder
+		columns []string
+	}
+
+	// csvRowWriter writes the table rows as csv records, preceded by a
+	// header record with the column names.
+	csvRowWriter struct {
+		rowWriterState
+		w   http.ResponseWriter
+		csv *csv.Writer
+	}
+)
+
+// streamContentType returns the media type of the streamed encoding
+// requested by the format query parameter or, if not set, by the Accept
+// header. It returns an empty string if the client wants the default
+// json document.
+func streamContentType(r *http.Request) string {
+	switch r.URL.Query().Get("format") {
+	case "ndjson":
+		return ndjsonContentType
+	case "csv":
+		return csvContentType
+	case "json":
+		return ""
+	}
+	for _, s := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(s))
+		switch mediaType {
+		case ndjsonContentType, csvContentType:
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// StreamTimeout returns a middleware cancelling the request context after
+// the timeout, except for the table responses streamed as ndjson or csv,
+// whose duration grows with the number of rows.
+func StreamTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withTimeout := middleware.Timeout(timeout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet && streamContentType(r) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			withTimeout.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (t rowWriterState) Started() bool {
+	return t.started
+}
+
+// flushDue counts a written row, and returns true if the buffered rows
+// are to be sent to the client.
+func (t *rowWriterState) flushDue() bool {
+	t.pending++
+	if t.pending < streamFlushRows && time.Since(t.flushed) < streamFlushInterval {
+		return false
+	}
+	t.pending = 0
+	t.flushed = time.Now()
+	return true
+}
+
+func newRowWriter(w http.ResponseWriter, contentType string) streamRowWriter {
+	if contentType == csvContentType {
+		return newCSVRowWriter(w)
+	}
+	return newNDJSONRowWriter(w)
+}
+
+func newNDJSONRowWriter(w http.ResponseWriter) *ndjsonRowWriter {
+	t := &ndjsonRowWriter{w: w, enc: json.NewEncoder(w)}
+	t.flushed = time.Now()
+	return t
+}
+
+func (t *ndjsonRowWriter) WriteHeader(columns []string) error {
+	t.started = true
+	t.columns = columns
+	t.w.Header().Set("Content-Type", ndjsonContentType)
+	return nil
+}
+
+func (t *ndjsonRowWriter) WriteRow(values []interface{}) error {
+	m := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		m[t.columns[i]] = v
+	}
+	if err := t.enc.Encode(m); err != nil {
+		return err
+	}
+	if t.flushDue() {
+		flush(t.w)
+	}
+	return nil
+}
+
+func (t *ndjsonRowWriter) Flush() error {
+	flush(t.w)
+	return nil
+}
+
+func newCSVRowWriter(w http.ResponseWriter) *csvRowWriter {
+	t := &csvRowWriter{w: w, csv: csv.NewWriter(w)}
+	t.flushed = time.Now()
+	return t
+}
+
+func (t *csvRowWriter) WriteHeader(columns []string) error {
+	t.started = true
+	t.w.Header().Set("Content-Type", csvContentType+"; charset=utf-8")
+	return t.write(columns)
+}
+
+func (t *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch o := v.(type) {
+		case nil:
+		case time.Time:
+			record[i] = o.Format("2006-01-02 15:04:05")
+		default:
+			record[i] = fmt.Sprint(o)
+		}
+	}
+	return t.write(record)
+}
+
+func (t *csvRowWriter) write(record []string) error {
+	if err := t.csv.Write(record); err != nil {
+		return err
+	}
+	if !t.flushDue() {
+		return nil
+	}
+	return t.Flush()
+}
+
+func (t *csvRowWriter) Flush() error {
+	t.csv.Flush()
+	if err := t.csv.Error(); err != nil {
+		return err
+	}
+	flush(t.w)
+	return nil
+}
+
+// flush sends the buffered response data to the client, if the response
+// writer supports it.
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,149 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamContentType(t *testing.T) {
+	tests := map[string]struct {
+		format   string
+		accept   string
+		expected string
+	}{
+		"default": {
+			expected: "",
+		},
+		"format ndjson": {
+			format:   "ndjson",
+			expected: ndjsonContentType,
+		},
+		"format csv": {
+			format:   "csv",
+			accept:   ndjsonContentType,
+			expected: csvContentType,
+		},
+		"format json": {
+			format:   "json",
+			accept:   csvContentType,
+			expected: "",
+		},
+		"accept csv": {
+			accept:   "application/json;q=0.5, text/csv; charset=utf-8",
+			expected: csvContentType,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		r := httptest.NewRequest(http.MethodGet, "/nodes?format="+test.format, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		assert.Equal(t, test.expected, streamContentType(r))
+	}
+}
+
+func TestCSVRowWriter(t *testing.T) {
+	date := time.Date(2022, 6, 15, 10, 30, 5, 0, time.UTC)
+	tests := map[string]struct {
+		values   []interface{}
+		expected string
+	}{
+		"plain": {
+			values:   []interface{}{"n1", 2, true},
+			expected: "n1,2,true\n",
+		},
+		"nil": {
+			values:   []interface{}{"n1", nil, 3},
+			expected: "n1,,3\n",
+		},
+		"time": {
+			values:   []interface{}{"n1", date, 3},
+			expected: "n1,2022-06-15 10:30:05,3\n",
+		},
+		"escaped": {
+			values:   []interface{}{"a,b", `say "hi"`, "l1\nl2"},
+			expected: "\"a,b\",\"say \"\"hi\"\"\",\"l1\nl2\"\n",
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		w := httptest.NewRecorder()
+		rw := newCSVRowWriter(w)
+		assert.Nil(t, rw.WriteHeader([]string{"a", "b", "c"}))
+		assert.True(t, rw.Started())
+		assert.Nil(t, rw.WriteRow(test.values))
+		assert.Nil(t, rw.Flush())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "a,b,c\n"+test.expected, w.Body.String())
+	}
+}
+
+func TestNDJSONRowWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	rw := newNDJSONRowWriter(w)
+	assert.False(t, rw.Started())
+	assert.Nil(t, rw.WriteHeader([]string{"nodename", "cpu_cores", "nodes.app"}))
+	assert.True(t, rw.Started())
+	assert.Nil(t, rw.WriteRow([]interface{}{"n1", 4, "app1"}))
+	assert.Nil(t, rw.WriteRow([]interface{}{"n2", nil, "app2"}))
+	assert.Nil(t, rw.Flush())
+	assert.Equal(t, ndjsonContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, ""+
+		`{"cpu_cores":4,"nodename":"n1","nodes.app":"app1"}`+"\n"+
+		`{"cpu_cores":null,"nodename":"n2","nodes.app":"app2"}`+"\n",
+		w.Body.String())
+}
+
+func TestRowWriterFlushDue(t *testing.T) {
+	s := rowWriterState{flushed: time.Now()}
+	for i := 1; i < streamFlushRows; i++ {
+		assert.False(t, s.flushDue(), "row %d", i)
+	}
+	assert.True(t, s.flushDue())
+	assert.False(t, s.flushDue())
+	s.flushed = time.Now().Add(-streamFlushInterval)
+	assert.True(t, s.flushDue())
+}
+
+func TestStreamTimeout(t *testing.T) {
+	tests := map[string]struct {
+		method   string
+		url      string
+		deadline bool
+	}{
+		"json": {
+			method:   http.MethodGet,
+			url:      "/nodes",
+			deadline: true,
+		},
+		"ndjson": {
+			method:   http.MethodGet,
+			url:      "/nodes?format=ndjson",
+			deadline: false,
+		},
+		"csv": {
+			method:   http.MethodGet,
+			url:      "/nodes?format=csv",
+			deadline: false,
+		},
+		"post": {
+			method:   http.MethodPost,
+			url:      "/nodes?format=csv",
+			deadline: true,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		var deadline bool
+		h := StreamTimeout(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, deadline = r.Context().Deadline()
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.url, nil))
+		assert.Equal(t, test.deadline, deadline)
+	}
+}
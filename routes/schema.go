@@ -0,0 +1,57 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+//
+// GetSchemaJoins     godoc
+// @Summary      Show the table join graph
+// @Description  List the joins the table requests use to reach the properties of other tables,
+// @Description  and the preferred routes used instead of the shortest join paths.
+// @Description  A table can be joined again under an alias with the "table@alias.property" syntax.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         schema
+// @Produce      json
+// @Success      200  {object}  db.JoinGraph
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /schema/joins  [get]
+//
+func GetSchemaJoins(w http.ResponseWriter, r *http.Request) {
+	if err := jsonEncodeWithETag(w, r, db.Joins()); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+}
+
+//
+// GetSchema     godoc
+// @Summary      Show the registered tables schema
+// @Description  List the tables with their properties and the tables they have a join with.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         schema
+// @Produce      json
+// @Success      200  {array}   db.TableSchema
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /schema  [get]
+//
+func GetSchema(w http.ResponseWriter, r *http.Request) {
+	l, err := db.Schema()
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, l); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+//
+// GetSearch     godoc
+// @Summary      Search nodes, services, tags and apps
+// @Description  Search the entries having words starting with all the searched words in their full-text indexed properties.
+// @Description  The hits are ranked by relevance, and limited to the entries the user can read.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         search
+// @Produce      json
+// @Success      200    {object}  db.SearchResponse
+// @Failure      400    {string}  string  "Bad Request"
+// @Failure      500    {string}  string  "Internal Server Error"
+// @Param        q      query     string  true   "the words to search"
+// @Param        types  query     string  false  "hit types to search (comma separated, default all)"  Enums(app, cluster, moduleset, node, ruleset, service, tag)
+// @Param        limit  query     int     false  "maximum number of hits to include in response (default 20, max 100)"
+// @Router       /search  [get]
+//
+func GetSearch(w http.ResponseWriter, r *http.Request) {
+	resp, err := db.Search(r)
+	if errors.Is(err, db.ErrBadRequest) {
+		http.Error(w, fmt.Sprint(err), 400)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if err := jsonEncode(w, resp); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+}
@@ -1,7 +1,6 @@
 package routes
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/opensvc/collector-api/db"
@@ -15,26 +14,31 @@ import (
 // @Tags      services
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /services  [get]
 //
 func GetServices(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("services").Request()
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
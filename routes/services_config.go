@@ -0,0 +1,381 @@
+package routes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/diff"
+	"github.com/opensvc/collector-api/events"
+	"github.com/opensvc/collector-api/svcconfig"
+	"gorm.io/gorm"
+)
+
+// serviceConfigVersion returns the configuration version of the service
+// with the index s in database, or the latest version if s is empty. It
+// returns false if the version does not exist.
+func serviceConfigVersion(svcID, s string) (tables.ServiceConfig, bool, error) {
+	data := make([]tables.ServiceConfig, 0)
+	tx := db.DB().Where("svc_id = ?", svcID)
+	if s != "" {
+		id, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return tables.ServiceConfig{}, false, nil
+		}
+		tx = tx.Where("id = ?", id)
+	} else {
+		tx = tx.Order("id DESC").Limit(1)
+	}
+	if err := tx.Find(&data).Error; err != nil || len(data) == 0 {
+		return tables.ServiceConfig{}, false, err
+	}
+	return data[0], true, nil
+}
+
+// serviceConfigLabel returns the label of a configuration version in a
+// unified diff.
+func serviceConfigLabel(svc tables.Service, v tables.ServiceConfig) string {
+	if v.ID == 0 {
+		return "/dev/null"
+	}
+	return fmt.Sprintf("%s.conf@%d\t%s", svc.Svcname, v.ID, v.CreatedAt.Format(time.RFC3339))
+}
+
+//
+// GetServiceConfig     godoc
+// @Summary      Show the configuration of a service
+// @Description  Respond the text of the last configuration pushed by a node of the service.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Produce      plain
+// @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Success      200  {string}  string  "the configuration text"
+// @Failure      404  {string}  string  "Not Found"
+// @Router       /services/{id}/config  [get]
+//
+func GetServiceConfig(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	if svc.SvcConfig == "" {
+		http.Error(w, fmt.Sprintf("%s: service %s has no configuration", http.StatusText(404), svc.Svcname), 404)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, svc.SvcConfig)
+}
+
+//
+// PostServiceConfig     godoc
+// @Summary      Push the configuration of a service
+// @Description  Requires node authentication, from a node running the service.
+// @Description  The request body is the configuration text. A new version is stored if the configuration changed since the last version, and the configuration keywords are parsed.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       plain
+// @Produce      json
+// @Param        id      path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        config  body      string  true  "the configuration text"
+// @Success      200     {object}  tables.ServiceConfig
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      404     {string}  string  "Not Found"
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /services/{id}/config  [post]
+//
+func PostServiceConfig(w http.ResponseWriter, r *http.Request) {
+	nodeID, ok := callerNodeID(w, r)
+	if !ok {
+		return
+	}
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	if _, ok, err := nodeServiceID(nodeID, svc.SvcID); err != nil {
+		http.Error(w, fmt.Sprintf("select service: %s", err), 500)
+		return
+	} else if !ok {
+		http.Error(w, fmt.Sprintf("%s: service %s is not running on the node", http.StatusText(403), svc.Svcname), 403)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	latest, ok, err := serviceConfigVersion(svc.SvcID, "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select last version: %s", err), 500)
+		return
+	}
+	if ok && latest.Hash == hash {
+		jsonEncode(w, latest)
+		return
+	}
+	version := tables.ServiceConfig{SvcID: svc.SvcID, NodeID: nodeID, Hash: hash, Text: string(body)}
+	keywords := make([]tables.ServiceConfigKeyword, 0)
+	for _, kw := range svcconfig.Parse(version.Text) {
+		keywords = append(keywords, tables.ServiceConfigKeyword{
+			SvcID:   svc.SvcID,
+			Section: kw.Section,
+			Group:   kw.Group,
+			RType:   kw.Type,
+			Keyword: kw.Keyword,
+			Value:   kw.Value,
+		})
+	}
+	var before, after []uint
+	err = db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&version).Error; err != nil {
+			return fmt.Errorf("insert version: %w", err)
+		}
+		if err := tx.Model(&tables.ServiceConfigKeyword{}).Where("svc_id = ?", svc.SvcID).Pluck("id", &before).Error; err != nil {
+			return fmt.Errorf("select keywords: %w", err)
+		}
+		if len(before) > 0 {
+			if err := tx.Where("id IN ?", before).Delete(&tables.ServiceConfigKeyword{}).Error; err != nil {
+				return fmt.Errorf("delete keywords: %w", err)
+			}
+		}
+		if len(keywords) > 0 {
+			if err := tx.Create(&keywords).Error; err != nil {
+				return fmt.Errorf("insert keywords: %w", err)
+			}
+		}
+		for _, kw := range keywords {
+			after = append(after, kw.ID)
+		}
+		err := tx.Model(&tables.Service{}).Where("id = ?", svc.ID).Updates(map[string]interface{}{
+			"svc_config":         version.Text,
+			"svc_config_updated": version.CreatedAt,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("update service: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	publishUpserted("svc_configs", nil, []uint{version.ID})
+	publishDeleted("svc_config_keywords", before...)
+	publishUpserted("svc_config_keywords", nil, after)
+	events.Publish(events.Update, "services", svc.ID, "svc_config", "svc_config_updated")
+	jsonEncode(w, version)
+}
+
+//
+// GetServiceConfigHistory     godoc
+// @Summary      List the configuration versions of a service
+// @Description  List the configuration versions pushed by the nodes of the service. Use props to exclude the cfg_text.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /services/{id}/config/history  [get]
+//
+func GetServiceConfigHistory(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	rq := db.Tab("svc_configs").Request()
+	rq.Where("svc_configs.svc_id = ?", svc.SvcID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetServiceConfigDiff     godoc
+// @Summary      Show the differences between two configuration versions of a service
+// @Description  Respond the unified diff from a version to another, designated by their index in the configuration history.
+// @Description  The to version defaults to the last version, and the from version to the version preceding the to version, or an empty configuration if none.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Produce      plain
+// @Param        id       path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        from     query     int     false  "the index of the old version in the configuration history"
+// @Param        to       query     int     false  "the index of the new version in the configuration history"
+// @Param        context  query     int     false  "the number of unchanged lines around the changes (default 3)"
+// @Success      200      {string}  string  "the unified diff, empty if the versions are the same"
+// @Failure      400      {string}  string  "Bad Request"
+// @Failure      404      {string}  string  "Not Found"
+// @Failure      500      {string}  string  "Internal Server Error"
+// @Router       /services/{id}/config/diff  [get]
+//
+func GetServiceConfigDiff(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	context := diff.DefaultContext
+	if s := r.URL.Query().Get("context"); s != "" {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 0 {
+			http.Error(w, fmt.Sprintf("%s: invalid context %s", http.StatusText(400), s), 400)
+			return
+		}
+		context = i
+	}
+	to, ok, err := serviceConfigVersion(svc.SvcID, r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("select version: %s", err), 500)
+		return
+	}
+	if !ok {
+		http.Error(w, fmt.Sprintf("%s: to version of service %s", http.StatusText(404), svc.Svcname), 404)
+		return
+	}
+	var from tables.ServiceConfig
+	if r.URL.Query().Get("from") != "" {
+		from, ok, err = serviceConfigVersion(svc.SvcID, r.URL.Query().Get("from"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("select version: %s", err), 500)
+			return
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("%s: from version of service %s", http.StatusText(404), svc.Svcname), 404)
+			return
+		}
+	} else {
+		l := make([]tables.ServiceConfig, 0)
+		if err := db.DB().Where("svc_id = ? AND id < ?", svc.SvcID, to.ID).Order("id DESC").Limit(1).Find(&l).Error; err != nil {
+			http.Error(w, fmt.Sprintf("select version: %s", err), 500)
+			return
+		}
+		if len(l) > 0 {
+			from = l[0]
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, diff.Unified(serviceConfigLabel(svc, from), serviceConfigLabel(svc, to), from.Text, to.Text, context))
+}
+
+//
+// GetServicesConfigKeywords     godoc
+// @Summary      List the services configuration keywords
+// @Description  List the keywords of the last configuration of the services of the apps published to the user, for example the services using a resource type with filters=cfg_rtype=fs.ext4&props=services.svcname&groupby=services.svcname.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example cfg_rtype=fs.ext4"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /services/config/keywords  [get]
+//
+func GetServicesConfigKeywords(w http.ResponseWriter, r *http.Request) {
+	rq := db.Tab("svc_config_keywords").Request()
+	serveTableResponse(w, r, rq)
+}
+
+//
+// GetServiceConfigKeywords     godoc
+// @Summary      List the configuration keywords of a service
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      string    true   "the index of the entry in database, or uuid, or name"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%), for example cfg_group=fs"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /services/{id}/config/keywords  [get]
+//
+func GetServiceConfigKeywords(w http.ResponseWriter, r *http.Request) {
+	data := tables.ServiceFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	svc := data[0]
+	rq := db.Tab("svc_config_keywords").Request()
+	rq.Where("svc_config_keywords.svc_id = ?", svc.SvcID)
+	serveTableResponse(w, r, rq)
+}
@@ -1,8 +1,11 @@
 package routes
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
 	"github.com/opensvc/collector-api/db/tables"
 )
 
@@ -16,9 +19,11 @@ import (
 // @Accept       json
 // @Produce      json
 // @Success      200  {array}   tables.Service
+// @Success      304  {string}  string  "Not Modified"
 // @Failure      404  {string}  string  "Not Found"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
 // @Router       /services/{id}  [get]
 //
 func GetService(w http.ResponseWriter, r *http.Request) {
@@ -27,5 +32,64 @@ func GetService(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	jsonEncode(w, data)
+	jsonEncodeWithETag(w, r, data)
+}
+
+//
+// PatchService	godoc
+// @Summary      Patch a service
+// @Description  The user must be responsible for the service, via app responsibles.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Description  The dashboard alerts of the service are refreshed in the background.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Service
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /services/{id}  [patch]
+//
+func PatchService(w http.ResponseWriter, r *http.Request) {
+	currents := tables.ServiceFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	var i int64
+	rq := db.Tab("services").Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where("services.id = ?", current.ID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not responsible for service %s in app %s", http.StatusText(403), current.Svcname, current.SvcApp), 403)
+		return
+	}
+	if !checkIfMatch(w, r, currents) {
+		return
+	}
+	if !patchEntry(w, r, "services", current, current.ID) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Service{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+	refreshAlerts(r, dashboard.RefreshArgs{ServiceID: current.ID})
 }
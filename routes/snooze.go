@@ -0,0 +1,208 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/dashboard"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+)
+
+type (
+	snoozeRequest struct {
+		// Till is the snooze expiry date.
+		Till *time.Time `json:"till"`
+
+		// Duration is the snooze duration from now, as a go duration
+		// string, if Till is not set.
+		Duration string `json:"duration" example:"2h30m"`
+	}
+)
+
+// decodeSnooze returns the snooze expiry date of the request body. It
+// responds 400 or 422 if the body is invalid.
+func decodeSnooze(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	req := snoozeRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 400)
+		return time.Time{}, false
+	}
+	now := time.Now()
+	switch {
+	case req.Till != nil && req.Duration != "":
+		validationError(w, db.FieldErrors{{Field: "duration", Error: "exclusive with till"}})
+	case req.Till != nil:
+		if req.Till.After(now) {
+			return *req.Till, true
+		}
+		validationError(w, db.FieldErrors{{Field: "till", Error: "must be in the future"}})
+	case req.Duration != "":
+		if d, err := time.ParseDuration(req.Duration); err != nil || d <= 0 {
+			validationError(w, db.FieldErrors{{Field: "duration", Error: "must be a positive duration"}})
+		} else {
+			return now.Add(d), true
+		}
+	default:
+		validationError(w, db.FieldErrors{{Field: "till", Error: "till or duration is required"}})
+	}
+	return time.Time{}, false
+}
+
+//
+// PostNodeSnooze     godoc
+// @Summary      Snooze a node
+// @Description  The user must be in the NodeManager privilege group.
+// @Description  The user must be responsible for the node, via app responsibles.
+// @Description  No dashboard alert is raised on the node, nor notified, until the snooze expires.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string         true  "the index of the entry in database, or uuid, or name"
+// @Param        snooze  body      snoozeRequest  true  "the snooze expiry date or duration"
+// @Success      200     {array}   tables.Node
+// @Failure      400     {string}  string  "Bad Request"
+// @Failure      401     {string}  string  "missing NodeManager privilege"
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      404     {string}  string  "Not Found"
+// @Failure      422     {object}  validationErrorResponse
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /nodes/{id}/snooze  [post]
+//
+func PostNodeSnooze(w http.ResponseWriter, r *http.Request) {
+	till, ok := decodeSnooze(w, r)
+	if !ok {
+		return
+	}
+	setNodeSnooze(w, r, till)
+}
+
+//
+// DelNodeSnooze     godoc
+// @Summary      Unsnooze a node
+// @Description  The user must be in the NodeManager privilege group.
+// @Description  The user must be responsible for the node, via app responsibles.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         nodes
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "the index of the entry in database, or uuid, or name"
+// @Success      200     {array}   tables.Node
+// @Failure      401     {string}  string  "missing NodeManager privilege"
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      404     {string}  string  "Not Found"
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /nodes/{id}/snooze  [delete]
+//
+func DelNodeSnooze(w http.ResponseWriter, r *http.Request) {
+	setNodeSnooze(w, r, time.Time{})
+}
+
+func setNodeSnooze(w http.ResponseWriter, r *http.Request, till time.Time) {
+	if !authuser.HasPrivilege(auth.User(r), "NodeManager") {
+		authuser.PrivError(w, "NodeManager")
+		return
+	}
+	currents := tables.NodeFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	if !canWriteEntry(w, r, "nodes", current.ID, current.App) {
+		return
+	}
+	if err := db.Tab("nodes").Update(current.ID, map[string]interface{}{"snooze_till": till}); err != nil {
+		http.Error(w, fmt.Sprintf("update: %s", err), 500)
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Node{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+	refreshAlerts(r, dashboard.RefreshArgs{NodeID: current.ID})
+}
+
+//
+// PostServiceSnooze     godoc
+// @Summary      Snooze a service
+// @Description  The user must be responsible for the service, via app responsibles.
+// @Description  No dashboard alert is raised on the service, nor notified, until the snooze expires.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string         true  "the index of the entry in database, or uuid, or name"
+// @Param        snooze  body      snoozeRequest  true  "the snooze expiry date or duration"
+// @Success      200     {array}   tables.Service
+// @Failure      400     {string}  string  "Bad Request"
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      404     {string}  string  "Not Found"
+// @Failure      422     {object}  validationErrorResponse
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /services/{id}/snooze  [post]
+//
+func PostServiceSnooze(w http.ResponseWriter, r *http.Request) {
+	till, ok := decodeSnooze(w, r)
+	if !ok {
+		return
+	}
+	setServiceSnooze(w, r, till)
+}
+
+//
+// DelServiceSnooze     godoc
+// @Summary      Unsnooze a service
+// @Description  The user must be responsible for the service, via app responsibles.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         services
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string  true  "the index of the entry in database, or uuid, or name"
+// @Success      200     {array}   tables.Service
+// @Failure      403     {string}  string  "Forbidden"
+// @Failure      404     {string}  string  "Not Found"
+// @Failure      500     {string}  string  "Internal Server Error"
+// @Router       /services/{id}/snooze  [delete]
+//
+func DelServiceSnooze(w http.ResponseWriter, r *http.Request) {
+	setServiceSnooze(w, r, time.Time{})
+}
+
+func setServiceSnooze(w http.ResponseWriter, r *http.Request, till time.Time) {
+	currents := tables.ServiceFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	if !canWriteEntry(w, r, "services", current.ID, current.SvcApp) {
+		return
+	}
+	if err := db.Tab("services").Update(current.ID, map[string]interface{}{"svc_snooze_till": till}); err != nil {
+		http.Error(w, fmt.Sprintf("update: %s", err), 500)
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Service{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+	refreshAlerts(r, dashboard.RefreshArgs{ServiceID: current.ID})
+}
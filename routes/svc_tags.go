@@ -39,7 +39,7 @@ func GetServiceTag(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprint(err), 500)
 		return
 	}
-	jsonEncode(w, data)
+	jsonEncodeWithETag(w, r, data)
 }
 
 //
@@ -50,28 +50,33 @@ func GetServiceTag(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /services/tags  [get]
 //
 func GetServicesTags(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("svc_tags").Request()
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -82,15 +87,28 @@ func GetServicesTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /services/{id}/tags  [get]
 //
 func GetServiceTags(w http.ResponseWriter, r *http.Request) {
@@ -103,15 +121,7 @@ func GetServiceTags(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("tags").Request()
 	rq.AutoJoin("svc_tags")
 	rq.Where("svc_tags.svc_id = ?", n.SvcID)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -122,15 +132,28 @@ func GetServiceTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /services/{id}/candidate_tags  [get]
 //
 func GetServiceCandidateTags(w http.ResponseWriter, r *http.Request) {
@@ -144,15 +167,7 @@ func GetServiceCandidateTags(w http.ResponseWriter, r *http.Request) {
 
 	rq := db.Tab("tags").Request()
 	rq.Where("tags.tag_id NOT IN (?)", exclude)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -163,15 +178,28 @@ func GetServiceCandidateTags(w http.ResponseWriter, r *http.Request) {
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /tags/{id}/services  [get]
 //
 func GetTagServices(w http.ResponseWriter, r *http.Request) {
@@ -180,13 +208,65 @@ func GetTagServices(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("services").Request()
 	rq.AutoJoin("tags")
 	rq.Where("svc_tags.tag_id = ?", tag.TagID)
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PatchServiceTag     godoc
+// @Summary      Patch a tag attachment to a service
+// @Description  The user must be responsible for the service, via app responsibles.
+// @Description  The attachment svc_id and tag_id can not be modified.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         tags
+// @Tags         services
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.ServiceTag
+// @Failure      403       {string}  string  "Forbidden"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /services/{id}/tags/{id}  [patch]
+// @Router       /services/tags/{id}  [patch]
+//
+func PatchServiceTag(w http.ResponseWriter, r *http.Request) {
+	attachs := tables.ServiceTagFromCtx(r)
+	if len(attachs) == 0 {
+		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
+	current := attachs[0]
+	var i int64
+	rq := db.Tab("svc_tags").Request(db.TableRequestWithWriteIntent(true))
+	if err := rq.TX(r).Where("svc_tags.id = ?", current.ID).Count(&i).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select from write: %s", err), 500)
+		return
+	}
+	if i == 0 {
+		http.Error(w, fmt.Sprintf("%s: user is not responsible for the service", http.StatusText(403)), 403)
+		return
+	}
+	if !checkIfMatch(w, r, attachs) {
+		return
+	}
+	if !patchEntry(w, r, "svc_tags", current, current.ID, db.ValidateWithReadOnly("svc_id", "tag_id")) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.ServiceTag{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
 	}
 }
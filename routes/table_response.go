@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type tableResponseMaker interface {
+	MakeTableResponse(r *http.Request) (*db.TableResponse, error)
+	StreamTableResponse(r *http.Request, w db.RowWriter) error
+}
+
+// serveTableResponse responds the table data and metadata selected by
+// the request query parameters.
+//
+// If the client requests the ndjson or csv format, the rows are streamed
+// as they are read from the database, without metadata.
+func serveTableResponse(w http.ResponseWriter, r *http.Request, rq tableResponseMaker) {
+	if contentType := streamContentType(r); contentType != "" {
+		serveTableStream(w, r, rq, contentType)
+		return
+	}
+	td, err := rq.MakeTableResponse(r)
+	if errors.Is(err, db.ErrBadRequest) {
+		http.Error(w, fmt.Sprint(err), 400)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, td); err != nil {
+		http.Error(w, fmt.Sprint(err), 500)
+		return
+	}
+}
+
+func serveTableStream(w http.ResponseWriter, r *http.Request, rq tableResponseMaker, contentType string) {
+	rw := newRowWriter(w, contentType)
+	err := rq.StreamTableResponse(r, rw)
+	if rw.Started() {
+		if err := rw.Flush(); err != nil {
+			log.Printf("stream table response: flush: %s", err)
+		}
+	}
+	switch {
+	case err == nil:
+	case rw.Started():
+		// the status is already sent, the truncated stream is the
+		// only error indication left.
+		log.Printf("stream table response: %s", err)
+	case errors.Is(err, db.ErrBadRequest):
+		http.Error(w, fmt.Sprint(err), 400)
+	default:
+		http.Error(w, fmt.Sprint(err), 500)
+	}
+}
@@ -21,28 +21,33 @@ import (
 // @Tags      tags
 // @Accept    json
 // @Produce   json
+// @Produce   application/x-ndjson
+// @Produce   text/csv
 // @Success   200      {object}  db.TableResponse
+// @Success   304      {string}  string    "Not Modified"
+// @Failure   400      {string}  string    "Bad Request"
 // @Failure   500      {string}  string    "Internal Server Error"
 // @Param     props    query     string    false  "properties to include, and optionally remap (comma separated)"
 // @Param     groupby  query     string    false  "properties to group by (comma separated)"
+// @Param     aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param     having   query     []string  false  "aggregate alias value filter (n>10)"
 // @Param     order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
 // @Param     filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param     filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
 // @Param     limit    query     int       false  "number of objets to include in response"
 // @Param     offset   query     int       false  "offset of the first objet to include in response"
+// @Param     cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param     total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param     format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param     facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param     facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
 // @Param     meta     query     bool      false  "turn off metadata in response"
+// @Param     If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
 // @Router    /tags  [get]
 //
 func GetTags(w http.ResponseWriter, r *http.Request) {
 	rq := db.Tab("tags").Request(db.TableRequestWithACL(false))
-	td, err := rq.MakeTableResponse(r)
-	if err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
-	if err := jsonEncode(w, td); err != nil {
-		http.Error(w, fmt.Sprint(err), 500)
-		return
-	}
+	serveTableResponse(w, r, rq)
 }
 
 //
@@ -56,6 +61,7 @@ func GetTags(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        tags  body      []tables.Tag  true  "list of tags to create or update"
 // @Success      200   {array}   tables.Tag
+// @Failure      422   {object}  validationErrorResponse
 // @Failure      500   {string}  string  "Internal Server Error"
 // @Router       /tags  [post]
 //
@@ -75,11 +81,27 @@ func PostTags(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
 		return
 	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateEntries(db.Tab("tags"), entries, "id"); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	before := make([]uint, len(tags))
+	for i, tag := range tags {
+		before[i] = tag.ID
+	}
 	tx := db.DB().Clauses(clause.OnConflict{UpdateAll: true})
 	if err := tx.Create(&tags).Error; err != nil {
 		http.Error(w, fmt.Sprintf("insert or update: %s", err), 500)
 		return
 	}
+	after := make([]uint, len(tags))
+	for i, tag := range tags {
+		after[i] = tag.ID
+	}
+	publishUpserted("tags", before, after)
 	if err := jsonEncode(w, tags); err != nil {
 		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
 		return
@@ -144,6 +166,9 @@ func DelTags(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprint(err), 500)
 		return
 	}
+	for _, tag := range tags {
+		publishDeleted("tags", tag.ID)
+	}
 	if err := jsonEncode(w, tags); err != nil {
 		http.Error(w, fmt.Sprint(err), 500)
 		return
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/opensvc/collector-api/auth"
@@ -23,8 +24,10 @@ import (
 // @Success   200  {array}   tables.Tag
 // @Success      204  {string}  string  "No Content"
 // @Failure      403  {string}  string  "Forbidden"
+// @Failure      412  {string}  string  "Precondition Failed"
 // @Failure   500  {string}  string  "Internal Server Error"
 // @Param     id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
 // @Router       /tags/{id}  [delete]
 //
 func DelTag(w http.ResponseWriter, r *http.Request) {
@@ -38,7 +41,14 @@ func DelTag(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(204), 204)
 		return
 	}
-	db.DB().Where("tags.id = ?", tags[0].ID).Delete(&[]tables.Tag{})
+	if !checkIfMatch(w, r, tags) {
+		return
+	}
+	if err := deleteIfMatch(r, db.DB(), "tags", tags[0].ID, tags[0].UpdatedAt, &[]tables.Tag{}); err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("tags", tags[0].ID)
 	jsonEncode(w, tags)
 }
 
@@ -51,9 +61,11 @@ func DelTag(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Success      200  {array}   tables.Tag
+// @Success      304  {string}  string  "Not Modified"
 // @Failure   404  {string}  string  "Not Found"
 // @Failure      500  {string}  string  "Internal Server Error"
 // @Param        id   path      string  true  "the index of the entry in database, or uuid, or name"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
 // @Router    /tags/{id}  [get]
 //
 func GetTag(w http.ResponseWriter, r *http.Request) {
@@ -62,5 +74,57 @@ func GetTag(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, http.StatusText(404), 404)
 		return
 	}
-	jsonEncode(w, tags)
+	jsonEncodeWithETag(w, r, tags)
+}
+
+//
+// PatchTag     godoc
+// @Summary      Patch a tag
+// @Description  Requires the TagManager privilege.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         tags
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      string  true   "the index of the entry in database, or uuid, or name"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Tag
+// @Failure      401       {string}  string  "missing TagManager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /tags/{id}  [patch]
+//
+func PatchTag(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.HasPrivilege(user, "TagManager") {
+		authuser.PrivError(w, "TagManager")
+		return
+	}
+	tags := tables.TagFromCtx(r)
+	if len(tags) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := tags[0]
+	if !checkIfMatch(w, r, tags) {
+		return
+	}
+	if !patchEntry(w, r, "tags", current, current.ID) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, []tables.Tag{current}); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
 }
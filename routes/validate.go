@@ -0,0 +1,69 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/opensvc/collector-api/db"
+)
+
+type validationErrorResponse struct {
+	Error  string         `json:"error"`
+	Fields db.FieldErrors `json:"fields"`
+}
+
+// decodeEntries returns the json objects of a request body containing
+// either a single object or a list of objects.
+func decodeEntries(body []byte) ([]map[string]interface{}, error) {
+	entry := make(map[string]interface{})
+	if err := json.Unmarshal(body, &entry); err == nil {
+		return []map[string]interface{}{entry}, nil
+	}
+	entries := make([]map[string]interface{}, 0)
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// validateEntries verifies a list of entries to create or update. The
+// entries having one of the keys properties set are updates, so their
+// required properties are not verified.
+func validateEntries(t *db.Table, entries []map[string]interface{}, keys ...string) db.FieldErrors {
+	errs := make(db.FieldErrors, 0)
+	for i, entry := range entries {
+		create := true
+		for _, k := range keys {
+			if _, ok := entry[k]; ok {
+				create = false
+			}
+		}
+		l := t.Validate(entry, db.ValidateWithCreate(create), db.ValidateWithKeys(keys...))
+		errs = append(errs, l.WithIndex(i)...)
+	}
+	return errs
+}
+
+// isStringList returns true if the json decoded value is a list of
+// strings.
+func isStringList(i interface{}) bool {
+	l, ok := i.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, e := range l {
+		if _, ok := e.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func validationError(w http.ResponseWriter, errs db.FieldErrors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	jsonEncode(w, validationErrorResponse{
+		Error:  "invalid properties",
+		Fields: errs,
+	})
+}
@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+)
+
+//
+// GetWebhooks     godoc
+// @Summary      List webhooks
+// @Description  Requires the Manager privilege. The webhook secrets are not included.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      401      {string}  string    "missing Manager privilege"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /webhooks  [get]
+//
+func GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !authuser.IsManager(auth.User(r)) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	rq := db.Tab("webhooks").Request()
+	serveTableResponse(w, r, rq)
+}
+
+
+//
+// PostWebhooks     godoc
+// @Summary      Create webhooks
+// @Description  Requires the Manager privilege.
+// @Description  The table entries change events matching the webhook events, tables and filters are posted to the webhook url.
+// @Description  The deliveries are signed with the secret, in the X-Collector-Signature header, and retried with an exponential backoff until the url responds a 2xx status.
+// @Description  The filters require a single table.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhooks  body      []tables.Webhook  true  "list of webhooks to create"
+// @Success      200       {array}   tables.Webhook
+// @Failure      401       {string}  string  "missing Manager privilege"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string  "Internal Server Error"
+// @Router       /webhooks  [post]
+//
+func PostWebhooks(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	if !authuser.IsManager(user) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	userID, _ := strconv.Atoi(user.GetID())
+	webhooks := make([]tables.Webhook, 0)
+	webhook := tables.Webhook{}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request body: %s", err), 500)
+		return
+	}
+	if err := json.Unmarshal(body, &webhook); err == nil {
+		// single entry
+		webhooks = append(webhooks, webhook)
+	} else if err := json.Unmarshal(body, &webhooks); err != nil {
+		// list of entry
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	}
+	if entries, err := decodeEntries(body); err != nil {
+		http.Error(w, fmt.Sprintf("unmarshal json: %s", err), 500)
+		return
+	} else if errs := validateWebhookEntries(entries); len(errs) > 0 {
+		validationError(w, errs)
+		return
+	}
+	for i := range webhooks {
+		webhooks[i].UserID = uint(userID)
+	}
+	if err := db.DB().Create(&webhooks).Error; err != nil {
+		http.Error(w, fmt.Sprintf("insert: %s", err), 500)
+		return
+	}
+	for _, webhook := range webhooks {
+		events.Publish(events.Create, "webhooks", webhook.ID)
+	}
+	if err := jsonEncode(w, tables.WebhooksWithoutSecret(webhooks)); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+// validateWebhookEntries verifies the webhooks to create against the table
+// rules, and verifies the url, the event kinds, the tables and the
+// filters.
+func validateWebhookEntries(entries []map[string]interface{}) db.FieldErrors {
+	errs := validateEntries(db.Tab("webhooks"), entries)
+	for i, entry := range entries {
+		errs = append(errs, validateWebhookEntry(entry).WithIndex(i)...)
+	}
+	return errs
+}
+
+func validateWebhookEntry(entry map[string]interface{}) db.FieldErrors {
+	errs := make(db.FieldErrors, 0)
+	if s, ok := entry["url"].(string); ok {
+		if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, db.FieldError{Field: "url", Error: "must be a http or https url"})
+		}
+	}
+	if s, ok := entry["events"].(string); ok && s != "" {
+		for _, kind := range strings.Split(s, ",") {
+			switch events.Kind(strings.TrimSpace(kind)) {
+			case events.Create, events.Update, events.Delete:
+			default:
+				errs = append(errs, db.FieldError{Field: "events", Error: "must be a comma separated list of create, update or delete"})
+				return errs
+			}
+		}
+	}
+	nTables := 0
+	if s, ok := entry["tables"].(string); ok && s != "" {
+		for _, name := range strings.Split(s, ",") {
+			if db.Tab(strings.TrimSpace(name)) == nil {
+				errs = append(errs, db.FieldError{Field: "tables", Error: fmt.Sprintf("unknown table %s", name)})
+			}
+			nTables++
+		}
+	}
+	if i, ok := entry["filters"]; ok && i != nil {
+		if !isStringList(i) {
+			errs = append(errs, db.FieldError{Field: "filters", Error: "must be a list of strings"})
+		} else if len(i.([]interface{})) > 0 && nTables != 1 {
+			errs = append(errs, db.FieldError{Field: "filters", Error: "require a single table"})
+		}
+	}
+	return errs
+}
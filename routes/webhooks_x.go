@@ -0,0 +1,240 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/opensvc/collector-api/auth"
+	"github.com/opensvc/collector-api/authuser"
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/webhook"
+	"gorm.io/gorm"
+)
+
+//
+// GetWebhook     godoc
+// @Summary      Show a webhook
+// @Description  Requires the Manager privilege. The webhook secret is not included.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Webhook
+// @Success      304  {string}  string  "Not Modified"
+// @Failure      404  {string}  string  "Not Found"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the entry matches"
+// @Router       /webhooks/{id}  [get]
+//
+func GetWebhook(w http.ResponseWriter, r *http.Request) {
+	data := tables.WebhookFromCtx(r)
+	if len(data) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	jsonEncodeWithETag(w, r, tables.WebhooksWithoutSecret(data))
+}
+
+//
+// PatchWebhook     godoc
+// @Summary      Patch a webhook
+// @Description  Requires the Manager privilege.
+// @Description  The request body is a RFC 7396 merge patch or a RFC 6902 patch, depending on the Content-Type.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path      int     true   "the index of the entry in database"
+// @Param        If-Match  header    string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Param        patch     body      object  true   "the merge patch or the list of patch operations"
+// @Success      200       {array}   tables.Webhook
+// @Failure      401       {string}  string  "missing Manager privilege"
+// @Failure      404       {string}  string  "the entry to update does not exist"
+// @Failure      409       {string}  string  "a patch test operation failed"
+// @Failure      412       {string}  string  "the entry was modified since the client read it"
+// @Failure      415       {string}  string  "unsupported patch media type"
+// @Failure      422       {object}  validationErrorResponse
+// @Failure      500       {string}  string
+// @Router       /webhooks/{id}  [patch]
+//
+func PatchWebhook(w http.ResponseWriter, r *http.Request) {
+	if !authuser.IsManager(auth.User(r)) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	currents := tables.WebhookFromCtx(r)
+	if len(currents) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	current := currents[0]
+	if !checkIfMatch(w, r, tables.WebhooksWithoutSecret(currents)) {
+		return
+	}
+	// the secret is not exposed, so it is not part of the patched
+	// document and can only be replaced
+	current.Secret = ""
+	if !patchEntry(w, r, "webhooks", current, current.ID) {
+		return
+	}
+	if err := db.DB().Take(&current).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	if err := jsonEncodeWithETag(w, r, tables.WebhooksWithoutSecret([]tables.Webhook{current})); err != nil {
+		http.Error(w, fmt.Sprintf("json encode: %s", err), 500)
+		return
+	}
+}
+
+//
+// DelWebhook     godoc
+// @Summary      Delete a webhook
+// @Description  Requires the Manager privilege.
+// @Description  Cascade deletes the webhook deliveries.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   tables.Webhook
+// @Success      204  {string}  string  "No Content"
+// @Failure      401  {string}  string  "missing Manager privilege"
+// @Failure      412  {string}  string  "Precondition Failed"
+// @Failure      500  {string}  string  "Internal Server Error"
+// @Param        id   path      int     true  "the index of the entry in database"
+// @Param        If-Match  header  string  false  "respond 412 Precondition Failed if the entity tag of the entry does not match"
+// @Router       /webhooks/{id}  [delete]
+//
+func DelWebhook(w http.ResponseWriter, r *http.Request) {
+	if !authuser.IsManager(auth.User(r)) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	webhooks := tables.WebhooksWithoutSecret(tables.WebhookFromCtx(r))
+	if len(webhooks) == 0 {
+		http.Error(w, http.StatusText(204), 204)
+		return
+	}
+	current := webhooks[0]
+	if !checkIfMatch(w, r, webhooks) {
+		return
+	}
+	err := db.DB().Transaction(func(tx *gorm.DB) error {
+		if err := deleteIfMatch(r, tx, "webhooks", current.ID, current.UpdatedAt, &[]tables.Webhook{}); err != nil {
+			return err
+		}
+		if err := tx.Where("webhook_id = ?", current.ID).Delete(&[]tables.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, "delete", err)
+		return
+	}
+	publishDeleted("webhooks", current.ID)
+	jsonEncode(w, webhooks)
+}
+
+//
+// GetWebhookDeliveries     godoc
+// @Summary      List the deliveries of a webhook
+// @Description  Requires the Manager privilege.
+// @Description  The deliveries with the "dead" status failed all their attempts. They can be sent again with the retry route.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Success      200      {object}  db.TableResponse
+// @Success      304      {string}  string    "Not Modified"
+// @Failure      400      {string}  string    "Bad Request"
+// @Failure      404      {string}  string    "Not Found"
+// @Failure      500      {string}  string    "Internal Server Error"
+// @Param        id       path      int       true   "the index of the webhook in database"
+// @Param        props    query     string    false  "properties to include, and optionally remap (comma separated)"
+// @Param        groupby  query     string    false  "properties to group by (comma separated)"
+// @Param        aggregate  query   string    false  "aggregates to select with their optional alias, with groupby (count(*):n,sum(nodes.mem_bytes):mem)"
+// @Param        having   query     []string  false  "aggregate alias value filter (n>10)"
+// @Param        order    query     string    false  "properties to order by (comma separated, prefix with '~' to reverse)"
+// @Param        filters  query     []string  false  "property value filter (a, !a, a&b, a|b, (a,b),  a%,  a%&!ab%)"
+// @Param        filterset  query    string    false  "name of a saved filterset to apply, owned by the user or shared with one of the user groups"
+// @Param        limit    query     int       false  "number of objets to include in response"
+// @Param        offset   query     int       false  "offset of the first objet to include in response"
+// @Param        cursor   query     string    false  "keyset paging cursor from meta.next_cursor or meta.prev_cursor, empty for the first page"
+// @Param        total    query     string    false  "meta.total computation: exact, approx or none"  Enums(exact, approx, none)
+// @Param        format   query     string    false  "response encoding, streamed without metadata if ndjson or csv (default from Accept)"  Enums(json, ndjson, csv)
+// @Param        facets   query     string    false  "properties to count the rows per value of in meta.facets (comma separated)"
+// @Param        facets_limit  query  int     false  "maximum number of values per facet, most frequent first (default 10)"
+// @Param        meta     query     bool      false  "turn off metadata in response"
+// @Param        If-None-Match  header  string  false  "respond 304 Not Modified if the entity tag of the response matches"
+// @Router       /webhooks/{id}/deliveries  [get]
+//
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	webhooks := tables.WebhookFromCtx(r)
+	if len(webhooks) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	rq := db.Tab("webhook_deliveries").Request()
+	rq.Where("webhook_deliveries.webhook_id = ?", webhooks[0].ID)
+	serveTableResponse(w, r, rq)
+}
+
+//
+// PostWebhookDeliveryRetry     godoc
+// @Summary      Retry a webhook delivery
+// @Description  Requires the Manager privilege.
+// @Description  Reset the delivery attempts, so it is sent again as soon as possible, including if it is dead.
+// @Security     BasicAuth
+// @Security     BearerAuth
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Success      200          {object}  tables.WebhookDelivery
+// @Failure      401          {string}  string  "missing Manager privilege"
+// @Failure      404          {string}  string  "Not Found"
+// @Failure      500          {string}  string  "Internal Server Error"
+// @Param        id           path      int     true  "the index of the webhook in database"
+// @Param        delivery_id  path      int     true  "the index of the delivery in database"
+// @Router       /webhooks/{id}/deliveries/{delivery_id}/retry  [post]
+//
+func PostWebhookDeliveryRetry(w http.ResponseWriter, r *http.Request) {
+	if !authuser.IsManager(auth.User(r)) {
+		authuser.PrivError(w, "Manager")
+		return
+	}
+	webhooks := tables.WebhookFromCtx(r)
+	if len(webhooks) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+	delivery := tables.WebhookDelivery{}
+	tx := db.DB().Where("id = ? AND webhook_id = ?", chi.URLParam(r, "delivery_id"), webhooks[0].ID)
+	if err := tx.Take(&delivery).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("select delivery: %s", err), 500)
+		return
+	}
+	if err := webhook.Retry(delivery.ID); err != nil {
+		http.Error(w, fmt.Sprintf("retry: %s", err), 500)
+		return
+	}
+	if err := db.DB().Take(&delivery).Error; err != nil {
+		http.Error(w, fmt.Sprintf("select after update: %s", err), 500)
+		return
+	}
+	jsonEncode(w, delivery)
+}
@@ -0,0 +1,89 @@
+// Package svcconfig parses the service configuration files pushed by the
+// nodes into keywords.
+//
+// The configuration is an ini file: [section] headers, followed by
+// "keyword = value" lines. A line indented after a keyword line continues
+// its value on a new line. The lines starting with # or ; are comments.
+// The lines before the first section header are ignored.
+//
+// The resource sections are named after their driver group and an index,
+// for example [fs#1], and their "type" keyword selects the driver of the
+// group, for example ext4. The keywords of a resource section are
+// tagged with the fs.ext4 resource type, or the fs driver group if the
+// section has no type keyword.
+package svcconfig
+
+import (
+	"strings"
+)
+
+type (
+	// Keyword is a keyword of a section of a service configuration.
+	Keyword struct {
+		Section string
+		Group   string
+		Type    string
+		Keyword string
+		Value   string
+	}
+)
+
+// Group returns the driver group of a section, the section name before
+// the # index separator.
+func Group(section string) string {
+	if i := strings.Index(section, "#"); i >= 0 {
+		return section[:i]
+	}
+	return section
+}
+
+// Parse returns the keywords of the configuration, in order.
+func Parse(text string) []Keyword {
+	l := make([]Keyword, 0)
+	var section string
+	last := -1
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			last = -1
+		case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, ";"):
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			last = -1
+		case section == "":
+		case line[0] == ' ' || line[0] == '\t':
+			if last >= 0 {
+				if l[last].Value == "" {
+					l[last].Value = trimmed
+				} else {
+					l[last].Value += "\n" + trimmed
+				}
+			}
+		default:
+			kw := Keyword{Section: section, Group: Group(section)}
+			if i := strings.Index(trimmed, "="); i >= 0 {
+				kw.Keyword = strings.TrimSpace(trimmed[:i])
+				kw.Value = strings.TrimSpace(trimmed[i+1:])
+			} else {
+				kw.Keyword = trimmed
+			}
+			l = append(l, kw)
+			last = len(l) - 1
+		}
+	}
+	types := make(map[string]string)
+	for _, kw := range l {
+		if kw.Keyword == "type" && kw.Value != "" {
+			types[kw.Section] = kw.Group + "." + kw.Value
+		}
+	}
+	for i := range l {
+		if t, ok := types[l[i].Section]; ok {
+			l[i].Type = t
+		} else {
+			l[i].Type = l[i].Group
+		}
+	}
+	return l
+}
@@ -0,0 +1,63 @@
+package svcconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	assert.Equal(t, "fs", Group("fs#1"))
+	assert.Equal(t, "DEFAULT", Group("DEFAULT"))
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected []Keyword
+	}{
+		"empty": {
+			text:     "",
+			expected: []Keyword{},
+		},
+		"sections": {
+			text: "[DEFAULT]\nnodes = n1 n2\n\n[fs#1]\ntype = ext4\nmnt=/srv\n",
+			expected: []Keyword{
+				{Section: "DEFAULT", Group: "DEFAULT", Type: "DEFAULT", Keyword: "nodes", Value: "n1 n2"},
+				{Section: "fs#1", Group: "fs", Type: "fs.ext4", Keyword: "type", Value: "ext4"},
+				{Section: "fs#1", Group: "fs", Type: "fs.ext4", Keyword: "mnt", Value: "/srv"},
+			},
+		},
+		"type after keywords": {
+			text: "[ip#0]\nipname = 10.0.0.1\ntype = crossbow\n",
+			expected: []Keyword{
+				{Section: "ip#0", Group: "ip", Type: "ip.crossbow", Keyword: "ipname", Value: "10.0.0.1"},
+				{Section: "ip#0", Group: "ip", Type: "ip.crossbow", Keyword: "type", Value: "crossbow"},
+			},
+		},
+		"comments and continuation": {
+			text: "# header\n[app#1]\n; comment\nscript = start.sh\nenv =\n  A=1\n  B=2\n",
+			expected: []Keyword{
+				{Section: "app#1", Group: "app", Type: "app", Keyword: "script", Value: "start.sh"},
+				{Section: "app#1", Group: "app", Type: "app", Keyword: "env", Value: "A=1\nB=2"},
+			},
+		},
+		"scoped keyword and no value": {
+			text: "[disk#1]\nname@n1 = vg1\nshared\n",
+			expected: []Keyword{
+				{Section: "disk#1", Group: "disk", Type: "disk", Keyword: "name@n1", Value: "vg1"},
+				{Section: "disk#1", Group: "disk", Type: "disk", Keyword: "shared", Value: ""},
+			},
+		},
+		"keywords before section ignored": {
+			text: "orphan = 1\n[DEFAULT]\nid = x\n",
+			expected: []Keyword{
+				{Section: "DEFAULT", Group: "DEFAULT", Type: "DEFAULT", Keyword: "id", Value: "x"},
+			},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, Parse(test.text))
+	}
+}
@@ -0,0 +1,176 @@
+// Package version compares the package versions reported by the nodes.
+//
+// The versions are compared the way dpkg does, which also orders the rpm
+// versions as expected in most cases: an optional numeric epoch followed
+// by a colon, the upstream version, and an optional revision after the
+// last hyphen. The upstream versions and revisions are compared by
+// alternating non-digit and digit segments. The non-digit segments are
+// compared character by character, letters sorting before the other
+// characters, and a tilde before anything, even the end of the segment,
+// so 1.0~rc1 is lower than 1.0. The digit segments are compared
+// numerically.
+package version
+
+import (
+	"strings"
+)
+
+// Op is a comparison operator of versions.
+type Op string
+
+const (
+	OpLT Op = "lt"
+	OpLE Op = "le"
+	OpEQ Op = "eq"
+	OpNE Op = "ne"
+	OpGE Op = "ge"
+	OpGT Op = "gt"
+)
+
+// ValidOp returns true if the operator is known.
+func ValidOp(op Op) bool {
+	switch op {
+	case OpLT, OpLE, OpEQ, OpNE, OpGE, OpGT:
+		return true
+	default:
+		return false
+	}
+}
+
+// Match returns true if the comparison of a with b satisfies the operator.
+func Match(a string, op Op, b string) bool {
+	i := Compare(a, b)
+	switch op {
+	case OpLT:
+		return i < 0
+	case OpLE:
+		return i <= 0
+	case OpEQ:
+		return i == 0
+	case OpNE:
+		return i != 0
+	case OpGE:
+		return i >= 0
+	case OpGT:
+		return i > 0
+	default:
+		return false
+	}
+}
+
+// Compare returns -1 if a is lower than b, 0 if they are equal, and 1 if a
+// is greater than b.
+func Compare(a, b string) int {
+	ea, ua, ra := split(a)
+	eb, ub, rb := split(b)
+	if i := compareDigits(ea, eb); i != 0 {
+		return i
+	}
+	if i := compareSegments(ua, ub); i != 0 {
+		return i
+	}
+	return compareSegments(ra, rb)
+}
+
+// split returns the epoch, upstream version and revision of a version.
+func split(s string) (epoch, upstream, revision string) {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ":"); i >= 0 && isDigits(s[:i]) {
+		epoch, s = s[:i], s[i+1:]
+	}
+	if i := strings.LastIndex(s, "-"); i >= 0 {
+		s, revision = s[:i], s[i+1:]
+	}
+	return epoch, s, revision
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// order returns the weight of a character in a non-digit segment. The end
+// of the segment weighs 0.
+func order(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case isLetter(c):
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// compareSegments compares the alternating non-digit and digit segments
+// of two upstream versions or revisions.
+func compareSegments(a, b string) int {
+	for a != "" || b != "" {
+		// non-digit segments
+		for (a != "" && !isDigit(a[0])) || (b != "" && !isDigit(b[0])) {
+			var oa, ob int
+			if a != "" && !isDigit(a[0]) {
+				oa = order(a[0])
+				a = a[1:]
+			}
+			if b != "" && !isDigit(b[0]) {
+				ob = order(b[0])
+				b = b[1:]
+			}
+			if oa != ob {
+				return sign(oa - ob)
+			}
+		}
+		// digit segments
+		var da, db string
+		da, a = digitPrefix(a)
+		db, b = digitPrefix(b)
+		if i := compareDigits(da, db); i != 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// digitPrefix returns the leading digits of s, and the rest of s.
+func digitPrefix(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareDigits compares two strings of digits numerically, without size
+// limit. The empty string is 0.
+func compareDigits(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return sign(len(a) - len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"equal":                 {a: "1.2.3", b: "1.2.3", expected: 0},
+		"numeric not lexical":   {a: "1.10", b: "1.9", expected: 1},
+		"leading zeros":         {a: "1.01", b: "1.1", expected: 0},
+		"more segments":         {a: "1.2.1", b: "1.2", expected: 1},
+		"epoch wins":            {a: "1:1.0", b: "2.0", expected: 1},
+		"missing epoch is zero": {a: "0:1.0", b: "1.0", expected: 0},
+		"revision":              {a: "1.0-2", b: "1.0-10", expected: -1},
+		"upstream before rev":   {a: "1.1-1", b: "1.0-9", expected: 1},
+		"hyphen in upstream":    {a: "1.0-beta-2", b: "1.0-beta-1", expected: 1},
+		"tilde before release":  {a: "1.0~rc1", b: "1.0", expected: -1},
+		"tilde before tilde":    {a: "1.0~~", b: "1.0~", expected: -1},
+		"letters before dots":   {a: "1.0a", b: "1.0.1", expected: -1},
+		"letters":               {a: "1.0b", b: "1.0a", expected: 1},
+		"rpm release":           {a: "2.28-211.el8", b: "2.28-189.5.el8_6", expected: 1},
+		"kernel":                {a: "4.18.0-425.3.1.el8", b: "4.18.0-80.el8", expected: 1},
+		"huge numbers":          {a: "20230101000000000000", b: "20230101000000000001", expected: -1},
+		"empty":                 {a: "", b: "0", expected: 0},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, Compare(test.a, test.b))
+		assert.Equal(t, -test.expected, Compare(test.b, test.a))
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := map[string]struct {
+		a        string
+		op       Op
+		b        string
+		expected bool
+	}{
+		"lt":       {a: "1.9", op: OpLT, b: "1.10", expected: true},
+		"lt equal": {a: "1.10", op: OpLT, b: "1.10", expected: false},
+		"le equal": {a: "1.10", op: OpLE, b: "1.10", expected: true},
+		"eq":       {a: "1.010", op: OpEQ, b: "1.10", expected: true},
+		"ne":       {a: "1.9", op: OpNE, b: "1.10", expected: true},
+		"ge":       {a: "1.10", op: OpGE, b: "1.9", expected: true},
+		"gt":       {a: "1.9", op: OpGT, b: "1.10", expected: false},
+		"unknown":  {a: "1.9", op: Op("like"), b: "1.9", expected: false},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.expected, Match(test.a, test.op, test.b))
+	}
+	assert.True(t, ValidOp(OpGE))
+	assert.False(t, ValidOp(Op("like")))
+}
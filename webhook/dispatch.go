@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/opensvc/collector-api/db"
+	"github.com/opensvc/collector-api/db/tables"
+	"github.com/opensvc/collector-api/events"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusFailed    = "failed"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+
+	sendInterval = 5 * time.Second
+	sendTimeout  = 10 * time.Second
+	sendBatch    = 50
+
+	// claimLease is the delay before another dispatcher can claim a
+	// delivery being sent.
+	claimLease = time.Minute
+)
+
+var (
+	wake = make(chan interface{}, 1)
+
+	dueStatuses = []string{StatusPending, StatusFailed}
+)
+
+// Start runs the dispatcher until the context is done. It enqueues a
+// delivery for each published event matching a webhook subscription, and
+// sends the due deliveries. Several api instances can run a dispatcher on
+// the same database: a delivery is claimed before being sent.
+func Start(ctx context.Context) {
+	client := &http.Client{Timeout: sendTimeout}
+	go enqueueLoop(ctx)
+	go sendLoop(ctx, client)
+}
+
+// Retry resets the attempts of a delivery, so it is sent again as soon as
+// possible.
+func Retry(deliveryID uint) error {
+	err := db.DB().Model(&tables.WebhookDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]interface{}{
+			"status":          StatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+			"error":           "",
+		}).Error
+	if err != nil {
+		return err
+	}
+	notify()
+	return nil
+}
+
+func notify() {
+	select {
+	case wake <- nil:
+	default:
+	}
+}
+
+func enqueueLoop(ctx context.Context) {
+	var (
+		lastID uint64
+		resume bool
+	)
+	for {
+		sub, backlog := events.Subscribe(lastID, resume)
+		for _, e := range backlog {
+			enqueue(e)
+			lastID = e.ID
+		}
+	events:
+		for {
+			select {
+			case <-ctx.Done():
+				events.Unsubscribe(sub)
+				return
+			case e, ok := <-sub.C:
+				if !ok {
+					// too slow, resume from the last event
+					break events
+				}
+				enqueue(e)
+				lastID = e.ID
+			}
+		}
+		resume = true
+	}
+}
+
+// enqueue creates a pending delivery of the event for each webhook
+// subscribed to it.
+func enqueue(e events.Event) {
+	if e.Kind == events.Reset {
+		log.Printf("webhook: events lost before event %d", e.ID)
+		return
+	}
+	hooks, err := tables.GetEnabledWebhooks()
+	if err != nil {
+		log.Printf("webhook: event %d: %s", e.ID, err)
+		return
+	}
+	n := 0
+	for _, hook := range hooks {
+		if ok, err := match(hook, e); err != nil {
+			log.Printf("webhook %d: event %d: %s", hook.ID, e.ID, err)
+			continue
+		} else if !ok {
+			continue
+		}
+		body, err := json.Marshal(Payload{WebhookID: hook.ID, Event: e})
+		if err != nil {
+			log.Printf("webhook %d: event %d: %s", hook.ID, e.ID, err)
+			continue
+		}
+		delivery := tables.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       e.ID,
+			EventKind:     string(e.Kind),
+			Payload:       string(body),
+			Status:        StatusPending,
+			NextAttemptAt: time.Now(),
+		}
+		if err := db.DB().Create(&delivery).Error; err != nil {
+			log.Printf("webhook %d: event %d: %s", hook.ID, e.ID, err)
+			continue
+		}
+		n++
+	}
+	if n > 0 {
+		notify()
+	}
+}
+
+// match returns true if the event matches the webhook subscription and
+// filters. The filters can not match the delete events of the entries
+// removed from the database.
+func match(hook tables.Webhook, e events.Event) (bool, error) {
+	if !NewSubscription(hook.Events, hook.Tables).Match(e) {
+		return false, nil
+	}
+	filters := make([]string, 0)
+	if len(hook.Filters) > 0 {
+		if err := json.Unmarshal(hook.Filters, &filters); err != nil {
+			return false, fmt.Errorf("decode filters: %w", err)
+		}
+	}
+	if len(filters) == 0 {
+		return true, nil
+	}
+	t := db.Tab(e.Table)
+	if t == nil {
+		return false, nil
+	}
+	return t.Matches(e.EntryID, filters)
+}
+
+func sendLoop(ctx context.Context, client *http.Client) {
+	ticker := time.NewTicker(sendInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+		sendDue(ctx, client)
+	}
+}
+
+// sendDue sends the pending deliveries and the failed deliveries whose
+// backoff delay is over.
+func sendDue(ctx context.Context, client *http.Client) {
+	now := time.Now()
+	due := make([]tables.WebhookDelivery, 0)
+	err := db.DB().
+		Where("status IN ? AND next_attempt_at <= ?", dueStatuses, now).
+		Order("next_attempt_at").
+		Limit(sendBatch).
+		Find(&due).Error
+	if err != nil {
+		log.Printf("webhook: select due deliveries: %s", err)
+		return
+	}
+	for _, delivery := range due {
+		if ok, err := claim(delivery, now); err != nil {
+			log.Printf("webhook %d: delivery %d: claim: %s", delivery.WebhookID, delivery.ID, err)
+			continue
+		} else if !ok {
+			// claimed by another dispatcher
+			continue
+		}
+		if err := send(ctx, client, delivery); err != nil {
+			log.Printf("webhook %d: delivery %d: %s", delivery.WebhookID, delivery.ID, err)
+		}
+	}
+}
+
+// claim postpones the next attempt of the delivery, so other dispatchers
+// do not send it too, and returns false if another dispatcher claimed it
+// first.
+func claim(delivery tables.WebhookDelivery, now time.Time) (bool, error) {
+	tx := db.DB().Model(&tables.WebhookDelivery{}).
+		Where("id = ? AND status IN ? AND next_attempt_at <= ?", delivery.ID, dueStatuses, now).
+		Update("next_attempt_at", now.Add(claimLease))
+	return tx.RowsAffected == 1, tx.Error
+}
+
+// send attempts a delivery and records the attempt result.
+func send(ctx context.Context, client *http.Client, delivery tables.WebhookDelivery) error {
+	var (
+		code    int
+		sendErr error
+	)
+	hook := tables.Webhook{}
+	err := db.DB().Where("id = ?", delivery.WebhookID).Take(&hook).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		sendErr = fmt.Errorf("webhook deleted")
+	case err != nil:
+		return err
+	default:
+		code, sendErr = Deliver(ctx, client, hook.URL, hook.Secret, delivery.ID, events.Kind(delivery.EventKind), []byte(delivery.Payload))
+	}
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+	changes := map[string]interface{}{
+		"attempts":        attempts,
+		"last_attempt_at": now,
+		"response_code":   code,
+		"error":           "",
+	}
+	switch {
+	case sendErr == nil:
+		changes["status"] = StatusDelivered
+	case attempts >= MaxAttempts || hook.ID == 0:
+		changes["status"] = StatusDead
+		changes["error"] = sendErr.Error()
+	default:
+		changes["status"] = StatusFailed
+		changes["error"] = sendErr.Error()
+		changes["next_attempt_at"] = now.Add(Backoff(attempts))
+	}
+	return db.DB().Model(&tables.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(changes).Error
+}
@@ -0,0 +1,150 @@
+// Package webhook delivers the table entries change events to the urls
+// subscribed in the webhooks table.
+//
+// Each delivery is a json POST request signed with the webhook secret:
+// the X-Collector-Signature header is "sha256=" followed by the hex
+// encoded HMAC-SHA256 of the request body. A delivery is retried with an
+// exponential backoff until the receiver responds a 2xx status, and is
+// marked dead after MaxAttempts failed attempts.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opensvc/collector-api/events"
+)
+
+const (
+	SignatureHeader = "X-Collector-Signature"
+	EventHeader     = "X-Collector-Event"
+	DeliveryHeader  = "X-Collector-Delivery"
+
+	// MaxAttempts is the number of failed attempts after which a
+	// delivery is dead.
+	MaxAttempts = 8
+
+	backoffBase = 10 * time.Second
+	backoffMax  = time.Hour
+)
+
+type (
+	// Payload is the body of a delivery request.
+	Payload struct {
+		WebhookID uint         `json:"webhook_id"`
+		Event     events.Event `json:"event"`
+	}
+
+	// Subscription selects the events delivered to a webhook.
+	Subscription struct {
+		// Events are the event kinds to deliver. All if empty.
+		Events []string
+
+		// Tables are the tables to deliver the events of. All if empty.
+		Tables []string
+	}
+)
+
+// Sign returns the signature of a delivery body, as set in the
+// SignatureHeader header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if the signature is the signature of the body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Backoff returns the delay before the next attempt of a delivery that
+// failed the attempts number of times.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+	return d
+}
+
+// NewSubscription returns the subscription of a webhook from its comma
+// separated events and tables.
+func NewSubscription(events, tables string) Subscription {
+	return Subscription{
+		Events: splitList(events),
+		Tables: splitList(tables),
+	}
+}
+
+func splitList(s string) []string {
+	l := make([]string, 0)
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+func hasOrEmpty(l []string, s string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns true if the event is to be delivered to the subscriber.
+func (t Subscription) Match(e events.Event) bool {
+	if e.Kind == events.Reset {
+		return false
+	}
+	return hasOrEmpty(t.Events, string(e.Kind)) && hasOrEmpty(t.Tables, e.Table)
+}
+
+// Deliver posts a delivery body to the url, and returns the response
+// status code. The error is set if the request failed or the response
+// status is not 2xx.
+func Deliver(ctx context.Context, client *http.Client, url, secret string, deliveryID uint, kind events.Kind, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "opensvc-collector-api")
+	req.Header.Set(EventHeader, string(kind))
+	req.Header.Set(DeliveryHeader, fmt.Sprint(deliveryID))
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opensvc/collector-api/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"webhook_id":1}`)
+	sig := Sign("s3cr3t", body)
+	assert.Equal(t, "sha256=", sig[:7])
+	assert.Len(t, sig, 7+64)
+	assert.True(t, Verify("s3cr3t", body, sig))
+	assert.False(t, Verify("other", body, sig))
+	assert.False(t, Verify("s3cr3t", []byte(`{"webhook_id":2}`), sig))
+}
+
+func TestBackoff(t *testing.T) {
+	tests := map[string]struct {
+		attempts int
+		delay    time.Duration
+	}{
+		"no attempt": {
+			attempts: 0,
+			delay:    0,
+		},
+		"first failure": {
+			attempts: 1,
+			delay:    10 * time.Second,
+		},
+		"third failure": {
+			attempts: 3,
+			delay:    40 * time.Second,
+		},
+		"capped": {
+			attempts: 20,
+			delay:    time.Hour,
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.delay, Backoff(test.attempts))
+	}
+}
+
+func TestSubscriptionMatch(t *testing.T) {
+	tests := map[string]struct {
+		events, tables string
+		event          events.Event
+		match          bool
+	}{
+		"all": {
+			event: events.Event{Kind: events.Create, Table: "nodes"},
+			match: true,
+		},
+		"kind and table": {
+			events: "create, delete",
+			tables: "nodes,tags",
+			event:  events.Event{Kind: events.Delete, Table: "tags"},
+			match:  true,
+		},
+		"other kind": {
+			events: "create,delete",
+			event:  events.Event{Kind: events.Update, Table: "nodes"},
+		},
+		"other table": {
+			tables: "tags",
+			event:  events.Event{Kind: events.Update, Table: "nodes"},
+		},
+		"reset": {
+			event: events.Event{Kind: events.Reset},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("%s", testName)
+		assert.Equal(t, test.match, NewSubscription(test.events, test.tables).Match(test.event))
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	body := []byte(`{"webhook_id":1,"event":{"id":1,"kind":"create"}}`)
+	status := http.StatusNoContent
+	var received *http.Request
+	var receivedBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	code, err := Deliver(context.Background(), srv.Client(), srv.URL, "s3cr3t", 12, events.Create, body)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "create", received.Header.Get(EventHeader))
+	assert.Equal(t, "12", received.Header.Get(DeliveryHeader))
+	assert.Equal(t, body, receivedBody)
+	assert.True(t, Verify("s3cr3t", receivedBody, received.Header.Get(SignatureHeader)))
+
+	status = http.StatusInternalServerError
+	code, err = Deliver(context.Background(), srv.Client(), srv.URL, "s3cr3t", 12, events.Create, body)
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	srv.Close()
+	code, err = Deliver(context.Background(), srv.Client(), srv.URL, "s3cr3t", 12, events.Create, body)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, code)
+}